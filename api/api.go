@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 	"github.com/ethereum/go-ethereum/log"
 
@@ -18,52 +20,84 @@ import (
 )
 
 const (
-	HealthPath             = "/healthz"
-	RollupWithTypePath     = "/api/v1/rollup-with-type"
-	RetrieveFromDAWithType = "/api/v1/retrieve-with-type"
+	HealthPath               = "/healthz"
+	ReadyPath                = "/readyz"
+	RollupWithTypePath       = "/api/v1/rollup-with-type"
+	RetrieveFromDAWithType   = "/api/v1/retrieve-with-type"
+	StatusWithTypePath       = "/api/v1/status-with-type"
+	ProofWithTypePath        = "/api/v1/proof-with-type"
+	AttestationWithTypePath  = "/api/v1/attestation-with-type"
+	EstimateCostWithTypePath = "/api/v1/estimate-cost-with-type"
+	ContentPath              = "/api/v2/content/{hash}"
+	AdminReloadPath          = "/admin/reload"
+	AdminFaultsPath          = "/admin/faults"
+	AdminFaultPath           = "/admin/faults/{da}"
 )
 
 type API struct {
-	log       log.Logger
-	router    *chi.Mux
-	apiServer *httputil.HTTPServer
-	stopped   atomic.Bool
+	log        log.Logger
+	apiAddress string
+	router     *chi.Mux
+	routes     routes.Routes
+	apiServer  *httputil.HTTPServer
+	stopped    atomic.Bool
 }
 
-func NewApi(ctx context.Context, log log.Logger, apiAddress string, rollup api.RollupInter) error {
+// NewApi builds the API routes, the server starts listening on Start.
+func NewApi(ctx context.Context, log log.Logger, apiAddress string, rollup api.RollupInter) (*API, error) {
 	out := &API{log: log}
 	if err := out.initFromConfig(ctx, apiAddress, rollup); err != nil {
-		return errors.Join(err, out.Stop(ctx))
+		return nil, errors.Join(err, out.Stop(ctx))
 	}
-	return nil
+	return out, nil
 }
 
 func (a *API) initFromConfig(ctx context.Context, apiAddress string, rollup api.RollupInter) error {
-
-	a.initRouter(rollup)
-	if err := a.startServer(apiAddress); err != nil {
-		return fmt.Errorf("failed to start API server: %w", err)
-	}
+	a.apiAddress = apiAddress
+	a.initRouter(ctx, rollup)
 	return nil
 }
 
-func (a *API) initRouter(rollup api.RollupInter) {
+func (a *API) initRouter(ctx context.Context, rollup api.RollupInter) {
 
 	svc := api.New(rollup)
 	apiRouter := chi.NewRouter()
-	h := routes.NewRoutes(a.log, apiRouter, svc)
+	h := routes.NewRoutes(ctx, a.log, apiRouter, svc)
 
-	apiRouter.Use(middleware.Timeout(time.Second * 12))
 	apiRouter.Use(middleware.Recoverer)
-	apiRouter.Use(middleware.Heartbeat(HealthPath))
 
+	// submissions wait for inclusion and fee bumps, they are bound to ctx rather than the request timeout
 	apiRouter.Post(fmt.Sprintf(RollupWithTypePath), h.RollupWithTypePathHandler)
-	apiRouter.Post(fmt.Sprintf(RetrieveFromDAWithType), h.RetrieveWithTypePathHandler)
+
+	apiRouter.Group(func(r chi.Router) {
+		r.Use(middleware.Timeout(time.Second * 12))
+		r.Get(HealthPath, h.HealthzHandler)
+		r.Get(ReadyPath, h.ReadyzHandler)
+		r.Post(fmt.Sprintf(RetrieveFromDAWithType), h.RetrieveWithTypePathHandler)
+		r.Post(StatusWithTypePath, h.StatusWithTypePathHandler)
+		r.Post(ProofWithTypePath, h.ProofWithTypePathHandler)
+		r.Post(AttestationWithTypePath, h.AttestationWithTypePathHandler)
+		r.Post(EstimateCostWithTypePath, h.EstimateCostWithTypePathHandler)
+		r.Get(ContentPath, h.ContentHandler)
+	})
 
 	a.router = apiRouter
+	a.routes = h
+}
+
+// EnableAdmin serves the admin endpoints to requests carrying the bearer token, it must be called before Start.
+func (a *API) EnableAdmin(token string) {
+	admin := a.router.With(middleware.Timeout(time.Second*12), routes.RequireBearerToken(token))
+	admin.Post(AdminReloadPath, a.routes.AdminReloadHandler)
+	admin.Get(AdminFaultsPath, a.routes.AdminFaultsHandler)
+	admin.Put(AdminFaultPath, a.routes.AdminSetFaultHandler)
+	admin.Delete(AdminFaultPath, a.routes.AdminClearFaultHandler)
 }
 
 func (a *API) Start(ctx context.Context) error {
+	if err := a.startServer(a.apiAddress); err != nil {
+		return fmt.Errorf("failed to start API server: %w", err)
+	}
 	return nil
 }
 
@@ -82,7 +116,10 @@ func (a *API) Stop(ctx context.Context) error {
 
 func (a *API) startServer(addr string) error {
 	a.log.Debug("API server listening...", "address", addr)
-	srv, err := httputil.StartHTTPServer(addr, a.router)
+	handler := otelhttp.NewHandler(a.router, "api", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return r.Method + " " + r.URL.Path
+	}))
+	srv, err := httputil.StartHTTPServer(addr, handler)
 	if err != nil {
 		return fmt.Errorf("failed to start API server: %w", err)
 	}
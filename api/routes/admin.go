@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/config"
+)
+
+type ReloadResponse struct {
+	Reloaded []string `json:"reloaded"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// RequireBearerToken ... Rejects requests without the `Authorization: Bearer <token>` header
+func RequireBearerToken(token string) func(http.Handler) http.Handler {
+	want := []byte("Bearer " + token)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(got, want) != 1 {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AdminReloadHandler ... Handles /admin/reload Post requests, re-reads the config file and rebuilds the changed DA backends
+func (h Routes) AdminReloadHandler(w http.ResponseWriter, r *http.Request) {
+	reloaded, err := h.svc.Reload(r.Context())
+	res := ReloadResponse{Reloaded: reloaded}
+	statusCode := http.StatusOK
+	if err != nil {
+		h.logger.Error("Unable to reload config", "err", err.Error())
+		res.Error = err.Error()
+		statusCode = http.StatusInternalServerError
+		var validationErr config.ValidationError
+		if errors.As(err, &validationErr) {
+			statusCode = http.StatusBadRequest
+		}
+	}
+
+	err = jsonResponse(w, res, statusCode)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// AdminFaultsHandler ... Handles /admin/faults Get requests, returns the injected faults by da name
+func (h Routes) AdminFaultsHandler(w http.ResponseWriter, r *http.Request) {
+	err := jsonResponse(w, h.svc.Faults(), http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// AdminSetFaultHandler ... Handles /admin/faults/{da} Put requests, replaces the faults injected in the requests to the DA
+func (h Routes) AdminSetFaultHandler(w http.ResponseWriter, r *http.Request) {
+	daType, err := _common.ParseDAType(chi.URLParam(r, "da"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var conf fault.Config
+	if err := json.NewDecoder(r.Body).Decode(&conf); err != nil {
+		http.Error(w, fmt.Sprintf("invalid faults: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if err := h.svc.SetFault(daType, conf); err != nil {
+		http.Error(w, fmt.Sprintf("invalid faults: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	h.AdminFaultsHandler(w, r)
+}
+
+// AdminClearFaultHandler ... Handles /admin/faults/{da} Delete requests, stops injecting faults in the requests to the DA
+func (h Routes) AdminClearFaultHandler(w http.ResponseWriter, r *http.Request) {
+	daType, err := _common.ParseDAType(chi.URLParam(r, "da"))
+	if err == nil {
+		err = h.svc.ClearFault(daType)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.AdminFaultsHandler(w, r)
+}
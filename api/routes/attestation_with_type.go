@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+)
+
+type AttestationRequest struct {
+	DAType int         `json:"da_type"`
+	Args   interface{} `json:"args"`
+}
+
+// AttestationWithTypePathHandler ... Handles /api/v1/attestation-with-type Post requests
+func (h Routes) AttestationWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeAttestationRequest")
+	decoder := json.NewDecoder(r.Body)
+	var req AttestationRequest
+	err := decoder.Decode(&req)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid attestation request: %s", err.Error()), http.StatusBadRequest)
+		h.logger.Error("failed to decode attestation request", "err", err)
+		return
+	}
+
+	res, err := h.svc.AttestationWithTypeContext(r.Context(), req.DAType, req.Args)
+	if errors.Is(err, _errors.AttestationNotSupportedErr) || errors.Is(err, celestia.ErrBlobstreamDisabled) ||
+		errors.Is(err, celestia.ErrNoCommitment) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, celestia.ErrNotAttested) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal server error attestation with type, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to get attestation with type", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, res, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/content"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+)
+
+// ContentHandler ... Handles /api/v2/content/{hash} Get requests
+func (h Routes) ContentHandler(w http.ResponseWriter, r *http.Request) {
+	// the bearer token lets the caller read the plaintext of encrypted payloads
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	res, err := h.svc.RetrieveByHashContext(_common.WithAuthToken(r.Context(), token), chi.URLParam(r, "hash"))
+	switch {
+	case errors.Is(err, content.ErrInvalidHash):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	case errors.Is(err, content.ErrNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, encryption.ErrUnauthorized), errors.Is(err, encryption.ErrUnknownKey):
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("Internal server error retrieve content, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to retrieve content", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, res, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
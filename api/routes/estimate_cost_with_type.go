@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+type EstimateCostRequest struct {
+	DAType int `json:"da_type"`
+	Size   int `json:"size"`
+}
+
+// EstimateCostWithTypePathHandler ... Handles /api/v1/estimate-cost-with-type Post requests
+func (h Routes) EstimateCostWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeEstimateCostRequest")
+	decoder := json.NewDecoder(r.Body)
+	var req EstimateCostRequest
+	err := decoder.Decode(&req)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid estimate cost request: %s", err.Error()), http.StatusBadRequest)
+		h.logger.Error("failed to decode estimate cost request", "err", err)
+		return
+	}
+
+	res, err := h.svc.EstimateCostWithTypeContext(r.Context(), req.DAType, req.Size)
+	if errors.Is(err, _errors.CostNotSupportedErr) || errors.Is(err, pricing.ErrNoRate) ||
+		errors.Is(err, _errors.UnknownDATypeErr) || errors.Is(err, _errors.WrongArgTypeErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal server error estimate cost with type, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to estimate cost with type", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, res, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
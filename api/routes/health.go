@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HealthzHandler ... Handles /healthz liveness requests, the node is alive as long as it serves
+// requests. It never probes the DA backends, a slow one must not get the node restarted.
+func (h Routes) HealthzHandler(w http.ResponseWriter, r *http.Request) {
+	err := jsonResponse(w, map[string]string{"status": "ok"}, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
+
+// ReadyzHandler ... Handles /readyz readiness requests, responds 503 unless every prepared DA backend is healthy
+func (h Routes) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	report, err := h.svc.HealthCheck(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal server error health check, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to check health", "err", err.Error())
+		return
+	}
+	statusCode := http.StatusOK
+	if !report.Ready {
+		statusCode = http.StatusServiceUnavailable
+	}
+	err = jsonResponse(w, report, statusCode)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
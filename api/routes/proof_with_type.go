@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+type ProofRequest struct {
+	DAType int         `json:"da_type"`
+	Args   interface{} `json:"args"`
+}
+
+// ProofWithTypePathHandler ... Handles /api/v1/proof-with-type Post requests
+func (h Routes) ProofWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeProofRequest")
+	decoder := json.NewDecoder(r.Body)
+	var req ProofRequest
+	err := decoder.Decode(&req)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid proof request: %s", err.Error()), http.StatusBadRequest)
+		h.logger.Error("failed to decode proof request", "err", err)
+		return
+	}
+
+	res, err := h.svc.ProofWithTypeContext(r.Context(), req.DAType, req.Args)
+	if errors.Is(err, _errors.ProofNotSupportedErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal server error proof with type, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to get proof with type", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, res, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
@@ -2,8 +2,14 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 type RetrieveRequest struct {
@@ -13,15 +19,23 @@ type RetrieveRequest struct {
 
 // RetrieveWithTypePathHandler ... Handles /api/v1/retrieve-with-type Post requests
 func (h Routes) RetrieveWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeRetrieveRequest")
 	decoder := json.NewDecoder(r.Body)
 	var req RetrieveRequest
 	err := decoder.Decode(&req)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		h.logger.Error("failed to decode retrieve request", "err", err)
 		return
 	}
 
-	res, err := h.svc.RetrieveFromDAWithType(req.DAType, req.Args)
+	// the bearer token lets the caller read the plaintext of encrypted payloads
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	res, err := h.svc.RetrieveFromDAWithTypeContext(_common.WithAuthToken(r.Context(), token), req.DAType, req.Args)
+	if errors.Is(err, encryption.ErrUnauthorized) || errors.Is(err, encryption.ErrUnknownKey) {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Internal server error retrieve with type, err msg: %s", err.Error()), http.StatusInternalServerError)
 		h.logger.Error("Unable to retrieve with type", "err", err.Error())
@@ -3,28 +3,54 @@ package routes
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 type RollupRequest struct {
 	DAType int    `json:"da_type"`
 	Data   string `json:"data"`
+	// Namespace is the tenant or namespace to store the data under, only used by celestia
+	Namespace string `json:"namespace,omitempty"`
+	// Codec overrides the codec configured for the DA, see common.WithCodec
+	Codec string `json:"codec,omitempty"`
+	// KeyID is the keyring key to encrypt the data with, see common.WithKeyID
+	KeyID string `json:"key_id,omitempty"`
 }
 
 // RollupWithTypePathHandler ... Handles /api/v1/rollup-with-type Post requests
 func (h Routes) RollupWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeRollupRequest")
 	decoder := json.NewDecoder(r.Body)
 	var req RollupRequest
 	err := decoder.Decode(&req)
 	dataB, err := base64.StdEncoding.DecodeString(req.Data)
+	span.SetAttributes(attribute.Int("data.size", len(dataB)))
+	tracing.EndSpan(span, err)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to decode request date, want base64. Err msg: %s", err.Error()), http.StatusBadRequest)
 		h.logger.Error("failed to decode rollup request", "err", err)
 		return
 	}
 
-	res, err := h.svc.RollupWithType(dataB, req.DAType)
+	// only the trace is taken from the request, a client giving up must not cancel a submission that may
+	// still land on the DA
+	ctx := trace.ContextWithSpan(h.ctx, trace.SpanFromContext(r.Context()))
+	ctx = _common.WithKeyID(_common.WithCodec(_common.WithNamespace(ctx, req.Namespace), req.Codec), req.KeyID)
+	res, err := h.svc.RollupWithTypeContext(ctx, dataB, req.DAType)
+	if errors.Is(err, codec.ErrUnknownCodec) || errors.Is(err, encryption.ErrUnknownKey) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Internal server error rollup with type, err msg: %s", err.Error()), http.StatusInternalServerError)
 		h.logger.Error("Unable to rollup with type", "err", err.Error())
@@ -1,20 +1,28 @@
 package routes
 
 import (
+	"context"
+
 	"github.com/eniac-x-labs/rollup-node/api/service"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/go-chi/chi/v5"
 )
 
+var tracer = tracing.Tracer("api")
+
 type Routes struct {
+	// ctx bounds the DA submissions, which outlive the request timeout
+	ctx    context.Context
 	logger log.Logger
 	router *chi.Mux
 	svc    service.HandlerSvc
 }
 
 // NewRoutes ... Construct a new route handler instance
-func NewRoutes(l log.Logger, r *chi.Mux, svc service.HandlerSvc) Routes {
+func NewRoutes(ctx context.Context, l log.Logger, r *chi.Mux, svc service.HandlerSvc) Routes {
 	return Routes{
+		ctx:    ctx,
 		logger: l,
 		router: r,
 		svc:    svc,
@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+type StatusRequest struct {
+	DAType int         `json:"da_type"`
+	Args   interface{} `json:"args"`
+}
+
+// StatusWithTypePathHandler ... Handles /api/v1/status-with-type Post requests
+func (h Routes) StatusWithTypePathHandler(w http.ResponseWriter, r *http.Request) {
+	_, span := tracer.Start(r.Context(), "api.DecodeStatusRequest")
+	decoder := json.NewDecoder(r.Body)
+	var req StatusRequest
+	err := decoder.Decode(&req)
+	tracing.EndSpan(span, err)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid status request: %s", err.Error()), http.StatusBadRequest)
+		h.logger.Error("failed to decode status request", "err", err)
+		return
+	}
+
+	res, err := h.svc.StatusWithTypeContext(r.Context(), req.DAType, req.Args)
+	if errors.Is(err, _errors.StatusNotTrackedErr) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Internal server error status with type, err msg: %s", err.Error()), http.StatusInternalServerError)
+		h.logger.Error("Unable to get status with type", "err", err.Error())
+		return
+	}
+
+	err = jsonResponse(w, res, http.StatusOK)
+	if err != nil {
+		h.logger.Error("Error writing response", "err", err.Error())
+	}
+}
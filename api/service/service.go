@@ -1,8 +1,28 @@
 package service
 
+import (
+	"context"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+)
+
 type RollupInter interface {
 	RollupWithType(data []byte, daType int) ([]interface{}, error)
 	RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error)
+	RollupWithTypeContext(ctx context.Context, data []byte, daType int) ([]interface{}, error)
+	RetrieveFromDAWithTypeContext(ctx context.Context, daType int, args interface{}) ([]byte, error)
+	StatusWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error)
+	ProofWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error)
+	AttestationWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error)
+	EstimateCostWithTypeContext(ctx context.Context, daType int, size int) (*_common.CostEstimate, error)
+	RetrieveByHashContext(ctx context.Context, hash string) (*_common.Content, error)
+	HealthCheck(ctx context.Context) (*health.Report, error)
+	Reload(ctx context.Context) ([]string, error)
+	Faults() map[string]fault.Config
+	SetFault(daType int, conf fault.Config) error
+	ClearFault(daType int) error
 }
 
 type HandlerSvc struct {
@@ -20,8 +20,11 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/eniac-x-labs/rollup-node/retry"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 const (
@@ -31,6 +34,8 @@ const (
 	defaultWaitTransaction = 5 * time.Minute
 )
 
+var tracer = tracing.Tracer("client")
+
 type EthClient interface {
 	SendTransaction(ctx context.Context, tx *types.Transaction) error
 	GetBalanceByBlockNumber(address string, blockNumber *big.Int) (*big.Int, error)
@@ -41,6 +46,9 @@ type EthClient interface {
 	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
 	TxByHash(common.Hash) (*types.Transaction, error)
 	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error)
 	Close()
 }
 
@@ -211,6 +219,17 @@ func (c *clnt) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumb
 	return hex, nil
 }
 
+// FilterLogs executes a filter query.
+func (c *clnt) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var result []types.Log
+	arg, err := toFilterArg(q)
+	if err != nil {
+		return nil, err
+	}
+	err = c.rpc.CallContext(ctx, &result, "eth_getLogs", arg)
+	return result, err
+}
+
 func (c *clnt) Close() {
 	c.rpc.Close()
 }
@@ -234,12 +253,18 @@ func (c *rpcClient) Close() {
 }
 
 func (c *rpcClient) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	ctx, span := tracer.Start(ctx, method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("rpc.method", method)))
 	err := c.rpc.CallContext(ctx, result, method, args...)
+	tracing.EndSpan(span, err)
 	return err
 }
 
 func (c *rpcClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	ctx, span := tracer.Start(ctx, "batch", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.Int("rpc.batch_size", len(b))))
 	err := c.rpc.BatchCallContext(ctx, b)
+	tracing.EndSpan(span, err)
 	return err
 }
 
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/receipt"
+	"github.com/eniac-x-labs/rollup-node/flags"
+	_rpc "github.com/eniac-x-labs/rollup-node/rpc"
+	"github.com/eniac-x-labs/rollup-node/sdk"
+)
+
+const (
+	rpcFlagName       = "rpc"
+	timeoutFlagName   = "timeout"
+	daFlagName        = "da"
+	fileFlagName      = "file"
+	receiptFlagName   = "receipt"
+	outFlagName       = "out"
+	namespaceFlagName = "namespace"
+	codecFlagName     = "codec"
+	keyIDFlagName     = "key-id"
+	tokenFlagName     = "token"
+)
+
+var (
+	rpcFlag = &cli.StringFlag{
+		Name:    rpcFlagName,
+		Usage:   "Rpc address of the running node",
+		Value:   "localhost:9000",
+		EnvVars: flags.PrefixEnvVar(flags.EnvVarPrefix, "RPC_ADDRESS"),
+	}
+	timeoutFlag = &cli.DurationFlag{
+		Name:  timeoutFlagName,
+		Usage: "Give up waiting for the node after this duration",
+		Value: 5 * time.Minute,
+	}
+	daFlag = &cli.StringFlag{
+		Name:     daFlagName,
+		Usage:    "DA name (anytrust, celestia, eigenda, eip4844, nearda, anytrust-das-committee, erasure) or da_type",
+		Required: true,
+	}
+)
+
+var clientCommands = []*cli.Command{
+	{
+		Name:  "submit",
+		Usage: "Roll up data to a DA through a running node and print the receipts",
+		Flags: []cli.Flag{rpcFlag, timeoutFlag, daFlag,
+			&cli.StringFlag{Name: fileFlagName, Usage: "File to submit, - reads stdin", Required: true},
+			&cli.StringFlag{Name: namespaceFlagName, Usage: "Celestia tenant or hex namespace to store the data under"},
+			&cli.StringFlag{Name: codecFlagName, Usage: "Codec (none, zstd, brotli, zlib) overriding the one configured for the DA"},
+			&cli.StringFlag{Name: keyIDFlagName, Usage: "Keyring key to encrypt the data with, overriding the node's default key"},
+		},
+		Action: submit,
+	},
+	{
+		Name:  "retrieve",
+		Usage: "Retrieve data from a DA through a running node",
+		Flags: []cli.Flag{rpcFlag, timeoutFlag, daFlag,
+			&cli.StringFlag{Name: receiptFlagName, Usage: "Receipt returned by submit, the data hash for anytrust", Required: true},
+			&cli.StringFlag{Name: outFlagName, Usage: "Write the data to this file instead of stdout"},
+			&cli.StringFlag{
+				Name:    tokenFlagName,
+				Usage:   "Reader token of the keyring key the data was encrypted with",
+				EnvVars: flags.PrefixEnvVar(flags.EnvVarPrefix, "TOKEN"),
+			},
+		},
+		Action: retrieve,
+	},
+	{
+		Name:      "content",
+		Usage:     "Retrieve data by its sha256 or keccak256 from any DA the node stored it on",
+		ArgsUsage: "<hash>",
+		Flags: []cli.Flag{rpcFlag, timeoutFlag,
+			&cli.StringFlag{Name: outFlagName, Usage: "Write the data to this file instead of stdout"},
+			&cli.StringFlag{
+				Name:    tokenFlagName,
+				Usage:   "Reader token of the keyring key the data was encrypted with",
+				EnvVars: flags.PrefixEnvVar(flags.EnvVarPrefix, "TOKEN"),
+			},
+		},
+		Action: retrieveContent,
+	},
+	{
+		Name:      "status",
+		Usage:     "Print the status of a submission, or the health of the node without argument",
+		ArgsUsage: "[receipt]",
+		Flags:     []cli.Flag{rpcFlag, timeoutFlag, &cli.StringFlag{Name: daFlagName, Usage: daFlag.Usage}},
+		Action:    status,
+	},
+	{
+		Name:      "proof",
+		Usage:     "Print the inclusion proof of a celestia receipt, checked by the DA node",
+		ArgsUsage: "<receipt>",
+		Flags:     []cli.Flag{rpcFlag, timeoutFlag, daFlag},
+		Action:    proof,
+	},
+	{
+		Name:      "attestation",
+		Usage:     "Print the calldata proving a celestia receipt was attested on L1 by blobstream",
+		ArgsUsage: "<receipt>",
+		Flags:     []cli.Flag{rpcFlag, timeoutFlag, daFlag},
+		Action:    attestation,
+	},
+	{
+		Name:      "estimate",
+		Usage:     "Print what submitting size bytes to a DA costs at current prices",
+		ArgsUsage: "<size>",
+		Flags:     []cli.Flag{rpcFlag, timeoutFlag, daFlag},
+		Action:    estimate,
+	},
+	{
+		Name:      "inspect",
+		Usage:     "Decode a receipt into human-readable form, without contacting the node",
+		ArgsUsage: "<receipt>",
+		Flags:     []cli.Flag{daFlag},
+		Action:    inspect,
+	},
+}
+
+// dial connects to the node, the returned func closes the connection and cancels ctx.
+func dial(cliCtx *cli.Context) (_rpc.RollupInter, context.Context, func(), error) {
+	client, err := sdk.NewRollupSdk(cliCtx.String(rpcFlagName))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	ctx, cancel := context.WithTimeout(cliCtx.Context, cliCtx.Duration(timeoutFlagName))
+	return client, ctx, func() {
+		cancel()
+		if closer, ok := client.(io.Closer); ok {
+			_ = closer.Close()
+		}
+	}, nil
+}
+
+func submit(cliCtx *cli.Context) error {
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+	var data []byte
+	if path := cliCtx.String(fileFlagName); path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	ctx = _common.WithCodec(_common.WithNamespace(ctx, cliCtx.String(namespaceFlagName)), cliCtx.String(codecFlagName))
+	ctx = _common.WithKeyID(ctx, cliCtx.String(keyIDFlagName))
+	receipts, err := client.RollupWithTypeContext(ctx, data, daType)
+	if err != nil {
+		return err
+	}
+	return printJSON(receipts)
+}
+
+func retrieve(cliCtx *cli.Context) error {
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+	var args interface{} = cliCtx.String(receiptFlagName)
+	// a bare celestia height is sent as a number, a full receipt as is
+	if daType == _common.CelestiaType && !strings.Contains(cliCtx.String(receiptFlagName), ":") {
+		if args, err = strconv.ParseUint(cliCtx.String(receiptFlagName), 10, 64); err != nil {
+			return fmt.Errorf("celestia receipt must be <height>:<namespace> or a height: %w", err)
+		}
+	}
+
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	data, err := client.RetrieveFromDAWithTypeContext(_common.WithAuthToken(ctx, cliCtx.String(tokenFlagName)), daType, args)
+	if err != nil {
+		return err
+	}
+	if path := cliCtx.String(outFlagName); len(path) != 0 {
+		return os.WriteFile(path, data, 0o644)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+func retrieveContent(cliCtx *cli.Context) error {
+	if !cliCtx.Args().Present() {
+		return errors.New("missing hash")
+	}
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	res, err := client.RetrieveByHashContext(_common.WithAuthToken(ctx, cliCtx.String(tokenFlagName)), cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "retrieved from %s\n", _common.DATypeName(res.DAType))
+	if path := cliCtx.String(outFlagName); len(path) != 0 {
+		return os.WriteFile(path, res.Data, 0o644)
+	}
+	_, err = os.Stdout.Write(res.Data)
+	return err
+}
+
+func status(cliCtx *cli.Context) error {
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	if !cliCtx.Args().Present() {
+		report, err := client.HealthCheck(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(report)
+	}
+
+	if !cliCtx.IsSet(daFlagName) {
+		return errors.New("--da is required to get the status of a submission")
+	}
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+	res, err := client.StatusWithTypeContext(ctx, daType, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func proof(cliCtx *cli.Context) error {
+	if !cliCtx.Args().Present() {
+		return errors.New("missing receipt")
+	}
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	res, err := client.ProofWithTypeContext(ctx, daType, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func attestation(cliCtx *cli.Context) error {
+	if !cliCtx.Args().Present() {
+		return errors.New("missing receipt")
+	}
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	res, err := client.AttestationWithTypeContext(ctx, daType, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func estimate(cliCtx *cli.Context) error {
+	if !cliCtx.Args().Present() {
+		return errors.New("missing size")
+	}
+	size, err := strconv.Atoi(cliCtx.Args().First())
+	if err != nil {
+		return fmt.Errorf("invalid size: %w", err)
+	}
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+
+	client, ctx, done, err := dial(cliCtx)
+	if err != nil {
+		return err
+	}
+	defer done()
+	res, err := client.EstimateCostWithTypeContext(ctx, daType, size)
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func inspect(cliCtx *cli.Context) error {
+	if !cliCtx.Args().Present() {
+		return errors.New("missing receipt")
+	}
+	daType, err := _common.ParseDAType(cliCtx.String(daFlagName))
+	if err != nil {
+		return err
+	}
+	res, err := receipt.Inspect(daType, cliCtx.Args().First())
+	if err != nil {
+		return err
+	}
+	return printJSON(res)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/flags"
+)
+
+const forceFlagName = "force"
+
+var configCommand = &cli.Command{
+	Name:  "config",
+	Usage: "Inspect and create the config file",
+	Subcommands: []*cli.Command{
+		{
+			Name:   "validate",
+			Usage:  "Validate the config file with env overrides applied",
+			Flags:  _config.CLIFlags(flags.EnvVarPrefix),
+			Action: validateConfig,
+		},
+		{
+			Name:   "print",
+			Usage:  "Print the effective config with env overrides applied and secrets redacted",
+			Flags:  _config.CLIFlags(flags.EnvVarPrefix),
+			Action: printConfig,
+		},
+		{
+			Name:      "init",
+			Usage:     "Write a documented config file",
+			ArgsUsage: "[path]",
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  forceFlagName,
+					Usage: "Overwrite an existing file",
+				},
+			},
+			Action: initConfig,
+		},
+	},
+}
+
+func validateConfig(cliCtx *cli.Context) error {
+	path := cliCtx.String(_config.ConfigFlagName)
+	conf, err := loadConfig(cliCtx)
+	if err != nil {
+		return err
+	}
+
+	var validationErr _config.ValidationError
+	if err := conf.Validate(); errors.As(err, &validationErr) {
+		for _, fieldErr := range validationErr {
+			fmt.Fprintln(os.Stderr, fieldErr)
+		}
+		return cli.Exit(fmt.Sprintf("%s: %d invalid fields", path, len(validationErr)), 1)
+	} else if err != nil {
+		return err
+	}
+	fmt.Printf("%s: ok\n", path)
+	return nil
+}
+
+func printConfig(cliCtx *cli.Context) error {
+	conf, err := loadConfig(cliCtx)
+	if err != nil {
+		return err
+	}
+	return conf.WriteTOML(os.Stdout)
+}
+
+func loadConfig(cliCtx *cli.Context) (*_config.Config, error) {
+	if cliCtx.Bool(_config.DevnetFlagName) {
+		return _config.LoadDevnet(cliCtx.String(_config.ConfigFlagName))
+	}
+	return _config.Load(cliCtx.String(_config.ConfigFlagName))
+}
+
+func initConfig(cliCtx *cli.Context) error {
+	path := _config.DefaultConfigFile
+	if cliCtx.Args().Present() {
+		path = cliCtx.Args().First()
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if cliCtx.Bool(forceFlagName) {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flag, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use --%s to overwrite it", path, forceFlagName)
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(_config.Template); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s\n", path)
+	return nil
+}
@@ -30,7 +30,9 @@ func main() {
 			Description: "Runs the rollup node service",
 			Action:      cliapp.LifecycleCmd(core.RunRollupModuleForCLI),
 		},
+		configCommand,
 	}
+	app.Commands = append(app.Commands, clientCommands...)
 
 	ctx := context.Background()
 	err := app.RunContext(ctx, os.Args)
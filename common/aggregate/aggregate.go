@@ -0,0 +1,209 @@
+package aggregate
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/celestiaorg/go-square/merkle"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// Version is the version of the packed blob layout and of the receipts.
+const Version = 1
+
+// Magic is the first byte of a packed blob. Its high nibble is no codec id and differs from the
+// encryption envelope, so a packed blob is never mistaken for a single payload.
+const Magic = 0xa0 | Version
+
+// ReceiptPrefix starts the receipt of an aggregated submission.
+const ReceiptPrefix = "agg1:"
+
+const headerSize = 1 + 32 + 4
+
+var (
+	ErrInvalidBlob  = errors.New("invalid aggregate blob")
+	ErrInvalidProof = errors.New("aggregate inclusion proof does not match the blob")
+)
+
+// Config of the aggregation of small submissions into a single DA blob.
+type Config struct {
+	// DAs lists the names or da types whose small submissions are aggregated, none when empty
+	DAs []string `mapstructure:"das"`
+	// Window is how long the first buffered submission waits for others
+	Window time.Duration `mapstructure:"window"`
+	// MaxSize is the size of a packed blob above which the buffer is flushed
+	MaxSize int `mapstructure:"max_size"`
+	// MaxPayloadSize is the size above which a submission goes to the DA on its own
+	MaxPayloadSize int `mapstructure:"max_payload_size"`
+	// FlushTimeout bounds the storing of a packed blob, the one of a shutdown included
+	FlushTimeout time.Duration `mapstructure:"flush_timeout"`
+}
+
+// DefaultConfig fills one 4844 blob at most.
+func DefaultConfig() Config {
+	return Config{
+		Window:         2 * time.Second,
+		MaxSize:        130000,
+		MaxPayloadSize: 8192,
+		FlushTimeout:   time.Minute,
+	}
+}
+
+func (c Config) Check() error {
+	if _, err := c.DATypes(); err != nil {
+		return err
+	}
+	if len(c.DAs) == 0 {
+		return nil
+	}
+	if c.Window <= 0 {
+		return errors.New("window must be positive")
+	}
+	if c.FlushTimeout <= 0 {
+		return errors.New("flush_timeout must be positive")
+	}
+	if c.MaxPayloadSize <= 0 {
+		return errors.New("max_payload_size must be positive")
+	}
+	if c.MaxSize < Overhead(1)+c.MaxPayloadSize {
+		return fmt.Errorf("max_size must fit a payload of max_payload_size, at least %d", Overhead(1)+c.MaxPayloadSize)
+	}
+	return nil
+}
+
+// DATypes returns the aggregated da types.
+func (c Config) DATypes() (map[int]bool, error) {
+	daTypes := make(map[int]bool, len(c.DAs))
+	for _, name := range c.DAs {
+		daType, err := _common.ParseDAType(name)
+		if err != nil {
+			return nil, err
+		}
+		daTypes[daType] = true
+	}
+	return daTypes, nil
+}
+
+// Overhead is the size a packed blob of count payloads adds to them.
+func Overhead(count int) int {
+	return headerSize + 4*count
+}
+
+// Receipt locates a payload in a packed blob and proves it belongs to the blob.
+type Receipt struct {
+	// DAArgs retrieves the packed blob from the DA, as the DA's own receipt would
+	DAArgs interface{} `json:"da_args"`
+	Index  int         `json:"index"`
+	Offset int         `json:"offset"`
+	Size   int         `json:"size"`
+	// Root is the merkle root of the payloads, stored in the blob header
+	Root  []byte        `json:"root"`
+	Proof *merkle.Proof `json:"proof"`
+}
+
+// String encodes the receipt as ReceiptPrefix followed by its base64 json.
+func (r *Receipt) String() string {
+	raw, _ := json.Marshal(r)
+	return ReceiptPrefix + base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsReceipt reports whether args is the receipt of an aggregated submission.
+func IsReceipt(args interface{}) bool {
+	s, ok := args.(string)
+	return ok && strings.HasPrefix(s, ReceiptPrefix)
+}
+
+// ParseReceipt decodes a receipt written by Receipt.String.
+func ParseReceipt(s string) (*Receipt, error) {
+	encoded, ok := strings.CutPrefix(s, ReceiptPrefix)
+	if !ok {
+		return nil, fmt.Errorf("aggregate receipt must start with %s", ReceiptPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode aggregate receipt: %w", err)
+	}
+	r := &Receipt{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// keep the da args a number when it is one, e.g. a celestia height
+	decoder.UseNumber()
+	if err := decoder.Decode(r); err != nil {
+		return nil, fmt.Errorf("decode aggregate receipt: %w", err)
+	}
+	if n, ok := r.DAArgs.(json.Number); ok {
+		height, err := n.Int64()
+		if err != nil || height < 0 {
+			return nil, fmt.Errorf("aggregate receipt da args %s is no height", n)
+		}
+		r.DAArgs = uint64(height)
+	}
+	if r.Proof == nil || len(r.Root) != 32 {
+		return nil, errors.New("aggregate receipt lacks its inclusion proof")
+	}
+	return r, nil
+}
+
+// Pack lays payloads out in a single blob: the magic byte, the merkle root of the payloads, their
+// count and sizes, then the payloads back to back. It returns the blob and a receipt per payload
+// without DAArgs.
+func Pack(payloads [][]byte) ([]byte, []*Receipt) {
+	root, proofs := merkle.ProofsFromByteSlices(payloads)
+	size := Overhead(len(payloads))
+	for _, payload := range payloads {
+		size += len(payload)
+	}
+
+	blob := make([]byte, 0, size)
+	blob = append(blob, Magic)
+	blob = append(blob, root...)
+	blob = binary.BigEndian.AppendUint32(blob, uint32(len(payloads)))
+	for _, payload := range payloads {
+		blob = binary.BigEndian.AppendUint32(blob, uint32(len(payload)))
+	}
+	receipts := make([]*Receipt, len(payloads))
+	for i, payload := range payloads {
+		receipts[i] = &Receipt{Index: i, Offset: len(blob), Size: len(payload), Root: root, Proof: proofs[i]}
+		blob = append(blob, payload...)
+	}
+	return blob, receipts
+}
+
+// Extract returns the payload the receipt points at, after checking its inclusion proof against
+// the root in the blob header.
+func Extract(blob []byte, r *Receipt) ([]byte, error) {
+	if len(blob) < headerSize || blob[0] != Magic {
+		return nil, ErrInvalidBlob
+	}
+	root := blob[1:33]
+	count := int(binary.BigEndian.Uint32(blob[33:headerSize]))
+	if count == 0 || len(blob) < Overhead(count) {
+		return nil, ErrInvalidBlob
+	}
+	if !bytes.Equal(root, r.Root) {
+		return nil, fmt.Errorf("%w: blob root %x, receipt root %x", ErrInvalidProof, root, r.Root)
+	}
+	if r.Index < 0 || r.Index >= count || r.Proof.Index != int64(r.Index) || r.Proof.Total != int64(count) {
+		return nil, fmt.Errorf("%w: index %d of %d payloads", ErrInvalidProof, r.Index, count)
+	}
+	// the offset follows from the sizes in the header and must match the receipt
+	offset := Overhead(count)
+	for i := 0; i < r.Index; i++ {
+		offset += int(binary.BigEndian.Uint32(blob[headerSize+4*i:]))
+	}
+	size := int(binary.BigEndian.Uint32(blob[headerSize+4*r.Index:]))
+	if offset != r.Offset || size != r.Size || offset+size > len(blob) {
+		return nil, fmt.Errorf("%w: payload %d at %d+%d", ErrInvalidBlob, r.Index, offset, size)
+	}
+	payload := blob[offset : offset+size]
+	if err := r.Proof.Verify(root, payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,121 @@
+package aggregate
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testPayloads() [][]byte {
+	payloads := make([][]byte, 5)
+	for i := range payloads {
+		payloads[i] = bytes.Repeat([]byte(fmt.Sprintf("appchain %d batch ", i)), i+1)
+	}
+	return payloads
+}
+
+func TestPackExtract(t *testing.T) {
+	payloads := testPayloads()
+	blob, receipts := Pack(payloads)
+	require.Equal(t, byte(Magic), blob[0])
+	require.Len(t, receipts, len(payloads))
+
+	size := Overhead(len(payloads))
+	for _, payload := range payloads {
+		size += len(payload)
+	}
+	require.Len(t, blob, size)
+
+	for i, receipt := range receipts {
+		require.Equal(t, i, receipt.Index)
+		payload, err := Extract(blob, receipt)
+		require.NoError(t, err)
+		require.Equal(t, payloads[i], payload)
+	}
+
+	// a single payload
+	blob, receipts = Pack(payloads[:1])
+	payload, err := Extract(blob, receipts[0])
+	require.NoError(t, err)
+	require.Equal(t, payloads[0], payload)
+}
+
+func TestExtractRejectsTampering(t *testing.T) {
+	blob, receipts := Pack(testPayloads())
+
+	tampered := append([]byte{}, blob...)
+	tampered[receipts[2].Offset] ^= 1
+	_, err := Extract(tampered, receipts[2])
+	require.ErrorIs(t, err, ErrInvalidProof)
+	// the other payloads are untouched
+	_, err = Extract(tampered, receipts[1])
+	require.NoError(t, err)
+
+	// a receipt of another blob
+	other, _ := Pack([][]byte{[]byte("other batch")})
+	_, err = Extract(other, receipts[0])
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	// pointing the proof of a payload at another one
+	moved := *receipts[1]
+	moved.Index, moved.Offset, moved.Size = 2, receipts[2].Offset, receipts[2].Size
+	_, err = Extract(blob, &moved)
+	require.ErrorIs(t, err, ErrInvalidProof)
+
+	moved = *receipts[1]
+	moved.Offset++
+	_, err = Extract(blob, &moved)
+	require.ErrorIs(t, err, ErrInvalidBlob)
+
+	for _, invalid := range [][]byte{nil, []byte("plain rollup batch"), blob[:Overhead(1)], blob[:len(blob)-1]} {
+		_, err = Extract(invalid, receipts[4])
+		require.Error(t, err)
+	}
+}
+
+func TestReceiptRoundTrip(t *testing.T) {
+	_, receipts := Pack(testPayloads())
+	for _, daArgs := range []interface{}{uint64(1 << 60), "0x0a0b", map[string]interface{}{"height": "12"}} {
+		receipts[3].DAArgs = daArgs
+		s := receipts[3].String()
+		require.True(t, IsReceipt(s))
+
+		parsed, err := ParseReceipt(s)
+		require.NoError(t, err)
+		require.Equal(t, receipts[3], parsed)
+	}
+
+	require.False(t, IsReceipt("0x0a0b"))
+	require.False(t, IsReceipt(uint64(12)))
+	for _, invalid := range []string{"0x0a0b", ReceiptPrefix + "!", ReceiptPrefix + "e30"} {
+		_, err := ParseReceipt(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestConfigCheck(t *testing.T) {
+	conf := DefaultConfig()
+	require.NoError(t, conf.Check())
+
+	conf.DAs = []string{"celestia", "3"}
+	require.NoError(t, conf.Check())
+	daTypes, err := conf.DATypes()
+	require.NoError(t, err)
+	require.Equal(t, map[int]bool{1: true, 3: true}, daTypes)
+
+	for _, invalid := range []func(c *Config){
+		func(c *Config) { c.DAs = []string{"avail"} },
+		func(c *Config) { c.Window = 0 },
+		func(c *Config) { c.FlushTimeout = 0 },
+		func(c *Config) { c.MaxPayloadSize = 0 },
+		func(c *Config) { c.MaxSize = c.MaxPayloadSize },
+	} {
+		c := conf
+		c.Window = time.Second
+		invalid(&c)
+		require.Error(t, c.Check())
+	}
+}
@@ -0,0 +1,395 @@
+package cache
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// Result tells which tier served a Get.
+type Result string
+
+const (
+	Memory Result = "memory"
+	Disk   Result = "disk"
+	Miss   Result = "miss"
+)
+
+// DefaultTTLs follow how long each DA serves data. They count from the time the DA stored the data
+// when it tells it, eip4844 blobs expiring with the retention of their block, so the cache doesn't
+// outlive the DA's own copy. Otherwise they count from the retrieval and bound how long the cache
+// keeps serving data the DA may have dropped since.
+var DefaultTTLs = _common.DARetention
+
+// Config of the read-through cache of retrieved DA data.
+type Config struct {
+	// MemoryBytes bounds the in-memory tier, it is disabled when 0
+	MemoryBytes int64 `mapstructure:"memory_bytes"`
+	// Dir holds the on-disk tier, it is disabled when empty
+	Dir string `mapstructure:"dir"`
+	// DiskBytes bounds the on-disk tier
+	DiskBytes int64 `mapstructure:"disk_bytes"`
+	// TTLs overrides DefaultTTLs by da name
+	TTLs map[string]time.Duration `mapstructure:"ttls"`
+}
+
+func DefaultConfig() Config {
+	return Config{MemoryBytes: 64 << 20, DiskBytes: 1 << 30}
+}
+
+func (c Config) Check() error {
+	if c.MemoryBytes < 0 {
+		return errors.New("memory_bytes must not be negative")
+	}
+	if len(c.Dir) != 0 && c.DiskBytes <= 0 {
+		return errors.New("disk_bytes must be positive")
+	}
+	for name, ttl := range c.TTLs {
+		if _, err := _common.ParseDAType(name); err != nil {
+			return fmt.Errorf("ttls: %w", err)
+		}
+		if ttl <= 0 {
+			return fmt.Errorf("ttls: %s must be positive", name)
+		}
+	}
+	return nil
+}
+
+// TTL returns how long data retrieved from daType is cached.
+func (c Config) TTL(daType int) time.Duration {
+	for name, ttl := range c.TTLs {
+		if t, err := _common.ParseDAType(name); err == nil && t == daType {
+			return ttl
+		}
+	}
+	return DefaultTTLs[daType]
+}
+
+// Key returns the canonical form of a receipt retrieving data from daType, ok is false for the
+// receipts whose data may change, such as a bare celestia height.
+func Key(daType int, args interface{}) (key string, ok bool) {
+	var s string
+	switch v := args.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case uint64:
+		s = strconv.FormatUint(v, 10)
+	case int:
+		s = strconv.Itoa(v)
+	case float64:
+		// json numbers decoded by the api
+		if v < 0 || v != math.Trunc(v) {
+			return "", false
+		}
+		s = strconv.FormatFloat(v, 'f', 0, 64)
+	case json.Number:
+		s = v.String()
+	case []byte:
+		s = base64.StdEncoding.EncodeToString(v)
+	default:
+		return "", false
+	}
+	switch daType {
+	case _common.AnytrustType, _common.AnytrustCommitteeType, _common.Eip4844Type:
+		// hex hashes, with or without their prefix
+		s = strings.TrimPrefix(strings.ToLower(s), "0x")
+	case _common.CelestiaType:
+		// a bare height reads the first blob of the namespace configured at the time
+		parts := strings.Split(strings.ToLower(s), ":")
+		if len(parts) < 2 {
+			return "", false
+		}
+		// the fee paid ends the receipt but doesn't identify the blob
+		if len(parts) > 2 {
+			parts = []string{parts[0], parts[1], strings.TrimPrefix(parts[2], "0x")}
+		}
+		s = strings.Join(parts, ":")
+	case _common.EigenDAType, _common.NearDAType:
+	default:
+		return "", false
+	}
+	return strconv.Itoa(daType) + ":" + s, len(s) != 0
+}
+
+type entry struct {
+	key    string
+	data   []byte
+	expiry time.Time
+}
+
+// Cache is a two-tier cache of retrieved DA data, an in-memory LRU in front of an on-disk store, both
+// bounded in bytes. Every entry carries the sha256 of its data, checked when it is read back from
+// disk. A nil Cache caches nothing. It is safe for concurrent use.
+type Cache struct {
+	conf Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element
+	lru      *list.List
+	memBytes int64
+
+	diskMu    sync.Mutex
+	diskBytes int64
+}
+
+// New returns the cache of conf, nil when both tiers are disabled.
+func New(conf Config) (*Cache, error) {
+	if conf.MemoryBytes == 0 && len(conf.Dir) == 0 {
+		return nil, nil
+	}
+	c := &Cache{conf: conf, now: time.Now, entries: make(map[string]*list.Element), lru: list.New()}
+	if len(conf.Dir) != 0 {
+		if err := os.MkdirAll(conf.Dir, 0o700); err != nil {
+			return nil, fmt.Errorf("create cache dir: %w", err)
+		}
+		files, err := c.diskFiles()
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			c.diskBytes += file.size
+		}
+	}
+	return c, nil
+}
+
+// Get returns the data cached for key and the tier it was found in.
+func (c *Cache) Get(key string) ([]byte, Result) {
+	if c == nil {
+		return nil, Miss
+	}
+	if data, ok := c.getMemory(key); ok {
+		return data, Memory
+	}
+	if data, expiry, ok := c.getDisk(key); ok {
+		c.putMemory(key, data, expiry)
+		return bytes.Clone(data), Disk
+	}
+	return nil, Miss
+}
+
+// Put caches data retrieved from daType with key, for the TTL of daType from stored, the time the DA
+// stored the data, or from now when stored is zero.
+func (c *Cache) Put(daType int, key string, data []byte, stored time.Time) {
+	if c == nil {
+		return
+	}
+	ttl := c.conf.TTL(daType)
+	if ttl <= 0 {
+		return
+	}
+	if stored.IsZero() {
+		stored = c.now()
+	}
+	expiry := stored.Add(ttl)
+	if !c.now().Before(expiry) {
+		return
+	}
+	data = bytes.Clone(data)
+	c.putMemory(key, data, expiry)
+	c.putDisk(key, data, expiry)
+}
+
+func (c *Cache) getMemory(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expiry) {
+		c.removeLocked(elem)
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return bytes.Clone(e.data), true
+}
+
+func (c *Cache) putMemory(key string, data []byte, expiry time.Time) {
+	size := int64(len(data))
+	if size > c.conf.MemoryBytes {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+	c.entries[key] = c.lru.PushFront(&entry{key: key, data: data, expiry: expiry})
+	c.memBytes += size
+	for c.memBytes > c.conf.MemoryBytes {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+func (c *Cache) removeLocked(elem *list.Element) {
+	e := c.lru.Remove(elem).(*entry)
+	delete(c.entries, e.key)
+	c.memBytes -= int64(len(e.data))
+}
+
+// A disk entry is the expiry in unix nanoseconds, the sha256 of the data, the length of the key,
+// the key and the data.
+const diskHeaderSize = 8 + sha256.Size + 2
+
+func (c *Cache) path(key string) string {
+	name := sha256.Sum256([]byte(key))
+	return filepath.Join(c.conf.Dir, hex.EncodeToString(name[:]))
+}
+
+func (c *Cache) getDisk(key string) ([]byte, time.Time, bool) {
+	if len(c.conf.Dir) == 0 {
+		return nil, time.Time{}, false
+	}
+	path := c.path(key)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+	data, expiry, err := decodeDiskEntry(raw, key)
+	if err == nil && !c.now().Before(expiry) {
+		err = errors.New("expired")
+	}
+	if err != nil {
+		log.Debug("dropping cache entry", "path", path, "err", err)
+		c.removeDisk(path, int64(len(raw)))
+		return nil, time.Time{}, false
+	}
+	// the mtime orders the eviction of the disk tier
+	now := c.now()
+	_ = os.Chtimes(path, now, now)
+	return data, expiry, true
+}
+
+func decodeDiskEntry(raw []byte, key string) ([]byte, time.Time, error) {
+	if len(raw) < diskHeaderSize {
+		return nil, time.Time{}, errors.New("truncated entry")
+	}
+	expiry := time.Unix(0, int64(binary.BigEndian.Uint64(raw)))
+	hash := raw[8 : 8+sha256.Size]
+	keyLen := int(binary.BigEndian.Uint16(raw[8+sha256.Size:]))
+	if len(raw) < diskHeaderSize+keyLen || string(raw[diskHeaderSize:diskHeaderSize+keyLen]) != key {
+		return nil, time.Time{}, errors.New("entry of another key")
+	}
+	data := raw[diskHeaderSize+keyLen:]
+	if sum := sha256.Sum256(data); !bytes.Equal(sum[:], hash) {
+		return nil, time.Time{}, errors.New("data does not match its hash")
+	}
+	return data, expiry, nil
+}
+
+func (c *Cache) putDisk(key string, data []byte, expiry time.Time) {
+	if len(c.conf.Dir) == 0 || len(key) > math.MaxUint16 {
+		return
+	}
+	size := int64(diskHeaderSize + len(key) + len(data))
+	if size > c.conf.DiskBytes {
+		return
+	}
+	raw := make([]byte, 0, size)
+	raw = binary.BigEndian.AppendUint64(raw, uint64(expiry.UnixNano()))
+	hash := sha256.Sum256(data)
+	raw = append(raw, hash[:]...)
+	raw = binary.BigEndian.AppendUint16(raw, uint16(len(key)))
+	raw = append(append(raw, key...), data...)
+
+	c.diskMu.Lock()
+	defer c.diskMu.Unlock()
+	path := c.path(key)
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	// written aside then renamed, a crash never leaves a partial entry behind
+	tmp, err := os.CreateTemp(c.conf.Dir, ".tmp-*")
+	if err == nil {
+		_, err = tmp.Write(raw)
+		err = errors.Join(err, tmp.Close())
+		if err == nil {
+			err = os.Rename(tmp.Name(), path)
+		}
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}
+	if err != nil {
+		log.Warn("failed to write cache entry", "path", path, "err", err)
+		return
+	}
+	c.diskBytes += size - replaced
+	if c.diskBytes > c.conf.DiskBytes {
+		c.evictDiskLocked()
+	}
+}
+
+func (c *Cache) removeDisk(path string, size int64) {
+	c.diskMu.Lock()
+	defer c.diskMu.Unlock()
+	if err := os.Remove(path); err == nil {
+		c.diskBytes -= size
+	}
+}
+
+type diskFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func (c *Cache) diskFiles() ([]diskFile, error) {
+	dirEntries, err := os.ReadDir(c.conf.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("read cache dir: %w", err)
+	}
+	files := make([]diskFile, 0, len(dirEntries))
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil || !info.Mode().IsRegular() || strings.HasPrefix(dirEntry.Name(), ".") {
+			continue
+		}
+		files = append(files, diskFile{path: filepath.Join(c.conf.Dir, dirEntry.Name()), size: info.Size(), modTime: info.ModTime()})
+	}
+	return files, nil
+}
+
+// evictDiskLocked removes the least recently used entries until the disk tier fits its budget.
+func (c *Cache) evictDiskLocked() {
+	files, err := c.diskFiles()
+	if err != nil {
+		log.Warn("failed to evict cache entries", "err", err)
+		return
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	c.diskBytes = 0
+	for _, file := range files {
+		c.diskBytes += file.size
+	}
+	for _, file := range files {
+		if c.diskBytes <= c.conf.DiskBytes {
+			return
+		}
+		if err := os.Remove(file.path); err == nil {
+			c.diskBytes -= file.size
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestKey(t *testing.T) {
+	for _, tt := range []struct {
+		daType int
+		args   interface{}
+		key    string
+		ok     bool
+	}{
+		{_common.AnytrustType, " 0xABcd", "0:abcd", true},
+		{_common.Eip4844Type, "0xabcd", "3:abcd", true},
+		{_common.CelestiaType, "42:00000000000000000000000000000000000000000000deadbeef:0xAB:100utia", "1:42:00000000000000000000000000000000000000000000deadbeef:ab", true},
+		{_common.CelestiaType, "42:00000000000000000000000000000000000000000000deadbeef:ab", "1:42:00000000000000000000000000000000000000000000deadbeef:ab", true},
+		{_common.CelestiaType, uint64(42), "", false},
+		{_common.CelestiaType, json.Number("42"), "", false},
+		{_common.EigenDAType, "request-id", "2:request-id", true},
+		{_common.NearDAType, float64(7), "4:7", true},
+		{_common.NearDAType, 1.5, "", false},
+		{_common.AnytrustType, "", "", false},
+		{_common.AnytrustType, struct{}{}, "", false},
+		{_common.ErasureType, "erasure1:abc", "", false},
+	} {
+		key, ok := Key(tt.daType, tt.args)
+		require.Equal(t, tt.ok, ok, "%v", tt.args)
+		if ok {
+			require.Equal(t, tt.key, key)
+		}
+	}
+}
+
+func TestMemoryTier(t *testing.T) {
+	c, err := New(Config{MemoryBytes: 10})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+
+	c.Put(_common.EigenDAType, "a", []byte("aaaa"), time.Time{})
+	c.Put(_common.EigenDAType, "b", []byte("bbbb"), time.Time{})
+	data, result := c.Get("a")
+	require.Equal(t, Memory, result)
+	require.Equal(t, []byte("aaaa"), data)
+
+	// b is the least recently used entry and goes first
+	c.Put(_common.EigenDAType, "c", []byte("cccc"), time.Time{})
+	_, result = c.Get("b")
+	require.Equal(t, Miss, result)
+	_, result = c.Get("a")
+	require.Equal(t, Memory, result)
+
+	// larger than the whole budget
+	c.Put(_common.EigenDAType, "d", make([]byte, 11), time.Time{})
+	_, result = c.Get("d")
+	require.Equal(t, Miss, result)
+
+	now = now.Add(DefaultTTLs[_common.EigenDAType])
+	_, result = c.Get("a")
+	require.Equal(t, Miss, result)
+}
+
+func TestExpiryFromStorage(t *testing.T) {
+	c, err := New(Config{MemoryBytes: 1 << 10})
+	require.NoError(t, err)
+	now := time.Unix(1000, 0)
+	c.now = func() time.Time { return now }
+	retention := DefaultTTLs[_common.Eip4844Type]
+
+	// a blob included a day before its retrieval expires with the retention of its block
+	c.Put(_common.Eip4844Type, "3:a", []byte("blob"), now.Add(-24*time.Hour))
+	now = now.Add(retention - 24*time.Hour - time.Second)
+	_, result := c.Get("3:a")
+	require.Equal(t, Memory, result)
+	now = now.Add(time.Second)
+	_, result = c.Get("3:a")
+	require.Equal(t, Miss, result)
+
+	// a blob past its retention isn't cached at all
+	c.Put(_common.Eip4844Type, "3:b", []byte("blob"), now.Add(-retention))
+	_, result = c.Get("3:b")
+	require.Equal(t, Miss, result)
+}
+
+func TestDiskTier(t *testing.T) {
+	dir := t.TempDir()
+	conf := Config{Dir: dir, DiskBytes: 1 << 10, TTLs: map[string]time.Duration{"nearda": time.Hour}}
+	c, err := New(conf)
+	require.NoError(t, err)
+	c.Put(_common.NearDAType, "4:a", []byte("near batch"), time.Time{})
+	c.Put(_common.AnytrustType, "0:b", []byte("anytrust batch"), time.Time{})
+
+	// a restarted node reads the disk tier
+	c, err = New(conf)
+	require.NoError(t, err)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	data, result := c.Get("4:a")
+	require.Equal(t, Disk, result)
+	require.Equal(t, []byte("near batch"), data)
+	_, result = c.Get("4:a")
+	require.Equal(t, Disk, result, "the memory tier is disabled")
+
+	// an entry no longer matching its hash is dropped
+	path := c.path("0:b")
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 1
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+	_, result = c.Get("0:b")
+	require.Equal(t, Miss, result)
+	require.NoFileExists(t, path)
+
+	// the nearda ttl is overridden
+	now = now.Add(time.Hour)
+	_, result = c.Get("4:a")
+	require.Equal(t, Miss, result)
+	require.NoFileExists(t, c.path("4:a"))
+}
+
+func TestDiskEviction(t *testing.T) {
+	c, err := New(Config{Dir: t.TempDir(), DiskBytes: 3 * (diskHeaderSize + 3 + 100)})
+	require.NoError(t, err)
+	for i, key := range []string{"2:a", "2:b", "2:c"} {
+		c.Put(_common.EigenDAType, key, make([]byte, 100), time.Time{})
+		mtime := time.Now().Add(time.Duration(i-10) * time.Minute)
+		require.NoError(t, os.Chtimes(c.path(key), mtime, mtime))
+	}
+	// reading a refreshes it, b is the least recently used entry
+	_, result := c.Get("2:a")
+	require.Equal(t, Disk, result)
+	c.Put(_common.EigenDAType, "2:d", make([]byte, 100), time.Time{})
+	for key, want := range map[string]Result{"2:a": Disk, "2:b": Miss, "2:c": Disk, "2:d": Disk} {
+		_, result := c.Get(key)
+		require.Equal(t, want, result, key)
+	}
+	require.LessOrEqual(t, c.diskBytes, c.conf.DiskBytes)
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, DefaultConfig().Check())
+	require.Error(t, Config{MemoryBytes: -1}.Check())
+	require.Error(t, Config{Dir: "cache"}.Check())
+	require.Error(t, Config{TTLs: map[string]time.Duration{"avail": time.Hour}}.Check())
+	require.Error(t, Config{TTLs: map[string]time.Duration{"celestia": 0}}.Check())
+
+	c, err := New(Config{})
+	require.NoError(t, err)
+	require.Nil(t, c)
+	c.Put(_common.EigenDAType, "2:a", []byte("a"), time.Time{})
+	_, result := c.Get("2:a")
+	require.Equal(t, Miss, result)
+}
@@ -0,0 +1,300 @@
+package chunk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// Version is the version of the manifest layout and of the receipts.
+const Version = 1
+
+// Magic is the first byte of a stored manifest. Its high nibble is no codec id and differs from the
+// encryption envelope and the aggregate blob.
+const Magic = 0xc0 | Version
+
+// ReceiptPrefix starts the receipt of a chunked submission.
+const ReceiptPrefix = "chunked1:"
+
+var (
+	ErrInvalidManifest = errors.New("invalid chunk manifest")
+	ErrChunkMismatch   = errors.New("chunk does not match the manifest")
+)
+
+// DefaultChunkSizes stay below the payload ceiling of every DA.
+var DefaultChunkSizes = map[int]int{
+	_common.AnytrustType: 1_000_000,
+	// the max blob size of a 64x64 data square
+	_common.CelestiaType: 1_900_000,
+	_common.EigenDAType:  2_000_000,
+	// the data a single blob holds
+	_common.Eip4844Type:           130_044,
+	_common.NearDAType:            3_500_000,
+	_common.AnytrustCommitteeType: 1_000_000,
+}
+
+// MaxReceiptSizes bound the length of the receipt each DA returns for a chunk, so the size of a
+// manifest is known before any chunk is paid for.
+var MaxReceiptSizes = map[int]int{
+	// a hex hash
+	_common.AnytrustType: 66,
+	// the height, namespace, commitment and fee of the blob
+	_common.CelestiaType: 192,
+	// the base64 of the request id of the disperser
+	_common.EigenDAType: 256,
+	// a hex transaction hash
+	_common.Eip4844Type: 66,
+	// the base64 of the frame reference
+	_common.NearDAType:            128,
+	_common.AnytrustCommitteeType: 66,
+}
+
+// Config of the chunked upload of payloads above the ceiling of a DA.
+type Config struct {
+	// DAs lists the names or da types whose large payloads are chunked, none when empty
+	DAs []string `mapstructure:"das"`
+	// ChunkSizes overrides DefaultChunkSizes by da name
+	ChunkSizes map[string]int `mapstructure:"chunk_sizes"`
+	// Parallelism is how many chunks are submitted or retrieved at once
+	Parallelism int `mapstructure:"parallelism"`
+}
+
+func DefaultConfig() Config {
+	return Config{Parallelism: 4}
+}
+
+func (c Config) Check() error {
+	if _, err := c.DATypes(); err != nil {
+		return err
+	}
+	for name, size := range c.ChunkSizes {
+		if _, err := _common.ParseDAType(name); err != nil {
+			return fmt.Errorf("chunk_sizes: %w", err)
+		}
+		if size <= 0 {
+			return fmt.Errorf("chunk_sizes: %s must be positive", name)
+		}
+	}
+	if len(c.DAs) != 0 && c.Parallelism <= 0 {
+		return errors.New("parallelism must be positive")
+	}
+	return nil
+}
+
+// DATypes returns the chunked da types.
+func (c Config) DATypes() (map[int]bool, error) {
+	daTypes := make(map[int]bool, len(c.DAs))
+	for _, name := range c.DAs {
+		daType, err := _common.ParseDAType(name)
+		if err != nil {
+			return nil, err
+		}
+		daTypes[daType] = true
+	}
+	return daTypes, nil
+}
+
+// ChunkSize returns the size payloads to daType are split at, 0 when they aren't chunked.
+func (c Config) ChunkSize(daType int) int {
+	daTypes, err := c.DATypes()
+	if err != nil || !daTypes[daType] {
+		return 0
+	}
+	for name, size := range c.ChunkSizes {
+		if t, err := _common.ParseDAType(name); err == nil && t == daType {
+			return size
+		}
+	}
+	return DefaultChunkSizes[daType]
+}
+
+// Split cuts data in chunks of size bytes, the last one holding the rest.
+func Split(data []byte, size int) [][]byte {
+	chunks := make([][]byte, 0, (len(data)+size-1)/size)
+	for len(data) > size {
+		chunks = append(chunks, data[:size])
+		data = data[size:]
+	}
+	return append(chunks, data)
+}
+
+// Chunk is a part of the object as the manifest lists it.
+type Chunk struct {
+	// DAArgs retrieves the chunk from the DA, as the DA's own receipt would
+	DAArgs interface{} `json:"da_args"`
+	Size   int         `json:"size"`
+	Hash   []byte      `json:"hash"`
+}
+
+// Manifest lists the chunks of an object in order.
+type Manifest struct {
+	Version int     `json:"version"`
+	Size    int     `json:"size"`
+	Hash    []byte  `json:"hash"`
+	Chunks  []Chunk `json:"chunks"`
+}
+
+// NewManifest describes data cut in chunks, the DAArgs of the chunks are set once they are stored.
+func NewManifest(data []byte, chunks [][]byte) *Manifest {
+	hash := sha256.Sum256(data)
+	m := &Manifest{Version: Version, Size: len(data), Hash: hash[:], Chunks: make([]Chunk, len(chunks))}
+	for i, chunk := range chunks {
+		hash := sha256.Sum256(chunk)
+		m.Chunks[i] = Chunk{Size: len(chunk), Hash: hash[:]}
+	}
+	return m
+}
+
+// Encode returns the manifest as stored on the DA: the magic byte followed by its json.
+func (m *Manifest) Encode() []byte {
+	raw, _ := json.Marshal(m)
+	return append([]byte{Magic}, raw...)
+}
+
+// EncodedSizeBound returns the size Encode reaches at most once every chunk holds a receipt of
+// receiptSize bytes at most.
+func (m *Manifest) EncodedSizeBound(receiptSize int) int {
+	bound := *m
+	bound.Chunks = make([]Chunk, len(m.Chunks))
+	for i, chunk := range m.Chunks {
+		chunk.DAArgs = strings.Repeat("0", receiptSize)
+		bound.Chunks[i] = chunk
+	}
+	return len(bound.Encode())
+}
+
+// DecodeManifest decodes a manifest written by Encode.
+func DecodeManifest(data []byte) (*Manifest, error) {
+	if len(data) == 0 || data[0] != Magic {
+		return nil, ErrInvalidManifest
+	}
+	m := &Manifest{}
+	if err := decodeJSON(data[1:], m); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if m.Version != Version || len(m.Chunks) == 0 || len(m.Hash) != sha256.Size {
+		return nil, ErrInvalidManifest
+	}
+	size := 0
+	for i := range m.Chunks {
+		args, err := daArgs(m.Chunks[i].DAArgs)
+		if err != nil {
+			return nil, fmt.Errorf("%w: chunk %d: %v", ErrInvalidManifest, i, err)
+		}
+		m.Chunks[i].DAArgs = args
+		size += m.Chunks[i].Size
+	}
+	if size != m.Size {
+		return nil, fmt.Errorf("%w: chunks sum up to %d bytes, not %d", ErrInvalidManifest, size, m.Size)
+	}
+	return m, nil
+}
+
+// Check verifies the i-th chunk against the manifest.
+func (m *Manifest) Check(i int, chunk []byte) error {
+	hash := sha256.Sum256(chunk)
+	if len(chunk) != m.Chunks[i].Size || !bytes.Equal(hash[:], m.Chunks[i].Hash) {
+		return fmt.Errorf("%w: chunk %d", ErrChunkMismatch, i)
+	}
+	return nil
+}
+
+// Assemble joins the chunks after verifying each of them and the whole object.
+func (m *Manifest) Assemble(chunks [][]byte) ([]byte, error) {
+	if len(chunks) != len(m.Chunks) {
+		return nil, fmt.Errorf("%w: %d chunks, the manifest lists %d", ErrChunkMismatch, len(chunks), len(m.Chunks))
+	}
+	data := make([]byte, 0, m.Size)
+	for i, chunk := range chunks {
+		if err := m.Check(i, chunk); err != nil {
+			return nil, err
+		}
+		data = append(data, chunk...)
+	}
+	if hash := sha256.Sum256(data); !bytes.Equal(hash[:], m.Hash) {
+		return nil, fmt.Errorf("%w: object hash", ErrChunkMismatch)
+	}
+	return data, nil
+}
+
+// Receipt retrieves the manifest of a chunked submission.
+type Receipt struct {
+	// DAArgs retrieves the manifest from the DA
+	DAArgs interface{} `json:"da_args"`
+	// Hash is the sha256 of the stored manifest
+	Hash []byte `json:"hash"`
+}
+
+// NewReceipt returns the receipt of the stored manifest.
+func NewReceipt(daArgs interface{}, manifest []byte) *Receipt {
+	hash := sha256.Sum256(manifest)
+	return &Receipt{DAArgs: daArgs, Hash: hash[:]}
+}
+
+// String encodes the receipt as ReceiptPrefix followed by its base64 json.
+func (r *Receipt) String() string {
+	raw, _ := json.Marshal(r)
+	return ReceiptPrefix + base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsReceipt reports whether args is the receipt of a chunked submission.
+func IsReceipt(args interface{}) bool {
+	s, ok := args.(string)
+	return ok && strings.HasPrefix(s, ReceiptPrefix)
+}
+
+// ParseReceipt decodes a receipt written by Receipt.String.
+func ParseReceipt(s string) (*Receipt, error) {
+	encoded, ok := strings.CutPrefix(s, ReceiptPrefix)
+	if !ok {
+		return nil, fmt.Errorf("chunked receipt must start with %s", ReceiptPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode chunked receipt: %w", err)
+	}
+	r := &Receipt{}
+	if err := decodeJSON(raw, r); err != nil {
+		return nil, fmt.Errorf("decode chunked receipt: %w", err)
+	}
+	if r.DAArgs, err = daArgs(r.DAArgs); err != nil {
+		return nil, fmt.Errorf("decode chunked receipt: %w", err)
+	}
+	if len(r.Hash) != sha256.Size {
+		return nil, errors.New("chunked receipt lacks the manifest hash")
+	}
+	return r, nil
+}
+
+// OpenManifest checks data is the manifest the receipt points at and decodes it.
+func (r *Receipt) OpenManifest(data []byte) (*Manifest, error) {
+	if hash := sha256.Sum256(data); !bytes.Equal(hash[:], r.Hash) {
+		return nil, fmt.Errorf("%w: manifest hash", ErrInvalidManifest)
+	}
+	return DecodeManifest(data)
+}
+
+func decodeJSON(raw []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// keep the da args a number when it is one, e.g. a celestia height
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+func daArgs(args interface{}) (interface{}, error) {
+	n, ok := args.(json.Number)
+	if !ok {
+		return args, nil
+	}
+	height, err := n.Int64()
+	if err != nil || height < 0 {
+		return nil, fmt.Errorf("da args %s is no height", n)
+	}
+	return uint64(height), nil
+}
@@ -0,0 +1,119 @@
+package chunk
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func testObject() []byte {
+	return bytes.Repeat([]byte("large rollup batch "), 100)
+}
+
+func TestSplit(t *testing.T) {
+	data := testObject()
+	chunks := Split(data, 512)
+	require.Len(t, chunks, 4)
+	for _, chunk := range chunks[:3] {
+		require.Len(t, chunk, 512)
+	}
+	require.Equal(t, data, bytes.Join(chunks, nil))
+
+	require.Len(t, Split(data[:512], 512), 1)
+}
+
+func TestManifestAssemble(t *testing.T) {
+	data := testObject()
+	chunks := Split(data, 512)
+	manifest := NewManifest(data, chunks)
+	for i := range manifest.Chunks {
+		manifest.Chunks[i].DAArgs = fmt.Sprintf("0x%02x", i)
+	}
+	manifest.Chunks[0].DAArgs = uint64(1 << 60)
+
+	encoded := manifest.Encode()
+	require.LessOrEqual(t, len(encoded), manifest.EncodedSizeBound(19))
+	require.Greater(t, len(encoded), manifest.EncodedSizeBound(4))
+	require.Equal(t, "0x01", manifest.Chunks[1].DAArgs)
+	require.Equal(t, byte(Magic), encoded[0])
+	receipt := NewReceipt("0xff", encoded)
+	decoded, err := receipt.OpenManifest(encoded)
+	require.NoError(t, err)
+	require.Equal(t, manifest, decoded)
+
+	assembled, err := decoded.Assemble(chunks)
+	require.NoError(t, err)
+	require.Equal(t, data, assembled)
+
+	// a tampered chunk, a missing one or chunks out of order
+	tampered := append([]byte{}, chunks[1]...)
+	tampered[0] ^= 1
+	_, err = decoded.Assemble([][]byte{chunks[0], tampered, chunks[2], chunks[3]})
+	require.ErrorIs(t, err, ErrChunkMismatch)
+	_, err = decoded.Assemble(chunks[:3])
+	require.ErrorIs(t, err, ErrChunkMismatch)
+	_, err = decoded.Assemble([][]byte{chunks[1], chunks[0], chunks[2], chunks[3]})
+	require.ErrorIs(t, err, ErrChunkMismatch)
+
+	// a manifest other than the receipt's
+	other := NewManifest(data[:512], chunks[:1]).Encode()
+	_, err = receipt.OpenManifest(other)
+	require.ErrorIs(t, err, ErrInvalidManifest)
+}
+
+func TestDecodeManifestRejects(t *testing.T) {
+	data := testObject()
+	manifest := NewManifest(data, Split(data, 512))
+	manifest.Size++
+
+	for _, invalid := range [][]byte{nil, []byte("plain rollup batch"), {Magic, '{'}, manifest.Encode()} {
+		_, err := DecodeManifest(invalid)
+		require.ErrorIs(t, err, ErrInvalidManifest)
+	}
+}
+
+func TestReceiptRoundTrip(t *testing.T) {
+	for _, daArgs := range []interface{}{uint64(12), "0x0a0b"} {
+		receipt := NewReceipt(daArgs, []byte("manifest"))
+		s := receipt.String()
+		require.True(t, IsReceipt(s))
+
+		parsed, err := ParseReceipt(s)
+		require.NoError(t, err)
+		require.Equal(t, receipt, parsed)
+	}
+
+	require.False(t, IsReceipt("agg1:e30"))
+	for _, invalid := range []string{"0x0a0b", ReceiptPrefix + "!", ReceiptPrefix + "e30"} {
+		_, err := ParseReceipt(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	conf := DefaultConfig()
+	require.NoError(t, conf.Check())
+	require.Zero(t, conf.ChunkSize(_common.Eip4844Type))
+
+	conf.DAs = []string{"eip4844", "celestia"}
+	conf.ChunkSizes = map[string]int{"celestia": 1000}
+	require.NoError(t, conf.Check())
+	require.Equal(t, DefaultChunkSizes[_common.Eip4844Type], conf.ChunkSize(_common.Eip4844Type))
+	require.Equal(t, 1000, conf.ChunkSize(_common.CelestiaType))
+	require.Zero(t, conf.ChunkSize(_common.EigenDAType))
+
+	for _, invalid := range []func(c *Config){
+		func(c *Config) { c.DAs = []string{"avail"} },
+		func(c *Config) { c.ChunkSizes = map[string]int{"avail": 1000} },
+		func(c *Config) { c.ChunkSizes = map[string]int{"celestia": 0} },
+		func(c *Config) { c.Parallelism = 0 },
+	} {
+		c := conf
+		invalid(&c)
+		require.Error(t, c.Check())
+	}
+}
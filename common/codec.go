@@ -0,0 +1,20 @@
+package common
+
+import "context"
+
+type codecKey struct{}
+
+// WithCodec returns a copy of ctx carrying the codec a rollup request is compressed with,
+// overriding the codec configured for the DA. "none" stores the request raw.
+func WithCodec(ctx context.Context, codec string) context.Context {
+	if len(codec) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, codecKey{}, codec)
+}
+
+// CodecFromContext returns the codec set by WithCodec, empty for the DA's configured one.
+func CodecFromContext(ctx context.Context) string {
+	codec, _ := ctx.Value(codecKey{}).(string)
+	return codec
+}
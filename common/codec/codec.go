@@ -0,0 +1,221 @@
+package codec
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// ID identifies a codec in the envelope header.
+type ID byte
+
+const (
+	None   ID = 0
+	Zstd   ID = 1
+	Brotli ID = 2
+	Zlib   ID = 3
+)
+
+// Version is the envelope version written by Encode, it is bumped when a codec changes its settings
+// in a way older decoders can't read.
+const Version = 1
+
+// MaxDecodedSize bounds the output of Decode so a crafted payload can't exhaust memory.
+const MaxDecodedSize = 128 << 20
+
+var (
+	ErrUnknownCodec = errors.New("unknown codec")
+	ErrTooLarge     = fmt.Errorf("decoded payload exceeds %d bytes", MaxDecodedSize)
+)
+
+// Codec compresses payloads before they are dispersed.
+type Codec interface {
+	ID() ID
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+var codecs = map[ID]Codec{
+	Zstd:   zstdCodec{},
+	Brotli: brotliCodec{},
+	Zlib:   zlibCodec{},
+}
+
+// Lookup returns the codec with the given name, nil for "none" or an empty name.
+func Lookup(name string) (Codec, error) {
+	name = strings.ToLower(name)
+	if len(name) == 0 || name == "none" {
+		return nil, nil
+	}
+	for _, c := range codecs {
+		if c.Name() == name {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w %q, expected one of %s", ErrUnknownCodec, name, strings.Join(Names(), ", "))
+}
+
+// Names lists the accepted codec names.
+func Names() []string {
+	names := []string{"none"}
+	for _, c := range codecs {
+		names = append(names, c.Name())
+	}
+	sort.Strings(names[1:])
+	return names
+}
+
+// Name returns the name of the codec with the given id, "none" for payloads stored raw.
+func Name(id ID) string {
+	if c, ok := codecs[id]; ok {
+		return c.Name()
+	}
+	return "none"
+}
+
+func header(id ID) byte {
+	return byte(id)<<4 | Version
+}
+
+// Encode compresses data with c behind a one byte header, the codec id in the high nibble and the
+// envelope version in the low one. Data is returned as is when c is nil, so DAs without a codec
+// store exactly what they stored before codecs existed. A codec always writes its header, even when
+// compressing doesn't make the data smaller, so its payloads are never read back as raw data.
+func Encode(c Codec, data []byte) ([]byte, ID, error) {
+	if c == nil {
+		return data, None, nil
+	}
+	compressed, err := c.Compress(data)
+	if err != nil {
+		return nil, None, fmt.Errorf("%s compress: %w", c.Name(), err)
+	}
+	return append([]byte{header(c.ID())}, compressed...), c.ID(), nil
+}
+
+// Decode reverses Encode. Payloads without a valid header, naming no known codec or whose body isn't
+// a valid stream of the codec it names, were stored raw and are returned as is.
+func Decode(data []byte) ([]byte, ID, error) {
+	if len(data) < 2 || data[0]&0x0f != Version {
+		return data, None, nil
+	}
+	c, ok := codecs[ID(data[0]>>4)]
+	if !ok {
+		return data, None, nil
+	}
+	decoded, err := c.Decompress(data[1:])
+	if errors.Is(err, ErrTooLarge) {
+		return nil, None, err
+	}
+	if err != nil {
+		return data, None, nil
+	}
+	return decoded, c.ID(), nil
+}
+
+// readAll reads r up to MaxDecodedSize.
+func readAll(r io.Reader) ([]byte, error) {
+	out, err := io.ReadAll(io.LimitReader(r, MaxDecodedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(out) > MaxDecodedSize {
+		return nil, ErrTooLarge
+	}
+	return out, nil
+}
+
+type zstdCodec struct{}
+
+func (zstdCodec) ID() ID       { return Zstd }
+func (zstdCodec) Name() string { return "zstd" }
+
+// the encoder and decoder are safe for concurrent EncodeAll and DecodeAll calls
+var (
+	zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBetterCompression))
+	zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxDecodedSize), zstd.WithDecoderConcurrency(0))
+)
+
+func (zstdCodec) Compress(data []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(data, nil), nil
+}
+
+func (zstdCodec) Decompress(data []byte) ([]byte, error) {
+	out, err := zstdDecoder.DecodeAll(data, nil)
+	if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+		return nil, ErrTooLarge
+	}
+	if len(out) > MaxDecodedSize {
+		return nil, ErrTooLarge
+	}
+	return out, err
+}
+
+type brotliCodec struct{}
+
+func (brotliCodec) ID() ID       { return Brotli }
+func (brotliCodec) Name() string { return "brotli" }
+
+func (brotliCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := brotli.NewWriterLevel(&buf, brotli.DefaultCompression)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (brotliCodec) Decompress(data []byte) ([]byte, error) {
+	// the reader fails on bytes past the end of the stream
+	return readAll(brotli.NewReader(bytes.NewReader(data)))
+}
+
+type zlibCodec struct{}
+
+func (zlibCodec) ID() ID       { return Zlib }
+func (zlibCodec) Name() string { return "zlib" }
+
+func (zlibCodec) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := zlib.NewWriterLevel(&buf, zlib.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (zlibCodec) Decompress(data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	out, err := readAll(zr)
+	if err != nil {
+		return nil, err
+	}
+	if err := zr.Close(); err != nil {
+		return nil, err
+	}
+	// trailing bytes mean the payload only happened to start like a zlib stream
+	if r.Len() != 0 {
+		return nil, errors.New("zlib: trailing data")
+	}
+	return out, nil
+}
@@ -0,0 +1,136 @@
+package codec
+
+import (
+	"bytes"
+	"compress/zlib"
+	"crypto/rand"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	data := bytes.Repeat([]byte("rollup batch "), 512)
+	for _, name := range []string{"zstd", "brotli", "zlib"} {
+		c, err := Lookup(name)
+		require.NoError(t, err)
+
+		encoded, id, err := Encode(c, data)
+		require.NoError(t, err)
+		require.Equal(t, c.ID(), id)
+		require.Equal(t, byte(c.ID())<<4|Version, encoded[0])
+		require.Less(t, len(encoded), len(data)/10, name)
+
+		decoded, id, err := Decode(encoded)
+		require.NoError(t, err)
+		require.Equal(t, c.ID(), id)
+		require.Equal(t, data, decoded, name)
+	}
+}
+
+func TestEncodeIncompressible(t *testing.T) {
+	data := make([]byte, 1024)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+
+	// a codec writes its header even when the data doesn't shrink
+	c, err := Lookup("zstd")
+	require.NoError(t, err)
+	encoded, id, err := Encode(c, data)
+	require.NoError(t, err)
+	require.Equal(t, Zstd, id)
+	require.Equal(t, header(Zstd), encoded[0])
+	decoded, id, err := Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, Zstd, id)
+	require.Equal(t, data, decoded)
+
+	// without codec the data is stored as is
+	encoded, id, err = Encode(nil, data)
+	require.NoError(t, err)
+	require.Equal(t, None, id)
+	require.Equal(t, data, encoded)
+}
+
+func TestDecodeRaw(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{0x11},
+		[]byte("plain rollup batch"),
+		// raw data starting with the version nibble
+		{0x01, 0x02, 0x03},
+		// known headers followed by bytes that aren't a stream of that codec
+		append([]byte{0x11}, []byte("not zstd")...),
+		append([]byte{0x21}, []byte("not brotli")...),
+		append([]byte{0x31}, []byte("not zlib")...),
+		// unknown codec and unknown version
+		{0x91, 0x00, 0x01},
+		{0x12, 0x00, 0x01},
+	} {
+		decoded, id, err := Decode(data)
+		require.NoError(t, err)
+		require.Equal(t, None, id)
+		require.Equal(t, data, decoded)
+	}
+}
+
+func TestDecodeTrailingData(t *testing.T) {
+	data := bytes.Repeat([]byte("rollup batch "), 512)
+	for _, name := range []string{"brotli", "zlib"} {
+		c, err := Lookup(name)
+		require.NoError(t, err)
+		encoded, _, err := Encode(c, data)
+		require.NoError(t, err)
+
+		tampered := append(append([]byte{}, encoded...), 0x00, 0x01)
+		decoded, id, err := Decode(tampered)
+		require.NoError(t, err)
+		require.Equal(t, None, id, name)
+		require.Equal(t, tampered, decoded)
+	}
+}
+
+func TestDecodeTooLarge(t *testing.T) {
+	// built at the fastest levels, compressing this much at the levels Encode uses takes seconds
+	bomb := make([]byte, MaxDecodedSize+1)
+	var zlibBomb, brotliBomb bytes.Buffer
+	zw, err := zlib.NewWriterLevel(&zlibBomb, zlib.BestSpeed)
+	require.NoError(t, err)
+	_, err = zw.Write(bomb)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	bw := brotli.NewWriterLevel(&brotliBomb, brotli.BestSpeed)
+	_, err = bw.Write(bomb)
+	require.NoError(t, err)
+	require.NoError(t, bw.Close())
+	zstdEnc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest))
+	require.NoError(t, err)
+
+	for id, compressed := range map[ID][]byte{
+		Zstd:   zstdEnc.EncodeAll(bomb, nil),
+		Brotli: brotliBomb.Bytes(),
+		Zlib:   zlibBomb.Bytes(),
+	} {
+		_, _, err := Decode(append([]byte{header(id)}, compressed...))
+		require.ErrorIs(t, err, ErrTooLarge, Name(id))
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range []string{"", "none", "NONE"} {
+		c, err := Lookup(name)
+		require.NoError(t, err)
+		require.Nil(t, c)
+	}
+	c, err := Lookup("Zstd")
+	require.NoError(t, err)
+	require.Equal(t, "zstd", c.Name())
+
+	_, err = Lookup("lz4")
+	require.ErrorIs(t, err, ErrUnknownCodec)
+	require.Equal(t, []string{"none", "brotli", "zlib", "zstd"}, Names())
+	require.Equal(t, "brotli", Name(Brotli))
+	require.Equal(t, "none", Name(ID(9)))
+}
@@ -1,5 +1,11 @@
 package common
 
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
 const (
 	AnytrustType = iota
 	CelestiaType
@@ -7,4 +13,57 @@ const (
 	Eip4844Type
 	NearDAType
 	AnytrustCommitteeType
+	// ErasureType spreads erasure coded shards of a payload over several backends, it is no backend itself
+	ErasureType
 )
+
+// DATypes lists the da type of every DA backend in da_type order.
+var DATypes = []int{
+	AnytrustType,
+	CelestiaType,
+	EigenDAType,
+	Eip4844Type,
+	NearDAType,
+	AnytrustCommitteeType,
+}
+
+// DARetention is how long each DA keeps serving the data it stored.
+var DARetention = map[int]time.Duration{
+	AnytrustType: 14 * 24 * time.Hour,
+	// the sampling window light nodes keep blobs for
+	CelestiaType: 30 * 24 * time.Hour,
+	EigenDAType:  14 * 24 * time.Hour,
+	// beacon nodes prune blob sidecars after 4096 epochs
+	Eip4844Type: 18 * 24 * time.Hour,
+	// rpc nodes garbage collect after 5 epochs
+	NearDAType:            60 * time.Hour,
+	AnytrustCommitteeType: 14 * 24 * time.Hour,
+}
+
+var daTypeNames = map[int]string{
+	AnytrustType:          "anytrust",
+	CelestiaType:          "celestia",
+	EigenDAType:           "eigenda",
+	Eip4844Type:           "eip4844",
+	NearDAType:            "nearda",
+	AnytrustCommitteeType: "anytrust-das-committee",
+	ErasureType:           "erasure",
+}
+
+// DATypeName returns the name used in logs, metrics and api responses for the given da type.
+func DATypeName(daType int) string {
+	if name, ok := daTypeNames[daType]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseDAType accepts a da type name as returned by DATypeName or its da_type number.
+func ParseDAType(s string) (int, error) {
+	for daType, name := range daTypeNames {
+		if name == s || strconv.Itoa(daType) == s {
+			return daType, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown da type %q", s)
+}
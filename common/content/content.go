@@ -0,0 +1,285 @@
+package content
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	ErrNotFound     = errors.New("no receipt of the payload hash")
+	ErrInvalidHash  = errors.New("payload hash must be the hex of 32 bytes")
+	ErrHashMismatch = errors.New("retrieved data does not match the payload hash")
+)
+
+// Entry is a receipt this node returned for a payload.
+type Entry struct {
+	DAType int `json:"da_type"`
+	// Receipt retrieves the payload from DAType
+	Receipt interface{} `json:"receipt"`
+	Time    time.Time   `json:"time"`
+}
+
+// record is a line of the index file.
+type record struct {
+	SHA256    string `json:"sha256"`
+	Keccak256 string `json:"keccak256"`
+	Entry
+}
+
+// DefaultMaxEntries bounds the receipts held by an index, each takes a few hundred bytes.
+const DefaultMaxEntries = 100000
+
+// item is a payload of the index.
+type item struct {
+	sha256    string
+	keccak256 string
+	// entries are the receipts of the payload, the newest last
+	entries []Entry
+	elem    *list.Element
+}
+
+// Index maps the sha256 and the keccak256 of the payloads rolled up by this node to their receipts.
+// A receipt returned again for a payload is held once, and the payloads rolled up the longest ago
+// are dropped once the index holds more than its maximum of receipts. It is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+	// items are the payloads by sha256, byKeccak256 maps their keccak256 to their sha256
+	items       map[string]*item
+	byKeccak256 map[string]string
+	// order lists the payloads, the least recently rolled up first
+	order      *list.List
+	count      int
+	maxEntries int
+
+	path string
+	file *os.File
+	// lines counts the records of the file, which is rewritten with the records of the index once
+	// it holds twice its maximum
+	lines int
+}
+
+// NewIndex returns an index held in memory only, holding at most maxEntries receipts or
+// DefaultMaxEntries when it isn't positive.
+func NewIndex(maxEntries int) *Index {
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+	return &Index{
+		items:       make(map[string]*item),
+		byKeccak256: make(map[string]string),
+		order:       list.New(),
+		maxEntries:  maxEntries,
+	}
+}
+
+// OpenIndex loads the index file at path and appends the new entries to it, the index is held in
+// memory only when path is empty. The file is rewritten without the receipts the index dropped.
+func OpenIndex(path string, maxEntries int) (*Index, error) {
+	x := NewIndex(maxEntries)
+	if len(path) == 0 {
+		return x, nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open content index: %w", err)
+	}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		x.lines++
+		var rec record
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		// keep the receipt a number when it is one, e.g. a celestia height
+		decoder.UseNumber()
+		if err := decoder.Decode(&rec); err != nil {
+			// a crash may leave the last line partly written
+			log.Warn("skipping invalid content index line", "file", path, "line", line, "err", err)
+			continue
+		}
+		if n, ok := rec.Receipt.(json.Number); ok {
+			if height, err := n.Int64(); err == nil && height >= 0 {
+				rec.Receipt = uint64(height)
+			}
+		}
+		x.add(rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Join(fmt.Errorf("read content index: %w", err), file.Close())
+	}
+	x.path = path
+	x.file = file
+	if x.lines > x.count {
+		if err := x.compactLocked(); err != nil {
+			return nil, errors.Join(err, x.file.Close())
+		}
+	}
+	return x, nil
+}
+
+func (x *Index) add(rec record) {
+	it := x.items[rec.SHA256]
+	if it == nil {
+		it = &item{sha256: rec.SHA256, keccak256: rec.Keccak256}
+		it.elem = x.order.PushBack(it)
+		x.items[rec.SHA256] = it
+		x.byKeccak256[rec.Keccak256] = rec.SHA256
+	} else {
+		x.order.MoveToBack(it.elem)
+	}
+	// a receipt returned again becomes the newest
+	for i, entry := range it.entries {
+		if entry.DAType == rec.DAType && reflect.DeepEqual(entry.Receipt, rec.Receipt) {
+			it.entries = append(it.entries[:i], it.entries[i+1:]...)
+			x.count--
+			break
+		}
+	}
+	it.entries = append(it.entries, rec.Entry)
+	x.count++
+
+	for x.count > x.maxEntries {
+		oldest := x.order.Front().Value.(*item)
+		if oldest == it {
+			// the payload is the only one left
+			it.entries = it.entries[1:]
+			x.count--
+			continue
+		}
+		x.order.Remove(oldest.elem)
+		delete(x.items, oldest.sha256)
+		delete(x.byKeccak256, oldest.keccak256)
+		x.count -= len(oldest.entries)
+	}
+}
+
+// Add records the receipt returned for data.
+func (x *Index) Add(data []byte, daType int, receipt interface{}) error {
+	sha := sha256.Sum256(data)
+	rec := record{
+		SHA256:    hex.EncodeToString(sha[:]),
+		Keccak256: hex.EncodeToString(crypto.Keccak256(data)),
+		Entry:     Entry{DAType: daType, Receipt: receipt, Time: time.Now().UTC()},
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.add(rec)
+	if x.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := x.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	x.lines++
+	if x.lines > 2*x.maxEntries {
+		return x.compactLocked()
+	}
+	return nil
+}
+
+// compactLocked rewrites the index file with the records of the index, the oldest first so that
+// loading it gives back the same index.
+func (x *Index) compactLocked() error {
+	tmp := x.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("compact content index: %w", err)
+	}
+	w := bufio.NewWriter(file)
+	lines := 0
+	for elem := x.order.Front(); elem != nil; elem = elem.Next() {
+		it := elem.Value.(*item)
+		for _, entry := range it.entries {
+			line, err := json.Marshal(record{SHA256: it.sha256, Keccak256: it.keccak256, Entry: entry})
+			if err == nil {
+				_, err = w.Write(append(line, '\n'))
+			}
+			if err != nil {
+				return errors.Join(fmt.Errorf("compact content index: %w", err), file.Close(), os.Remove(tmp))
+			}
+			lines++
+		}
+	}
+	if err := errors.Join(w.Flush(), file.Sync(), file.Close()); err != nil {
+		return errors.Join(fmt.Errorf("compact content index: %w", err), os.Remove(tmp))
+	}
+	if err := os.Rename(tmp, x.path); err != nil {
+		return errors.Join(fmt.Errorf("compact content index: %w", err), os.Remove(tmp))
+	}
+
+	// the old file was replaced, new records go to the compacted one
+	compacted, err := os.OpenFile(x.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("reopen content index: %w", err)
+	}
+	err = x.file.Close()
+	x.file = compacted
+	x.lines = lines
+	if err != nil {
+		log.Warn("failed to close compacted content index", "file", x.path, "err", err)
+	}
+	return nil
+}
+
+// Lookup returns the receipts of the payload with the sha256 or keccak256 hash, the newest first.
+func (x *Index) Lookup(hash []byte) []Entry {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	key := hex.EncodeToString(hash)
+	if sha, ok := x.byKeccak256[key]; ok {
+		key = sha
+	}
+	it := x.items[key]
+	if it == nil {
+		return []Entry{}
+	}
+	out := make([]Entry, len(it.entries))
+	for i, entry := range it.entries {
+		out[len(it.entries)-1-i] = entry
+	}
+	return out
+}
+
+// Close closes the index file.
+func (x *Index) Close() error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.file == nil {
+		return nil
+	}
+	err := x.file.Close()
+	x.file = nil
+	return err
+}
+
+// ParseHash decodes a payload hash, with or without its 0x prefix.
+func ParseHash(s string) ([]byte, error) {
+	hash, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(hash) != 32 {
+		return nil, ErrInvalidHash
+	}
+	return hash, nil
+}
+
+// Matches reports whether hash is the sha256 or the keccak256 of data.
+func Matches(data, hash []byte) bool {
+	sha := sha256.Sum256(data)
+	return bytes.Equal(sha[:], hash) || bytes.Equal(crypto.Keccak256(data), hash)
+}
@@ -0,0 +1,134 @@
+package content
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestIndexLookup(t *testing.T) {
+	x := NewIndex(0)
+	data := []byte("rollup batch")
+	require.NoError(t, x.Add(data, _common.CelestiaType, "12:deadbeef:0a0b:100utia"))
+	require.NoError(t, x.Add(data, _common.EigenDAType, "AAEC"))
+	require.NoError(t, x.Add([]byte("other batch"), _common.EigenDAType, "AAED"))
+
+	sha := sha256.Sum256(data)
+	for _, hash := range [][]byte{sha[:], crypto.Keccak256(data)} {
+		entries := x.Lookup(hash)
+		require.Len(t, entries, 2)
+		// the newest first
+		require.Equal(t, _common.EigenDAType, entries[0].DAType)
+		require.Equal(t, "AAEC", entries[0].Receipt)
+		require.Equal(t, _common.CelestiaType, entries[1].DAType)
+	}
+	require.Empty(t, x.Lookup(make([]byte, 32)))
+	require.NoError(t, x.Close())
+}
+
+func TestIndexFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content.jsonl")
+	x, err := OpenIndex(path, 0)
+	require.NoError(t, err)
+	data := []byte("rollup batch")
+	require.NoError(t, x.Add(data, _common.CelestiaType, uint64(12)))
+	require.NoError(t, x.Add(data, _common.Eip4844Type, "0x0a0b"))
+	require.NoError(t, x.Close())
+
+	// a crash left the last line partly written
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"sha256":"`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	x, err = OpenIndex(path, 0)
+	require.NoError(t, err)
+	defer x.Close()
+	entries := x.Lookup(crypto.Keccak256(data))
+	require.Len(t, entries, 2)
+	require.Equal(t, "0x0a0b", entries[0].Receipt)
+	require.Equal(t, uint64(12), entries[1].Receipt)
+}
+
+func TestIndexDedupAndCap(t *testing.T) {
+	x := NewIndex(3)
+	first, second := []byte("first batch"), []byte("second batch")
+	require.NoError(t, x.Add(first, _common.EigenDAType, "AAEC"))
+	require.NoError(t, x.Add(first, _common.CelestiaType, uint64(12)))
+	// the same receipt is held once, as the newest
+	require.NoError(t, x.Add(first, _common.EigenDAType, "AAEC"))
+	entries := x.Lookup(crypto.Keccak256(first))
+	require.Len(t, entries, 2)
+	require.Equal(t, "AAEC", entries[0].Receipt)
+	require.Equal(t, uint64(12), entries[1].Receipt)
+
+	// the payload rolled up the longest ago goes first
+	require.NoError(t, x.Add(second, _common.EigenDAType, "AAED"))
+	require.NoError(t, x.Add(second, _common.NearDAType, "AAEE"))
+	require.Empty(t, x.Lookup(crypto.Keccak256(first)))
+	sha := sha256.Sum256(first)
+	require.Empty(t, x.Lookup(sha[:]))
+	require.Len(t, x.Lookup(crypto.Keccak256(second)), 2)
+
+	// a payload with more receipts than the maximum keeps the newest
+	for _, receipt := range []string{"AAEF", "AAF0"} {
+		require.NoError(t, x.Add(second, _common.EigenDAType, receipt))
+	}
+	entries = x.Lookup(crypto.Keccak256(second))
+	require.Len(t, entries, 3)
+	require.Equal(t, "AAF0", entries[0].Receipt)
+	require.Equal(t, "AAEE", entries[2].Receipt)
+}
+
+func TestIndexCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content.jsonl")
+	x, err := OpenIndex(path, 2)
+	require.NoError(t, err)
+	data := []byte("rollup batch")
+	for i := 0; i < 5; i++ {
+		require.NoError(t, x.Add(data, _common.EigenDAType, "AAEC"))
+	}
+	// the fifth record is above twice the maximum, the file is rewritten with the single receipt
+	require.Equal(t, 1, x.lines)
+	require.NoError(t, x.Add([]byte("other batch"), _common.EigenDAType, "AAED"))
+	require.NoError(t, x.Add([]byte("third batch"), _common.EigenDAType, "AAEE"))
+	require.NoError(t, x.Close())
+
+	// loading drops the records of the payloads above the maximum
+	x, err = OpenIndex(path, 2)
+	require.NoError(t, err)
+	defer x.Close()
+	require.Empty(t, x.Lookup(crypto.Keccak256(data)))
+	require.Len(t, x.Lookup(crypto.Keccak256([]byte("other batch"))), 1)
+	require.Len(t, x.Lookup(crypto.Keccak256([]byte("third batch"))), 1)
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, bytes.Count(raw, []byte("\n")))
+}
+
+func TestParseHash(t *testing.T) {
+	data := []byte("rollup batch")
+	sha := sha256.Sum256(data)
+	for _, s := range []string{hex.EncodeToString(sha[:]), "0x" + hex.EncodeToString(sha[:])} {
+		hash, err := ParseHash(s)
+		require.NoError(t, err)
+		require.Equal(t, sha[:], hash)
+		require.True(t, Matches(data, hash))
+	}
+	require.True(t, Matches(data, crypto.Keccak256(data)))
+	require.False(t, Matches([]byte("other batch"), sha[:]))
+
+	for _, invalid := range []string{"", "0x0a0b", "not hex"} {
+		_, err := ParseHash(invalid)
+		require.ErrorIs(t, err, ErrInvalidHash)
+	}
+}
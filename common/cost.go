@@ -0,0 +1,15 @@
+package common
+
+// CostEstimate is what storing a payload of Size bytes on a DA is expected to cost at current prices.
+type CostEstimate struct {
+	DAType int `json:"da_type"`
+	Size   int `json:"size"`
+	// Amount is a decimal number of the smallest unit of Token, e.g. wei or utia
+	Amount   string `json:"amount"`
+	Token    string `json:"token"`
+	Decimals int    `json:"decimals"`
+	// Fiat is the value of Amount in Currency, both are empty unless a price source is configured
+	Fiat     float64           `json:"fiat,omitempty"`
+	Currency string            `json:"currency,omitempty"`
+	Detail   map[string]string `json:"detail,omitempty"`
+}
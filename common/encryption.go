@@ -0,0 +1,38 @@
+package common
+
+import "context"
+
+type (
+	keyIDKey     struct{}
+	authTokenKey struct{}
+)
+
+// WithKeyID returns a copy of ctx carrying the id of the keyring key a rollup request is encrypted
+// with, overriding the default key.
+func WithKeyID(ctx context.Context, keyID string) context.Context {
+	if len(keyID) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, keyIDKey{}, keyID)
+}
+
+// KeyIDFromContext returns the key id set by WithKeyID, empty for the default key.
+func KeyIDFromContext(ctx context.Context) string {
+	keyID, _ := ctx.Value(keyIDKey{}).(string)
+	return keyID
+}
+
+// WithAuthToken returns a copy of ctx carrying the token a caller presents to retrieve the
+// plaintext of encrypted payloads.
+func WithAuthToken(ctx context.Context, token string) context.Context {
+	if len(token) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, authTokenKey{}, token)
+}
+
+// AuthTokenFromContext returns the token set by WithAuthToken.
+func AuthTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(authTokenKey{}).(string)
+	return token
+}
@@ -0,0 +1,244 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Algorithm is an AEAD payloads are sealed with.
+type Algorithm byte
+
+const (
+	AES256GCM         Algorithm = 1
+	XChaCha20Poly1305 Algorithm = 2
+)
+
+var algorithmNames = map[Algorithm]string{
+	AES256GCM:         "aes-256-gcm",
+	XChaCha20Poly1305: "xchacha20-poly1305",
+}
+
+func (a Algorithm) String() string {
+	if name, ok := algorithmNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", byte(a))
+}
+
+// ParseAlgorithm parses an algorithm name, aes-256-gcm when empty.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	if len(name) == 0 {
+		return AES256GCM, nil
+	}
+	for alg, algName := range algorithmNames {
+		if strings.EqualFold(name, algName) {
+			return alg, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown algorithm %q, expected aes-256-gcm or xchacha20-poly1305", name)
+}
+
+// Version is the envelope version written by Seal.
+const Version = 1
+
+// Magic is the first byte of a sealed payload. Its high nibble is no codec id, so a sealed payload
+// is never mistaken for a compressed one.
+const Magic = 0xe0 | Version
+
+// KeySize is the size of the keys of every algorithm.
+const KeySize = 32
+
+var (
+	ErrUnknownKey   = errors.New("unknown encryption key")
+	ErrUnauthorized = errors.New("caller is not allowed to decrypt with this key")
+	ErrDecrypt      = errors.New("decrypting payload failed")
+)
+
+// KeyConfig is a key of the keyring file.
+type KeyConfig struct {
+	ID string `json:"id"`
+	// Algorithm is aes-256-gcm or xchacha20-poly1305, aes-256-gcm when empty
+	Algorithm string `json:"algorithm"`
+	// Key is the hex of 32 bytes
+	Key string `json:"key"`
+	// Readers are the bearer tokens of the callers allowed to retrieve plaintext, every caller
+	// is when empty
+	Readers []string `json:"readers"`
+}
+
+// KeyringFile is the layout of the keyring file.
+type KeyringFile struct {
+	Keys []KeyConfig `json:"keys"`
+}
+
+type entry struct {
+	id        string
+	algorithm Algorithm
+	aead      cipher.AEAD
+	readers   [][]byte
+}
+
+// authorized reports whether a caller presenting token may read plaintext sealed with e.
+func (e *entry) authorized(token string) bool {
+	if len(e.readers) == 0 {
+		return true
+	}
+	for _, reader := range e.readers {
+		if subtle.ConstantTimeCompare(reader, []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// Keyring holds the keys payloads are sealed with, it is safe for concurrent use.
+type Keyring struct {
+	keys map[string]*entry
+}
+
+// LoadKeyring reads the JSON keyring file at path.
+func LoadKeyring(path string) (*Keyring, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keyring: %w", err)
+	}
+	var file KeyringFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("decode keyring %s: %w", path, err)
+	}
+	return NewKeyring(file)
+}
+
+// NewKeyring checks and builds the keys of file.
+func NewKeyring(file KeyringFile) (*Keyring, error) {
+	k := &Keyring{keys: make(map[string]*entry, len(file.Keys))}
+	for i, conf := range file.Keys {
+		if len(conf.ID) == 0 || len(conf.ID) > 255 {
+			return nil, fmt.Errorf("key %d: id must be 1 to 255 bytes", i)
+		}
+		if _, ok := k.keys[conf.ID]; ok {
+			return nil, fmt.Errorf("key %s: duplicate id", conf.ID)
+		}
+		alg, err := ParseAlgorithm(conf.Algorithm)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", conf.ID, err)
+		}
+		secret, err := hex.DecodeString(strings.TrimPrefix(conf.Key, "0x"))
+		if err != nil || len(secret) != KeySize {
+			return nil, fmt.Errorf("key %s: key must be the hex of %d bytes", conf.ID, KeySize)
+		}
+		aead, err := newAEAD(alg, secret)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", conf.ID, err)
+		}
+		readers := make([][]byte, len(conf.Readers))
+		for j, reader := range conf.Readers {
+			readers[j] = []byte(reader)
+		}
+		k.keys[conf.ID] = &entry{id: conf.ID, algorithm: alg, aead: aead, readers: readers}
+	}
+	return k, nil
+}
+
+func newAEAD(alg Algorithm, secret []byte) (cipher.AEAD, error) {
+	switch alg {
+	case AES256GCM:
+		block, err := aes.NewCipher(secret)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case XChaCha20Poly1305:
+		return chacha20poly1305.NewX(secret)
+	}
+	return nil, fmt.Errorf("unknown algorithm %d", alg)
+}
+
+// Has reports whether the keyring holds the key id.
+func (k *Keyring) Has(id string) bool {
+	if k == nil {
+		return false
+	}
+	_, ok := k.keys[id]
+	return ok
+}
+
+// Seal encrypts plaintext with the key id. The envelope is the magic byte, the algorithm, the
+// length of the key id, the key id, the nonce and the ciphertext; everything before the nonce is
+// authenticated along with the ciphertext.
+func (k *Keyring) Seal(id string, plaintext []byte) ([]byte, error) {
+	if !k.Has(id) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+	key := k.keys[id]
+	header := append([]byte{Magic, byte(key.algorithm), byte(len(id))}, id...)
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(header)+len(nonce)+len(plaintext)+key.aead.Overhead())
+	out = append(append(out, header...), nonce...)
+	return key.aead.Seal(out, nonce, plaintext, header), nil
+}
+
+// Envelope is the header of a sealed payload.
+type Envelope struct {
+	Algorithm Algorithm
+	KeyID     string
+	header    []byte
+	body      []byte
+}
+
+// Parse returns the envelope of a sealed payload, ok is false for anything else.
+func Parse(data []byte) (env *Envelope, ok bool) {
+	if len(data) < 3 || data[0] != Magic {
+		return nil, false
+	}
+	alg := Algorithm(data[1])
+	if _, known := algorithmNames[alg]; !known {
+		return nil, false
+	}
+	end := 3 + int(data[2])
+	if data[2] == 0 || len(data) < end {
+		return nil, false
+	}
+	return &Envelope{Algorithm: alg, KeyID: string(data[3:end]), header: data[:end], body: data[end:]}, true
+}
+
+// Open decrypts a payload sealed by Seal for a caller presenting token. Payloads without a sealed
+// envelope were stored in clear and are returned as is.
+func (k *Keyring) Open(data []byte, token string) ([]byte, error) {
+	env, ok := Parse(data)
+	if !ok {
+		return data, nil
+	}
+	if !k.Has(env.KeyID) {
+		return nil, fmt.Errorf("%w %q", ErrUnknownKey, env.KeyID)
+	}
+	key := k.keys[env.KeyID]
+	if !key.authorized(token) {
+		return nil, fmt.Errorf("%w %q", ErrUnauthorized, env.KeyID)
+	}
+	if key.algorithm != env.Algorithm {
+		return nil, fmt.Errorf("%w: sealed with %s, key %s is %s", ErrDecrypt, env.Algorithm, env.KeyID, key.algorithm)
+	}
+	nonceSize := key.aead.NonceSize()
+	if len(env.body) < nonceSize+key.aead.Overhead() {
+		return nil, fmt.Errorf("%w: payload too short", ErrDecrypt)
+	}
+	plaintext, err := key.aead.Open(nil, env.body[:nonceSize], env.body[nonceSize:], env.header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return plaintext, nil
+}
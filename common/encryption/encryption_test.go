@@ -0,0 +1,129 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T) *Keyring {
+	keyring, err := NewKeyring(KeyringFile{Keys: []KeyConfig{
+		{ID: "appchain_a", Key: strings.Repeat("0a", KeySize), Readers: []string{"token-a"}},
+		{ID: "appchain_b", Algorithm: "xchacha20-poly1305", Key: "0x" + strings.Repeat("0b", KeySize)},
+	}})
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestSealOpen(t *testing.T) {
+	keyring := testKeyring(t)
+	plaintext := []byte("private appchain batch")
+
+	for id, alg := range map[string]Algorithm{"appchain_a": AES256GCM, "appchain_b": XChaCha20Poly1305} {
+		sealed, err := keyring.Seal(id, plaintext)
+		require.NoError(t, err)
+		require.False(t, bytes.Contains(sealed, plaintext))
+
+		env, ok := Parse(sealed)
+		require.True(t, ok)
+		require.Equal(t, id, env.KeyID)
+		require.Equal(t, alg, env.Algorithm)
+
+		opened, err := keyring.Open(sealed, "token-a")
+		require.NoError(t, err)
+		require.Equal(t, plaintext, opened)
+	}
+
+	// a fresh nonce every time
+	first, err := keyring.Seal("appchain_a", plaintext)
+	require.NoError(t, err)
+	second, err := keyring.Seal("appchain_a", plaintext)
+	require.NoError(t, err)
+	require.NotEqual(t, first, second)
+}
+
+func TestOpenChecksCaller(t *testing.T) {
+	keyring := testKeyring(t)
+	sealed, err := keyring.Seal("appchain_a", []byte("private appchain batch"))
+	require.NoError(t, err)
+
+	_, err = keyring.Open(sealed, "")
+	require.ErrorIs(t, err, ErrUnauthorized)
+	_, err = keyring.Open(sealed, "token-b")
+	require.ErrorIs(t, err, ErrUnauthorized)
+
+	// a node without the key can't open it
+	other, err := NewKeyring(KeyringFile{})
+	require.NoError(t, err)
+	_, err = other.Open(sealed, "token-a")
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = (*Keyring)(nil).Open(sealed, "token-a")
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestOpenRejectsTampering(t *testing.T) {
+	keyring := testKeyring(t)
+	sealed, err := keyring.Seal("appchain_a", []byte("private appchain batch"))
+	require.NoError(t, err)
+
+	body := append([]byte{}, sealed...)
+	body[len(body)-1] ^= 1
+	_, err = keyring.Open(body, "token-a")
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	// the key id is authenticated, moving the ciphertext under another key fails
+	moved := append([]byte{Magic, byte(XChaCha20Poly1305), byte(len("appchain_b"))}, "appchain_b"...)
+	moved = append(moved, sealed[3+len("appchain_a"):]...)
+	_, err = keyring.Open(moved, "")
+	require.ErrorIs(t, err, ErrDecrypt)
+
+	_, err = keyring.Open(sealed[:len(sealed)-20], "token-a")
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestOpenClear(t *testing.T) {
+	keyring := testKeyring(t)
+	for _, data := range [][]byte{
+		nil,
+		[]byte("plain rollup batch"),
+		// payloads without a valid sealed envelope were stored in clear
+		{Magic, 0x09, 0x01, 'a'},
+		{Magic, byte(AES256GCM), 0x00},
+		{Magic, byte(AES256GCM), 0x05, 'a'},
+	} {
+		opened, err := keyring.Open(data, "")
+		require.NoError(t, err)
+		require.Equal(t, data, opened)
+	}
+
+	// a sealed envelope naming a key the keyring lacks
+	_, err := keyring.Open(append([]byte{Magic, byte(AES256GCM), 0x01}, "zz"...), "")
+	require.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestLoadKeyring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	raw, err := json.Marshal(KeyringFile{Keys: []KeyConfig{{ID: "appchain_a", Key: strings.Repeat("0a", KeySize)}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, raw, 0o600))
+
+	keyring, err := LoadKeyring(path)
+	require.NoError(t, err)
+	require.True(t, keyring.Has("appchain_a"))
+	require.False(t, keyring.Has("appchain_b"))
+
+	for _, keys := range [][]KeyConfig{
+		{{ID: "", Key: strings.Repeat("0a", KeySize)}},
+		{{ID: "a", Key: "0a0a"}},
+		{{ID: "a", Key: strings.Repeat("0a", KeySize), Algorithm: "des"}},
+		{{ID: "a", Key: strings.Repeat("0a", KeySize)}, {ID: "a", Key: strings.Repeat("0b", KeySize)}},
+	} {
+		_, err := NewKeyring(KeyringFile{Keys: keys})
+		require.Error(t, err)
+	}
+}
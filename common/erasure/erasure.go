@@ -0,0 +1,239 @@
+package erasure
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/reedsolomon"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// Version is the version of the receipts.
+const Version = 1
+
+// ReceiptPrefix starts the receipt of an erasure coded submission.
+const ReceiptPrefix = "erasure1:"
+
+var (
+	ErrNotEnoughShards = errors.New("not enough shards to reconstruct the payload")
+	ErrShardMismatch   = errors.New("shard does not match the receipt")
+)
+
+// Config of the erasure coding of the payloads submitted with the erasure da type.
+type Config struct {
+	// DataShards is the number of shards the payload is split in, any DataShards shards rebuild it
+	DataShards int `mapstructure:"data_shards"`
+	// ParityShards is the number of shards added to the data ones
+	ParityShards int `mapstructure:"parity_shards"`
+	// DAs lists the names or da types the shards are spread over, shard i going to DAs[i % len(DAs)].
+	// Erasure coding is disabled when empty.
+	DAs []string `mapstructure:"das"`
+}
+
+func DefaultConfig() Config {
+	return Config{DataShards: 4, ParityShards: 2}
+}
+
+// Enabled reports whether payloads can be submitted with the erasure da type.
+func (c Config) Enabled() bool {
+	return len(c.DAs) != 0
+}
+
+func (c Config) Check() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.DataShards <= 0 || c.ParityShards <= 0 {
+		return errors.New("data_shards and parity_shards must be positive")
+	}
+	if c.DataShards+c.ParityShards > 256 {
+		return errors.New("data_shards and parity_shards add up to 256 at most")
+	}
+	if len(c.DAs) > c.DataShards+c.ParityShards {
+		return fmt.Errorf("das lists %d DAs for %d shards", len(c.DAs), c.DataShards+c.ParityShards)
+	}
+	if _, err := c.ShardDATypes(); err != nil {
+		return err
+	}
+	if c.Tolerance() == 0 {
+		return errors.New("losing a single DA loses more than parity_shards shards, list more das or add parity shards")
+	}
+	return nil
+}
+
+// ShardDATypes returns the da type storing every shard.
+func (c Config) ShardDATypes() ([]int, error) {
+	daTypes := make([]int, c.DataShards+c.ParityShards)
+	for i := range daTypes {
+		daType, err := _common.ParseDAType(c.DAs[i%len(c.DAs)])
+		if err != nil {
+			return nil, err
+		}
+		if daType == _common.ErasureType {
+			return nil, errors.New("shards must be stored on DA backends")
+		}
+		daTypes[i] = daType
+	}
+	return daTypes, nil
+}
+
+// Tolerance is the number of DAs whose loss still leaves enough shards to reconstruct payloads.
+func (c Config) Tolerance() int {
+	daTypes, err := c.ShardDATypes()
+	if err != nil {
+		return 0
+	}
+	counts := make(map[int]int)
+	for _, daType := range daTypes {
+		counts[daType]++
+	}
+	perDA := make([]int, 0, len(counts))
+	for _, count := range counts {
+		perDA = append(perDA, count)
+	}
+	// the worst case loses the DAs holding the most shards
+	sort.Sort(sort.Reverse(sort.IntSlice(perDA)))
+	lost, tolerance := 0, 0
+	for _, count := range perDA {
+		if lost += count; lost > c.ParityShards {
+			break
+		}
+		tolerance++
+	}
+	return tolerance
+}
+
+// Encode splits data in dataShards shards of equal size and appends parityShards parity shards.
+func Encode(data []byte, dataShards, parityShards int) ([][]byte, error) {
+	enc, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := enc.Split(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(shards); err != nil {
+		return nil, err
+	}
+	return shards, nil
+}
+
+// Shard locates a shard on the DA storing it.
+type Shard struct {
+	DAType int `json:"da_type"`
+	// DAArgs retrieves the shard from the DA, as the DA's own receipt would
+	DAArgs interface{} `json:"da_args"`
+	Hash   []byte      `json:"hash"`
+}
+
+// Receipt lists the location of every shard of a payload, data shards first.
+type Receipt struct {
+	DataShards   int     `json:"data_shards"`
+	ParityShards int     `json:"parity_shards"`
+	Size         int     `json:"size"`
+	Hash         []byte  `json:"hash"`
+	Shards       []Shard `json:"shards"`
+}
+
+// NewReceipt describes the shards of data, their locations are set once they are stored.
+func NewReceipt(data []byte, shards [][]byte, dataShards, parityShards int) *Receipt {
+	hash := sha256.Sum256(data)
+	r := &Receipt{DataShards: dataShards, ParityShards: parityShards, Size: len(data), Hash: hash[:], Shards: make([]Shard, len(shards))}
+	for i, shard := range shards {
+		hash := sha256.Sum256(shard)
+		r.Shards[i].Hash = hash[:]
+	}
+	return r
+}
+
+// String encodes the receipt as ReceiptPrefix followed by its base64 json.
+func (r *Receipt) String() string {
+	raw, _ := json.Marshal(r)
+	return ReceiptPrefix + base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// IsReceipt reports whether args is the receipt of an erasure coded submission.
+func IsReceipt(args interface{}) bool {
+	s, ok := args.(string)
+	return ok && strings.HasPrefix(s, ReceiptPrefix)
+}
+
+// ParseReceipt decodes a receipt written by Receipt.String.
+func ParseReceipt(s string) (*Receipt, error) {
+	encoded, ok := strings.CutPrefix(strings.TrimSpace(s), ReceiptPrefix)
+	if !ok {
+		return nil, fmt.Errorf("erasure receipt must start with %s", ReceiptPrefix)
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode erasure receipt: %w", err)
+	}
+	r := &Receipt{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// keep the da args a number when it is one, e.g. a celestia height
+	decoder.UseNumber()
+	if err := decoder.Decode(r); err != nil {
+		return nil, fmt.Errorf("decode erasure receipt: %w", err)
+	}
+	if r.DataShards <= 0 || r.ParityShards <= 0 || len(r.Shards) != r.DataShards+r.ParityShards || len(r.Hash) != sha256.Size {
+		return nil, errors.New("erasure receipt doesn't list its shards")
+	}
+	for i := range r.Shards {
+		if n, ok := r.Shards[i].DAArgs.(json.Number); ok {
+			height, err := n.Int64()
+			if err != nil || height < 0 {
+				return nil, fmt.Errorf("erasure receipt shard %d da args %s is no height", i, n)
+			}
+			r.Shards[i].DAArgs = uint64(height)
+		}
+	}
+	return r, nil
+}
+
+// CheckShard verifies the i-th shard against the receipt.
+func (r *Receipt) CheckShard(i int, shard []byte) error {
+	if hash := sha256.Sum256(shard); !bytes.Equal(hash[:], r.Shards[i].Hash) {
+		return fmt.Errorf("%w: shard %d", ErrShardMismatch, i)
+	}
+	return nil
+}
+
+// Reconstruct rebuilds the payload out of the shards, nil for the missing ones, and checks its hash.
+// The shards are expected to be checked already.
+func (r *Receipt) Reconstruct(shards [][]byte) ([]byte, error) {
+	if len(shards) != len(r.Shards) {
+		return nil, fmt.Errorf("%w: %d shards, the receipt lists %d", ErrShardMismatch, len(shards), len(r.Shards))
+	}
+	present := 0
+	for _, shard := range shards {
+		if shard != nil {
+			present++
+		}
+	}
+	if present < r.DataShards {
+		return nil, fmt.Errorf("%w: %d of %d", ErrNotEnoughShards, present, r.DataShards)
+	}
+	enc, err := reedsolomon.New(r.DataShards, r.ParityShards)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.ReconstructData(shards); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrShardMismatch, err)
+	}
+	var data bytes.Buffer
+	if err := enc.Join(&data, shards, r.Size); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrShardMismatch, err)
+	}
+	if hash := sha256.Sum256(data.Bytes()); !bytes.Equal(hash[:], r.Hash) {
+		return nil, fmt.Errorf("%w: payload hash", ErrShardMismatch)
+	}
+	return data.Bytes(), nil
+}
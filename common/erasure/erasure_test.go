@@ -0,0 +1,98 @@
+package erasure
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestReconstruct(t *testing.T) {
+	data := bytes.Repeat([]byte("rollup batch "), 100)
+	shards, err := Encode(data, 4, 2)
+	require.NoError(t, err)
+	require.Len(t, shards, 6)
+	receipt := NewReceipt(data, shards, 4, 2)
+	for i, shard := range shards {
+		require.NoError(t, receipt.CheckShard(i, shard))
+	}
+
+	// any two shards may be lost
+	for _, lost := range [][]int{{}, {0, 1}, {2, 5}, {4, 5}, {3}} {
+		available := make([][]byte, len(shards))
+		copy(available, shards)
+		for _, i := range lost {
+			available[i] = nil
+		}
+		rebuilt, err := receipt.Reconstruct(available)
+		require.NoError(t, err)
+		require.Equal(t, data, rebuilt)
+	}
+
+	available := make([][]byte, len(shards))
+	copy(available, shards)
+	available[0], available[1], available[2] = nil, nil, nil
+	_, err = receipt.Reconstruct(available)
+	require.ErrorIs(t, err, ErrNotEnoughShards)
+
+	tampered := append([]byte{}, shards[1]...)
+	tampered[0] ^= 1
+	require.ErrorIs(t, receipt.CheckShard(1, tampered), ErrShardMismatch)
+	_, err = receipt.Reconstruct([][]byte{shards[0], tampered, shards[2], shards[3], nil, nil})
+	require.ErrorIs(t, err, ErrShardMismatch)
+}
+
+func TestReceiptRoundTrip(t *testing.T) {
+	data := []byte("rollup batch")
+	shards, err := Encode(data, 2, 1)
+	require.NoError(t, err)
+	receipt := NewReceipt(data, shards, 2, 1)
+	receipt.Shards[0] = Shard{DAType: _common.CelestiaType, DAArgs: uint64(12), Hash: receipt.Shards[0].Hash}
+	receipt.Shards[1] = Shard{DAType: _common.EigenDAType, DAArgs: "AAEC", Hash: receipt.Shards[1].Hash}
+	receipt.Shards[2] = Shard{DAType: _common.Eip4844Type, DAArgs: "0x0a0b", Hash: receipt.Shards[2].Hash}
+
+	s := receipt.String()
+	require.True(t, IsReceipt(s))
+	parsed, err := ParseReceipt(s)
+	require.NoError(t, err)
+	require.Equal(t, receipt, parsed)
+
+	receipt.Shards = receipt.Shards[:2]
+	for _, invalid := range []string{"0x0a0b", ReceiptPrefix + "!", ReceiptPrefix + "e30", receipt.String()} {
+		_, err := ParseReceipt(invalid)
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestConfigTolerance(t *testing.T) {
+	conf := DefaultConfig()
+	require.False(t, conf.Enabled())
+	require.NoError(t, conf.Check())
+
+	for _, c := range []struct {
+		das       []string
+		tolerance int
+	}{
+		{[]string{"celestia", "eigenda", "eip4844", "nearda", "anytrust", "anytrust-das-committee"}, 2},
+		{[]string{"celestia", "eigenda", "eip4844"}, 1},
+		{[]string{"celestia", "eigenda"}, 0},
+	} {
+		conf.DAs = c.das
+		require.Equal(t, c.tolerance, conf.Tolerance(), c.das)
+		if c.tolerance == 0 {
+			require.Error(t, conf.Check())
+		} else {
+			require.NoError(t, conf.Check())
+		}
+	}
+
+	for _, das := range [][]string{{"erasure", "celestia", "eigenda"}, {"avail", "celestia", "eigenda"}} {
+		conf.DAs = das
+		require.Error(t, conf.Check())
+	}
+	conf.DAs = []string{"celestia", "eigenda", "eip4844"}
+	conf.ParityShards = 0
+	require.Error(t, conf.Check())
+}
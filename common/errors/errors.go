@@ -3,21 +3,31 @@ package errors
 import "errors"
 
 const (
-	UnknownDATypeErrMsg   = "Rollup with unknown da type"
-	DANotPreparedErrMsg   = "DA not prepared"
-	WrongArgsNumberErrMsg = "Number of args is wrong"
-	RollupFailedMsg       = "Rollup into DA failed"
-	GetFromDAErrMsg       = "Get from DA failed"
-	WrongArgTypeErrMsg    = "Arg with wrong type"
-	NilPointerErrMsg      = "got nil pointer"
+	UnknownDATypeErrMsg        = "Rollup with unknown da type"
+	DANotPreparedErrMsg        = "DA not prepared"
+	WrongArgsNumberErrMsg      = "Number of args is wrong"
+	RollupFailedMsg            = "Rollup into DA failed"
+	GetFromDAErrMsg            = "Get from DA failed"
+	WrongArgTypeErrMsg         = "Arg with wrong type"
+	NilPointerErrMsg           = "got nil pointer"
+	ShuttingDownErrMsg         = "Rollup node is shutting down"
+	StatusNotTrackedMsg        = "Status is only tracked for eigenda and eip4844, other DAs store the data before returning the receipt"
+	ProofNotSupportedMsg       = "Inclusion proofs are only served for celestia"
+	AttestationNotSupportedMsg = "L1 attestations are only served for celestia, through blobstream"
+	CostNotSupportedMsg        = "Costs are estimated per DA, erasure coded data is paid on several DAs in different tokens"
 )
 
 var (
-	UnknownDATypeErr   = errors.New(UnknownDATypeErrMsg)
-	DANotPreparedErr   = errors.New(DANotPreparedErrMsg)
-	WrongArgsNumberErr = errors.New(WrongArgsNumberErrMsg)
-	RollupFailedErr    = errors.New(RollupFailedMsg)
-	GetFromDAErr       = errors.New(GetFromDAErrMsg)
-	WrongArgTypeErr    = errors.New(WrongArgTypeErrMsg)
-	NilPointerErr      = errors.New(NilPointerErrMsg)
+	UnknownDATypeErr           = errors.New(UnknownDATypeErrMsg)
+	DANotPreparedErr           = errors.New(DANotPreparedErrMsg)
+	WrongArgsNumberErr         = errors.New(WrongArgsNumberErrMsg)
+	RollupFailedErr            = errors.New(RollupFailedMsg)
+	GetFromDAErr               = errors.New(GetFromDAErrMsg)
+	WrongArgTypeErr            = errors.New(WrongArgTypeErrMsg)
+	NilPointerErr              = errors.New(NilPointerErrMsg)
+	ShuttingDownErr            = errors.New(ShuttingDownErrMsg)
+	StatusNotTrackedErr        = errors.New(StatusNotTrackedMsg)
+	ProofNotSupportedErr       = errors.New(ProofNotSupportedMsg)
+	AttestationNotSupportedErr = errors.New(AttestationNotSupportedMsg)
+	CostNotSupportedErr        = errors.New(CostNotSupportedMsg)
 )
@@ -0,0 +1,313 @@
+// Package fault injects faults in the requests to the DA backends, so the behavior of a sequencer
+// facing misbehaving DAs can be rehearsed against a running node. The faults of every DA are set by
+// the [faults.<da>] sections of the config file and changed at runtime through /admin/faults.
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// The requests faults apply to.
+const (
+	OpRollup   = "rollup"
+	OpRetrieve = "retrieve"
+	OpStatus   = "status"
+)
+
+// The faults, as recorded by the faults metric.
+const (
+	KindLatency  = "latency"
+	KindError    = "error"
+	KindTimeout  = "timeout"
+	KindTruncate = "truncate"
+	KindCorrupt  = "corrupt"
+	KindPending  = "pending"
+)
+
+// DefaultTimeout bounds the requests hung by a timeout fault whose context has no earlier deadline.
+const DefaultTimeout = 30 * time.Second
+
+var (
+	ErrInjected = errors.New("injected fault")
+	ErrTimeout  = fmt.Errorf("%w: request timed out", context.DeadlineExceeded)
+)
+
+// Config is the faults of a DA, every rate is the fraction of the requests failing that way.
+type Config struct {
+	// Ops limits the faults to these requests, all of them when empty
+	Ops []string `mapstructure:"ops"`
+	// Latency and Jitter delay every request
+	Latency time.Duration `mapstructure:"latency"`
+	Jitter  time.Duration `mapstructure:"jitter"`
+	// ErrorRate fails requests with ErrInjected
+	ErrorRate float64 `mapstructure:"error_rate"`
+	// TimeoutRate hangs requests until their context ends, or Timeout
+	TimeoutRate float64       `mapstructure:"timeout_rate"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	// TruncateRate and CorruptRate cut short or flip a byte of the retrieved data
+	TruncateRate float64 `mapstructure:"truncate_rate"`
+	CorruptRate  float64 `mapstructure:"corrupt_rate"`
+	// Pending keeps the status of every submission pending
+	Pending bool `mapstructure:"pending"`
+}
+
+func (c Config) Check() error {
+	for _, op := range c.Ops {
+		if op != OpRollup && op != OpRetrieve && op != OpStatus {
+			return fmt.Errorf("unknown op %q, expected rollup, retrieve or status", op)
+		}
+	}
+	if c.Latency < 0 || c.Jitter < 0 || c.Timeout < 0 {
+		return errors.New("latency, jitter and timeout must not be negative")
+	}
+	for name, rate := range map[string]float64{
+		"error_rate":    c.ErrorRate,
+		"timeout_rate":  c.TimeoutRate,
+		"truncate_rate": c.TruncateRate,
+		"corrupt_rate":  c.CorruptRate,
+	} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("%s must be between 0 and 1", name)
+		}
+	}
+	return nil
+}
+
+func (c Config) applies(op string) bool {
+	return len(c.Ops) == 0 || slices.Contains(c.Ops, op)
+}
+
+// configJSON is the form of Config served by /admin/faults, with durations such as "1.5s".
+type configJSON struct {
+	Ops          []string `json:"ops,omitempty"`
+	Latency      string   `json:"latency,omitempty"`
+	Jitter       string   `json:"jitter,omitempty"`
+	ErrorRate    float64  `json:"error_rate,omitempty"`
+	TimeoutRate  float64  `json:"timeout_rate,omitempty"`
+	Timeout      string   `json:"timeout,omitempty"`
+	TruncateRate float64  `json:"truncate_rate,omitempty"`
+	CorruptRate  float64  `json:"corrupt_rate,omitempty"`
+	Pending      bool     `json:"pending,omitempty"`
+}
+
+func (c Config) MarshalJSON() ([]byte, error) {
+	duration := func(d time.Duration) string {
+		if d == 0 {
+			return ""
+		}
+		return d.String()
+	}
+	return json.Marshal(configJSON{
+		Ops:          c.Ops,
+		Latency:      duration(c.Latency),
+		Jitter:       duration(c.Jitter),
+		ErrorRate:    c.ErrorRate,
+		TimeoutRate:  c.TimeoutRate,
+		Timeout:      duration(c.Timeout),
+		TruncateRate: c.TruncateRate,
+		CorruptRate:  c.CorruptRate,
+		Pending:      c.Pending,
+	})
+}
+
+func (c *Config) UnmarshalJSON(data []byte) error {
+	var raw configJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	conf := Config{
+		Ops:          raw.Ops,
+		ErrorRate:    raw.ErrorRate,
+		TimeoutRate:  raw.TimeoutRate,
+		TruncateRate: raw.TruncateRate,
+		CorruptRate:  raw.CorruptRate,
+		Pending:      raw.Pending,
+	}
+	for _, d := range []struct {
+		name  string
+		value string
+		dest  *time.Duration
+	}{
+		{"latency", raw.Latency, &conf.Latency},
+		{"jitter", raw.Jitter, &conf.Jitter},
+		{"timeout", raw.Timeout, &conf.Timeout},
+	} {
+		if len(d.value) == 0 {
+			continue
+		}
+		var err error
+		if *d.dest, err = time.ParseDuration(d.value); err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+	}
+	*c = conf
+	return nil
+}
+
+// ParseConfigs converts faults by da name, as in the config file, to faults by da type.
+func ParseConfigs(faults map[string]Config) (map[int]Config, error) {
+	res := make(map[int]Config, len(faults))
+	for name, conf := range faults {
+		daType, err := _common.ParseDAType(name)
+		if err != nil {
+			return nil, err
+		}
+		if daType == _common.ErasureType {
+			return nil, errors.New("erasure is served by other DAs, set their faults instead")
+		}
+		if err := conf.Check(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		res[daType] = conf
+	}
+	return res, nil
+}
+
+// Injector applies the faults of every DA to its requests, a nil Injector injects nothing.
+type Injector struct {
+	// record is called for every injected fault
+	record func(daType int, kind string)
+
+	mu     sync.RWMutex
+	faults map[int]Config
+}
+
+// New returns an injector of faults, record may be nil.
+func New(faults map[int]Config, record func(daType int, kind string)) *Injector {
+	if record == nil {
+		record = func(int, string) {}
+	}
+	i := &Injector{record: record}
+	i.Reset(faults)
+	return i
+}
+
+// Reset replaces the faults of every DA.
+func (i *Injector) Reset(faults map[int]Config) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = make(map[int]Config, len(faults))
+	for daType, conf := range faults {
+		i.faults[daType] = conf
+	}
+}
+
+// Set replaces the faults of daType.
+func (i *Injector) Set(daType int, conf Config) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults[daType] = conf
+}
+
+// Clear removes the faults of daType.
+func (i *Injector) Clear(daType int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.faults, daType)
+}
+
+// All returns the faults by da type.
+func (i *Injector) All() map[int]Config {
+	if i == nil {
+		return nil
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	res := make(map[int]Config, len(i.faults))
+	for daType, conf := range i.faults {
+		res[daType] = conf
+	}
+	return res
+}
+
+func (i *Injector) get(daType int, op string) (Config, bool) {
+	if i == nil {
+		return Config{}, false
+	}
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	conf, ok := i.faults[daType]
+	return conf, ok && conf.applies(op)
+}
+
+// Before delays an op request to daType and decides whether it fails, it is called before the
+// request reaches the backend.
+func (i *Injector) Before(ctx context.Context, daType int, op string) error {
+	conf, ok := i.get(daType, op)
+	if !ok {
+		return nil
+	}
+	if delay := conf.Latency; delay > 0 || conf.Jitter > 0 {
+		if conf.Jitter > 0 {
+			delay += rand.N(conf.Jitter)
+		}
+		i.record(daType, KindLatency)
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if roll(conf.ErrorRate) {
+		i.record(daType, KindError)
+		return fmt.Errorf("%w: %s %s failed", ErrInjected, _common.DATypeName(daType), op)
+	}
+	if roll(conf.TimeoutRate) {
+		i.record(daType, KindTimeout)
+		timeout := conf.Timeout
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			return ErrTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Data returns the data retrieved from daType as the faults alter it, data itself is left untouched.
+func (i *Injector) Data(daType int, data []byte) []byte {
+	conf, ok := i.get(daType, OpRetrieve)
+	if !ok || len(data) == 0 {
+		return data
+	}
+	if roll(conf.TruncateRate) {
+		i.record(daType, KindTruncate)
+		data = data[:rand.N(len(data))]
+	}
+	if roll(conf.CorruptRate) && len(data) > 0 {
+		i.record(daType, KindCorrupt)
+		data = slices.Clone(data)
+		data[rand.N(len(data))] ^= 0xff
+	}
+	return data
+}
+
+// Pending reports whether the submissions to daType must be reported pending.
+func (i *Injector) Pending(daType int) bool {
+	conf, ok := i.get(daType, OpStatus)
+	if ok && conf.Pending {
+		i.record(daType, KindPending)
+	}
+	return ok && conf.Pending
+}
+
+func roll(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
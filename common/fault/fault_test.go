@@ -0,0 +1,89 @@
+package fault
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestInjector(t *testing.T) {
+	ctx := context.Background()
+	recorded := make(map[string]int)
+	i := New(map[int]Config{
+		_common.EigenDAType: {ErrorRate: 1, Ops: []string{OpRollup}},
+	}, func(_ int, kind string) { recorded[kind]++ })
+
+	require.ErrorIs(t, i.Before(ctx, _common.EigenDAType, OpRollup), ErrInjected)
+	require.NoError(t, i.Before(ctx, _common.EigenDAType, OpRetrieve))
+	require.NoError(t, i.Before(ctx, _common.CelestiaType, OpRollup))
+
+	i.Set(_common.CelestiaType, Config{TimeoutRate: 1, Timeout: time.Millisecond})
+	require.ErrorIs(t, i.Before(ctx, _common.CelestiaType, OpStatus), context.DeadlineExceeded)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	i.Set(_common.CelestiaType, Config{TimeoutRate: 1})
+	require.ErrorIs(t, i.Before(canceled, _common.CelestiaType, OpStatus), context.Canceled)
+
+	i.Set(_common.CelestiaType, Config{Latency: 20 * time.Millisecond})
+	start := time.Now()
+	require.NoError(t, i.Before(ctx, _common.CelestiaType, OpRetrieve))
+	require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	data := []byte("rollup batch")
+	i.Set(_common.CelestiaType, Config{TruncateRate: 1})
+	require.Less(t, len(i.Data(_common.CelestiaType, data)), len(data))
+	i.Set(_common.CelestiaType, Config{CorruptRate: 1})
+	corrupted := i.Data(_common.CelestiaType, data)
+	require.Len(t, corrupted, len(data))
+	require.NotEqual(t, data, corrupted)
+	require.Equal(t, []byte("rollup batch"), data, "the retrieved data is altered in place")
+
+	i.Set(_common.CelestiaType, Config{Pending: true})
+	require.True(t, i.Pending(_common.CelestiaType))
+	require.False(t, i.Pending(_common.EigenDAType))
+
+	i.Clear(_common.CelestiaType)
+	require.Len(t, i.All(), 1)
+	require.Equal(t, map[string]int{KindError: 1, KindTimeout: 2, KindLatency: 1, KindTruncate: 1, KindCorrupt: 1, KindPending: 1}, recorded)
+
+	var none *Injector
+	require.NoError(t, none.Before(ctx, _common.EigenDAType, OpRollup))
+	require.Equal(t, data, none.Data(_common.EigenDAType, data))
+	require.False(t, none.Pending(_common.EigenDAType))
+}
+
+func TestConfig(t *testing.T) {
+	var conf Config
+	require.NoError(t, json.Unmarshal([]byte(`{"ops":["retrieve"],"latency":"1.5s","corrupt_rate":0.25}`), &conf))
+	require.Equal(t, Config{Ops: []string{OpRetrieve}, Latency: 1500 * time.Millisecond, CorruptRate: 0.25}, conf)
+	raw, err := json.Marshal(conf)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"ops":["retrieve"],"latency":"1.5s","corrupt_rate":0.25}`, string(raw))
+	require.Error(t, json.Unmarshal([]byte(`{"latency":"soon"}`), &conf))
+
+	for _, conf := range []Config{
+		{Ops: []string{"proof"}},
+		{Latency: -time.Second},
+		{ErrorRate: 1.5},
+		{CorruptRate: -0.1},
+	} {
+		require.Error(t, conf.Check(), "%+v", conf)
+	}
+
+	faults, err := ParseConfigs(map[string]Config{"eigenda": {ErrorRate: 0.5}})
+	require.NoError(t, err)
+	require.Equal(t, map[int]Config{_common.EigenDAType: {ErrorRate: 0.5}}, faults)
+	for _, faults := range []map[string]Config{
+		{"avail": {}},
+		{"erasure": {}},
+		{"celestia": {TimeoutRate: 2}},
+	} {
+		_, err := ParseConfigs(faults)
+		require.Error(t, err, "%v", faults)
+	}
+}
@@ -0,0 +1,36 @@
+package health
+
+import (
+	"context"
+)
+
+type Status string
+
+const (
+	StatusOK          Status = "ok"
+	StatusUnhealthy   Status = "unhealthy"
+	StatusNotPrepared Status = "not_prepared"
+)
+
+// Checker is implemented by DA clients which are able to probe their backend,
+// e.g. an rpc ping, an auth check, an account balance or the sync status.
+type Checker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// BackendStatus is the result of probing a single DA backend.
+type BackendStatus struct {
+	Name      string `json:"name"`
+	DAType    int    `json:"da_type"`
+	State     string `json:"state,omitempty"`
+	Status    Status `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// Report aggregates the status of all DA backends of a rollup node.
+// The node is ready when at least one backend is prepared and every prepared backend is healthy.
+type Report struct {
+	Ready    bool            `json:"ready"`
+	Backends []BackendStatus `json:"backends"`
+}
@@ -0,0 +1,52 @@
+package inflight
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Tracker counts in-flight requests and lets a shutdown wait for them to finish
+// while refusing new ones.
+type Tracker struct {
+	mu       sync.RWMutex
+	draining bool
+	wg       sync.WaitGroup
+}
+
+// Begin registers a new request. It returns false once Drain was called, in which
+// case the request must be rejected and Done must not be called.
+func (t *Tracker) Begin() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.draining {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+// Done marks a request registered by Begin as finished.
+func (t *Tracker) Done() {
+	t.wg.Done()
+}
+
+// Drain refuses new requests and waits until the in-flight ones finished or ctx is done.
+func (t *Tracker) Drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("in-flight requests did not finish: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,20 @@
+package common
+
+import "context"
+
+type namespaceKey struct{}
+
+// WithNamespace returns a copy of ctx carrying the namespace a rollup request is stored under,
+// either a tenant name or a raw namespace. DAs without namespaces ignore it.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	if len(namespace) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, namespaceKey{}, namespace)
+}
+
+// NamespaceFromContext returns the namespace set by WithNamespace, empty for the DA's default one.
+func NamespaceFromContext(ctx context.Context) string {
+	namespace, _ := ctx.Value(namespaceKey{}).(string)
+	return namespace
+}
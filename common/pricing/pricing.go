@@ -0,0 +1,250 @@
+// Package pricing prices the DAs without a fee market to query and converts the estimated costs to
+// fiat, see RollupModule.EstimateCostWithTypeContext.
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// The sources of the token prices.
+const (
+	SourceStatic    = "static"
+	SourceCoinGecko = "coingecko"
+)
+
+const (
+	DefaultCoinGeckoURL = "https://api.coingecko.com/api/v3"
+	DefaultTTL          = 5 * time.Minute
+	fetchTimeout        = 10 * time.Second
+)
+
+var ErrNoRate = errors.New("no pricing configured")
+
+// Token is the native token fees of a DA are paid in.
+type Token struct {
+	Symbol string `json:"symbol"`
+	// Decimals of the smallest unit amounts are counted in, e.g. 18 for wei
+	Decimals int `json:"decimals"`
+}
+
+var (
+	ETH  = Token{Symbol: "ETH", Decimals: 18}
+	TIA  = Token{Symbol: "TIA", Decimals: 6}
+	NEAR = Token{Symbol: "NEAR", Decimals: 24}
+)
+
+// NativeTokens are the tokens fees are paid in by da type, anytrust and eigenda are settled in ETH.
+var NativeTokens = map[int]Token{
+	_common.AnytrustType:          ETH,
+	_common.CelestiaType:          TIA,
+	_common.EigenDAType:           ETH,
+	_common.Eip4844Type:           ETH,
+	_common.NearDAType:            NEAR,
+	_common.AnytrustCommitteeType: ETH,
+}
+
+// coinGeckoIDs are the ids of the tokens in the CoinGecko API.
+var coinGeckoIDs = map[string]string{
+	ETH.Symbol:  "ethereum",
+	TIA.Symbol:  "celestia",
+	NEAR.Symbol: "near",
+}
+
+// Rate prices a submission to a DA in whole native tokens, e.g. 0.00001 NEAR per byte.
+type Rate struct {
+	Base    float64 `mapstructure:"base"`
+	PerByte float64 `mapstructure:"per_byte"`
+}
+
+// Amount returns the price of a submission of size bytes in the smallest unit of token.
+func (r Rate) Amount(size int, token Token) *big.Int {
+	total := new(big.Rat).Mul(decimal(r.PerByte), new(big.Rat).SetInt64(int64(size)))
+	total.Add(total, decimal(r.Base))
+	total.Mul(total, new(big.Rat).SetInt(unit(token)))
+	// fractions of the smallest unit are dropped
+	return new(big.Int).Quo(total.Num(), total.Denom())
+}
+
+// decimal returns the number f was written as in the config file, 0.1 rather than its closest float.
+func decimal(f float64) *big.Rat {
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return r
+}
+
+// Config of the [pricing] section.
+type Config struct {
+	// Rates price the DAs without a fee market by da name, anytrust, eigenda and nearda
+	Rates map[string]Rate `mapstructure:"rates"`
+	// Currency the costs are converted to, e.g. "usd", they are left in native tokens when empty
+	Currency string `mapstructure:"currency"`
+	// Source of the token prices, "static" reads Prices, "coingecko" queries URL
+	Source string `mapstructure:"source"`
+	URL    string `mapstructure:"url"`
+	// Prices of the tokens in Currency by symbol, for the static source
+	Prices map[string]float64 `mapstructure:"prices"`
+	// TTL of the prices fetched from the source
+	TTL time.Duration `mapstructure:"ttl"`
+}
+
+func DefaultConfig() Config {
+	return Config{Source: SourceStatic, URL: DefaultCoinGeckoURL, TTL: DefaultTTL}
+}
+
+func (c Config) Check() error {
+	for name, rate := range c.Rates {
+		if _, err := _common.ParseDAType(name); err != nil {
+			return fmt.Errorf("rates: %w", err)
+		}
+		if rate.Base < 0 || rate.PerByte < 0 {
+			return fmt.Errorf("rates: %s must not be negative", name)
+		}
+	}
+	if len(c.Currency) == 0 {
+		return nil
+	}
+	switch c.Source {
+	case SourceStatic:
+		for symbol, price := range c.Prices {
+			if price < 0 {
+				return fmt.Errorf("prices: %s must not be negative", symbol)
+			}
+		}
+	case SourceCoinGecko:
+		if _, err := url.ParseRequestURI(c.URL); err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+		if c.TTL <= 0 {
+			return errors.New("ttl must be positive")
+		}
+	default:
+		return fmt.Errorf("unknown source %q, expected %s or %s", c.Source, SourceStatic, SourceCoinGecko)
+	}
+	return nil
+}
+
+// Rate returns the rate of daType, ok is false when it isn't priced.
+func (c Config) Rate(daType int) (Rate, bool) {
+	for name, rate := range c.Rates {
+		if t, err := _common.ParseDAType(name); err == nil && t == daType {
+			return rate, true
+		}
+	}
+	return Rate{}, false
+}
+
+// Prices serves the prices of the tokens in the configured currency, caching the fetched ones for
+// the TTL. A nil Prices converts nothing.
+type Prices struct {
+	conf   Config
+	client *http.Client
+
+	mu      sync.Mutex
+	fetched map[string]price
+}
+
+type price struct {
+	value float64
+	at    time.Time
+}
+
+// NewPrices returns the price source of conf, nil when no currency is set.
+func NewPrices(conf Config) *Prices {
+	if len(conf.Currency) == 0 {
+		return nil
+	}
+	return &Prices{
+		conf:    conf,
+		client:  &http.Client{Timeout: fetchTimeout},
+		fetched: make(map[string]price),
+	}
+}
+
+// Currency returns the currency prices are in.
+func (p *Prices) Currency() string {
+	if p == nil {
+		return ""
+	}
+	return p.conf.Currency
+}
+
+// Price returns the price of a whole token.
+func (p *Prices) Price(ctx context.Context, token Token) (float64, error) {
+	if p == nil {
+		return 0, errors.New("no currency configured")
+	}
+	if p.conf.Source == SourceStatic {
+		// the keys of the config file are lowercased
+		for symbol, value := range p.conf.Prices {
+			if strings.EqualFold(symbol, token.Symbol) {
+				return value, nil
+			}
+		}
+		return 0, fmt.Errorf("no price of %s", token.Symbol)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if cached, ok := p.fetched[token.Symbol]; ok && time.Since(cached.at) < p.conf.TTL {
+		return cached.value, nil
+	}
+	value, err := p.fetchCoinGecko(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	p.fetched[token.Symbol] = price{value: value, at: time.Now()}
+	return value, nil
+}
+
+// fetchCoinGecko queries the simple price endpoint of the CoinGecko API.
+func (p *Prices) fetchCoinGecko(ctx context.Context, token Token) (float64, error) {
+	id, ok := coinGeckoIDs[token.Symbol]
+	if !ok {
+		return 0, fmt.Errorf("no coingecko id for %s", token.Symbol)
+	}
+	currency := strings.ToLower(p.conf.Currency)
+	query := url.Values{"ids": {id}, "vs_currencies": {currency}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(p.conf.URL, "/")+"/simple/price?"+query.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetch price of %s: %w", token.Symbol, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("fetch price of %s: %s", token.Symbol, resp.Status)
+	}
+	var prices map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&prices); err != nil {
+		return 0, fmt.Errorf("decode price of %s: %w", token.Symbol, err)
+	}
+	value, ok := prices[id][currency]
+	if !ok {
+		return 0, fmt.Errorf("no price of %s in %s", token.Symbol, currency)
+	}
+	return value, nil
+}
+
+// Fiat converts an amount in the smallest unit of token at the price of a whole token.
+func Fiat(amount *big.Int, token Token, price float64) float64 {
+	value, _ := new(big.Rat).SetFrac(amount, unit(token)).Float64()
+	return value * price
+}
+
+// unit returns the number of smallest units in a whole token.
+func unit(token Token) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(token.Decimals)), nil)
+}
@@ -0,0 +1,87 @@
+package pricing
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestRate(t *testing.T) {
+	for _, tt := range []struct {
+		rate   Rate
+		size   int
+		token  Token
+		amount string
+	}{
+		{Rate{PerByte: 0.00001}, 1000, NEAR, "10000000000000000000000"},
+		{Rate{Base: 0.1, PerByte: 0.000000001}, 1024, ETH, "100001024000000000"},
+		{Rate{Base: 0.5}, 0, TIA, "500000"},
+		{Rate{PerByte: 0.0000001}, 3, TIA, "0"},
+	} {
+		require.Equal(t, tt.amount, tt.rate.Amount(tt.size, tt.token).String(), "%+v", tt.rate)
+	}
+
+	conf := Config{Rates: map[string]Rate{"eigenda": {PerByte: 1e-9}}}
+	rate, ok := conf.Rate(_common.EigenDAType)
+	require.True(t, ok)
+	require.Equal(t, 1e-9, rate.PerByte)
+	_, ok = conf.Rate(_common.NearDAType)
+	require.False(t, ok)
+
+	require.InDelta(t, 3.0, Fiat(big.NewInt(1e15), ETH, 3000), 1e-9)
+}
+
+func TestConfig(t *testing.T) {
+	require.NoError(t, DefaultConfig().Check())
+	for _, conf := range []Config{
+		{Rates: map[string]Rate{"avail": {}}},
+		{Rates: map[string]Rate{"nearda": {PerByte: -1}}},
+		{Currency: "usd", Source: "oracle"},
+		{Currency: "usd", Source: SourceCoinGecko, URL: "not a url", TTL: time.Minute},
+		{Currency: "usd", Source: SourceCoinGecko, URL: DefaultCoinGeckoURL},
+		{Currency: "usd", Source: SourceStatic, Prices: map[string]float64{"eth": -1}},
+	} {
+		require.Error(t, conf.Check(), "%+v", conf)
+	}
+}
+
+func TestPrices(t *testing.T) {
+	ctx := context.Background()
+	require.Nil(t, NewPrices(DefaultConfig()))
+
+	static := NewPrices(Config{Currency: "usd", Source: SourceStatic, Prices: map[string]float64{"eth": 3000}})
+	price, err := static.Price(ctx, ETH)
+	require.NoError(t, err)
+	require.Equal(t, 3000.0, price)
+	_, err = static.Price(ctx, TIA)
+	require.Error(t, err)
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "/simple/price", r.URL.Path)
+		assert.Equal(t, "celestia", r.URL.Query().Get("ids"))
+		assert.Equal(t, "eur", r.URL.Query().Get("vs_currencies"))
+		_, _ = w.Write([]byte(`{"celestia":{"eur":4.5}}`))
+	}))
+	defer srv.Close()
+
+	prices := NewPrices(Config{Currency: "EUR", Source: SourceCoinGecko, URL: srv.URL, TTL: time.Minute})
+	for i := 0; i < 3; i++ {
+		price, err = prices.Price(ctx, TIA)
+		require.NoError(t, err)
+		require.Equal(t, 4.5, price)
+	}
+	require.Equal(t, int32(1), requests.Load(), "the price is cached for the ttl")
+	_, err = prices.Price(ctx, Token{Symbol: "DOGE"})
+	require.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package common
+
+import "encoding/json"
+
+// InclusionProof is the proof that submitted data is included in the DA, as returned by the DA node.
+type InclusionProof struct {
+	DAType int `json:"da_type"`
+	// Included is the result of checking the proof against the DA node
+	Included bool              `json:"included"`
+	Detail   map[string]string `json:"detail,omitempty"`
+	Proof    json.RawMessage   `json:"proof,omitempty"`
+}
+
+// Attestation proves that submitted data was attested on L1, as checked against the L1 contract.
+type Attestation struct {
+	DAType int               `json:"da_type"`
+	Detail map[string]string `json:"detail,omitempty"`
+	// Calldata is the hex encoded call of the on-chain verifier, it holds the whole proof
+	Calldata string `json:"calldata"`
+}
+
+// Content is a payload retrieved by its hash, with the receipt it was retrieved with.
+type Content struct {
+	DAType  int         `json:"da_type"`
+	Receipt interface{} `json:"receipt"`
+	Data    []byte      `json:"data"`
+}
@@ -0,0 +1,253 @@
+// Package receipt decodes the receipts returned by RollupWithType without contacting any DA.
+package receipt
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+)
+
+const (
+	// anytrust certificate header flags, see das.Serialize
+	dasMessageHeaderFlag     byte = 0x80
+	treeDASMessageHeaderFlag byte = 0x08
+
+	blobCommitmentVersionKZG byte = 0x01
+
+	// version byte followed by the 28 bytes id
+	celestiaNamespaceSize = 29
+)
+
+// AnytrustCert is an anytrust data availability certificate.
+type AnytrustCert struct {
+	Version     uint8     `json:"version"`
+	KeysetHash  string    `json:"keyset_hash"`
+	DataHash    string    `json:"data_hash"`
+	Timeout     time.Time `json:"timeout"`
+	SignersMask uint64    `json:"signers_mask"`
+	Signers     []int     `json:"signers"`
+	Signature   string    `json:"signature"`
+}
+
+// AnytrustDataHash is the first receipt of anytrust, the key the data is retrieved with.
+type AnytrustDataHash struct {
+	DataHash string `json:"data_hash"`
+}
+
+// CelestiaReceipt is the height the blob was included at, the namespace it was stored under and
+// its share commitment, followed by the fee paid in utia. Bare heights only have the height, older
+// receipts have no commitment or fee.
+type CelestiaReceipt struct {
+	Height           uint64 `json:"height"`
+	Namespace        string `json:"namespace,omitempty"`
+	NamespaceVersion *uint8 `json:"namespace_version,omitempty"`
+	NamespaceID      string `json:"namespace_id,omitempty"`
+	Commitment       string `json:"commitment,omitempty"`
+	FeeUtia          uint64 `json:"fee_utia,omitempty"`
+}
+
+// EigenDARequestID is the request id returned by the disperser, "<blob hash>-<metadata hash>".
+type EigenDARequestID struct {
+	BlobHash     string    `json:"blob_hash"`
+	MetadataHash string    `json:"metadata_hash"`
+	RequestedAt  time.Time `json:"requested_at"`
+	// SecurityParams are "quorum/adversary threshold" pairs
+	SecurityParams []string `json:"security_params,omitempty"`
+}
+
+// Eip4844Ref is the hash of a batch transaction, or the versioned hash of one of its blobs.
+type Eip4844Ref struct {
+	TxHash        string `json:"tx_hash,omitempty"`
+	VersionedHash string `json:"versioned_hash,omitempty"`
+	// Note tells apart the ambiguous cases, a tx hash may start with the kzg version byte.
+	Note string `json:"note,omitempty"`
+}
+
+// NearDAFrameRef is the reference of a frame submitted to the near blob contract.
+type NearDAFrameRef struct {
+	TxID       string `json:"tx_id"`
+	Commitment string `json:"commitment"`
+	TxIndex    uint32 `json:"tx_index"`
+}
+
+// Inspect decodes a receipt of the given da type into one of the types of this package.
+func Inspect(daType int, receipt string) (interface{}, error) {
+	receipt = strings.TrimSpace(receipt)
+	switch daType {
+	case _common.AnytrustType, _common.AnytrustCommitteeType:
+		if hash, err := decodeHex(receipt); err == nil && len(hash) == 32 {
+			return &AnytrustDataHash{DataHash: "0x" + hex.EncodeToString(hash)}, nil
+		}
+		cert, err := base64.StdEncoding.DecodeString(receipt)
+		if err != nil {
+			return nil, fmt.Errorf("anytrust receipt is neither a data hash nor a base64 certificate: %w", err)
+		}
+		return DecodeAnytrustCert(cert)
+	case _common.CelestiaType:
+		return DecodeCelestiaReceipt(receipt)
+	case _common.EigenDAType:
+		reqID, err := base64.StdEncoding.DecodeString(receipt)
+		if err != nil {
+			return nil, fmt.Errorf("eigenda receipt must be base64: %w", err)
+		}
+		return DecodeEigenDARequestID(reqID)
+	case _common.Eip4844Type:
+		return DecodeEip4844Ref(receipt)
+	case _common.NearDAType:
+		frameRef, err := base64.StdEncoding.DecodeString(receipt)
+		if err != nil {
+			return nil, fmt.Errorf("nearda receipt must be base64: %w", err)
+		}
+		return DecodeNearDAFrameRef(frameRef)
+	case _common.ErasureType:
+		return erasure.ParseReceipt(receipt)
+	}
+	return nil, fmt.Errorf("unknown da type %d", daType)
+}
+
+// DecodeAnytrustCert decodes a certificate serialized by das.Serialize.
+func DecodeAnytrustCert(data []byte) (*AnytrustCert, error) {
+	if len(data) == 0 || data[0]&dasMessageHeaderFlag == 0 {
+		return nil, errors.New("not an anytrust certificate, missing das header flag")
+	}
+	hasVersion := data[0]&treeDASMessageHeaderFlag != 0
+	size := 1 + 32 + 32 + 8 + 8
+	if hasVersion {
+		size++
+	}
+	if len(data) < size {
+		return nil, fmt.Errorf("anytrust certificate too short, got %d bytes, want at least %d", len(data), size)
+	}
+
+	cert := &AnytrustCert{
+		KeysetHash: "0x" + hex.EncodeToString(data[1:33]),
+		DataHash:   "0x" + hex.EncodeToString(data[33:65]),
+		Timeout:    time.Unix(int64(binary.BigEndian.Uint64(data[65:73])), 0).UTC(),
+	}
+	rest := data[73:]
+	if hasVersion {
+		cert.Version = rest[0]
+		rest = rest[1:]
+	}
+	cert.SignersMask = binary.BigEndian.Uint64(rest[:8])
+	for i := 0; i < 64; i++ {
+		if cert.SignersMask&(1<<i) != 0 {
+			cert.Signers = append(cert.Signers, i)
+		}
+	}
+	cert.Signature = "0x" + hex.EncodeToString(rest[8:])
+	return cert, nil
+}
+
+// DecodeCelestiaReceipt decodes "<height>:<namespace hex>:<commitment hex>:<fee>utia", receipts
+// without fee or commitment, or a bare height.
+func DecodeCelestiaReceipt(receipt string) (*CelestiaReceipt, error) {
+	parts := strings.Split(receipt, ":")
+	if len(parts) > 4 {
+		return nil, fmt.Errorf("invalid celestia receipt %q, want <height>:<namespace>:<commitment>:<fee>", receipt)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid celestia receipt height: %w", err)
+	}
+	res := &CelestiaReceipt{Height: height}
+	if len(parts) == 1 {
+		return res, nil
+	}
+	namespace, err := decodeHex(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid celestia receipt namespace: %w", err)
+	}
+	if len(namespace) != celestiaNamespaceSize {
+		return nil, fmt.Errorf("celestia namespace must be %d bytes, got %d", celestiaNamespaceSize, len(namespace))
+	}
+	res.Namespace = "0x" + hex.EncodeToString(namespace)
+	res.NamespaceVersion = &namespace[0]
+	res.NamespaceID = "0x" + hex.EncodeToString(namespace[1:])
+	if len(parts) > 2 {
+		commitment, err := decodeHex(parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid celestia receipt commitment: %w", err)
+		}
+		res.Commitment = "0x" + hex.EncodeToString(commitment)
+	}
+	if len(parts) > 3 {
+		if res.FeeUtia, err = strconv.ParseUint(strings.TrimSuffix(parts[3], "utia"), 10, 64); err != nil {
+			return nil, fmt.Errorf("invalid celestia receipt fee: %w", err)
+		}
+	}
+	return res, nil
+}
+
+// DecodeEigenDARequestID decodes the request id of a dispersal, the metadata hash is the hex of
+// "<requested at ns>/<quorum>/<threshold>/..." followed by a sha256 digest.
+func DecodeEigenDARequestID(reqID []byte) (*EigenDARequestID, error) {
+	blobHash, metadataHash, ok := strings.Cut(string(reqID), "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid eigenda request id %q, want <blob hash>-<metadata hash>", reqID)
+	}
+	res := &EigenDARequestID{BlobHash: "0x" + blobHash, MetadataHash: "0x" + metadataHash}
+
+	metadata, err := hex.DecodeString(metadataHash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eigenda metadata hash: %w", err)
+	}
+	if len(metadata) <= sha256.Size {
+		return nil, fmt.Errorf("eigenda metadata hash too short, got %d bytes", len(metadata))
+	}
+	fields := strings.Split(strings.TrimSuffix(string(metadata[:len(metadata)-sha256.Size]), "/"), "/")
+	requestedAt, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid eigenda request time: %w", err)
+	}
+	res.RequestedAt = time.Unix(0, requestedAt).UTC()
+	for i := 1; i+1 < len(fields); i += 2 {
+		res.SecurityParams = append(res.SecurityParams, fields[i]+"/"+fields[i+1])
+	}
+	return res, nil
+}
+
+// DecodeEip4844Ref decodes a 32 bytes hex hash, either a tx hash or a blob versioned hash.
+func DecodeEip4844Ref(ref string) (*Eip4844Ref, error) {
+	hash, err := decodeHex(ref)
+	if err != nil {
+		return nil, fmt.Errorf("eip4844 receipt must be hex: %w", err)
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("eip4844 receipt must be 32 bytes, got %d", len(hash))
+	}
+	hashHex := "0x" + hex.EncodeToString(hash)
+	if hash[0] == blobCommitmentVersionKZG {
+		return &Eip4844Ref{
+			TxHash:        hashHex,
+			VersionedHash: hashHex,
+			Note:          "starts with the kzg version byte, this is a blob versioned hash unless it is the tx hash returned by rollup",
+		}, nil
+	}
+	return &Eip4844Ref{TxHash: hashHex}, nil
+}
+
+// DecodeNearDAFrameRef decodes a frame ref, the tx id followed by the commitment.
+func DecodeNearDAFrameRef(frameRef []byte) (*NearDAFrameRef, error) {
+	if len(frameRef) != 64 {
+		return nil, fmt.Errorf("nearda frame ref must be 64 bytes, got %d", len(frameRef))
+	}
+	return &NearDAFrameRef{
+		TxID:       "0x" + hex.EncodeToString(frameRef[:32]),
+		Commitment: "0x" + hex.EncodeToString(frameRef[32:]),
+		TxIndex:    binary.BigEndian.Uint32(frameRef[:32]),
+	}, nil
+}
+
+func decodeHex(s string) ([]byte, error) {
+	return hex.DecodeString(strings.TrimPrefix(s, "0x"))
+}
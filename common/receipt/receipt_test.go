@@ -0,0 +1,92 @@
+package receipt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+func TestInspectAnytrustCert(t *testing.T) {
+	var cert bytes.Buffer
+	cert.WriteByte(dasMessageHeaderFlag | treeDASMessageHeaderFlag)
+	cert.Write(bytes.Repeat([]byte{0xaa}, 32))
+	cert.Write(bytes.Repeat([]byte{0xbb}, 32))
+	binary.Write(&cert, binary.BigEndian, uint64(1717834500))
+	cert.WriteByte(1)
+	binary.Write(&cert, binary.BigEndian, uint64(0b101))
+	cert.Write([]byte{0x01, 0x02})
+
+	res, err := Inspect(_common.AnytrustType, base64.StdEncoding.EncodeToString(cert.Bytes()))
+	require.NoError(t, err)
+	decoded := res.(*AnytrustCert)
+	require.Equal(t, uint8(1), decoded.Version)
+	require.Equal(t, "0x"+bytesHex(0xbb, 32), decoded.DataHash)
+	require.Equal(t, time.Unix(1717834500, 0).UTC(), decoded.Timeout)
+	require.Equal(t, []int{0, 2}, decoded.Signers)
+	require.Equal(t, "0x0102", decoded.Signature)
+
+	_, err = Inspect(_common.AnytrustType, base64.StdEncoding.EncodeToString(cert.Bytes()[:40]))
+	require.Error(t, err)
+}
+
+func TestInspectEigenDARequestID(t *testing.T) {
+	reqID := "MWNjNDc5YmVjMTBmNTFkYjVkMTUzNjJiMzg2ZTNmNGU2ZDhlY2E4MmRlZGViOTAyMWNmYWYyZjNkMzI3ZjJhNS0zMTM3MzEzNzM4MzMzNDM1MzAzMDM4MzIzMDM3MzkzNDM0MzYzODJmMzAyZjMzMzMyZjMxMmYzMzMzMmZlM2IwYzQ0Mjk4ZmMxYzE0OWFmYmY0Yzg5OTZmYjkyNDI3YWU0MWU0NjQ5YjkzNGNhNDk1OTkxYjc4NTJiODU1"
+
+	res, err := Inspect(_common.EigenDAType, reqID)
+	require.NoError(t, err)
+	decoded := res.(*EigenDARequestID)
+	require.Equal(t, "0x1cc479bec10f51db5d15362b386e3f4e6d8eca82dedeb9021cfaf2f3d327f2a5", decoded.BlobHash)
+	require.Equal(t, time.Unix(0, 1717834500820794468).UTC(), decoded.RequestedAt)
+	require.Equal(t, []string{"0/33", "1/33"}, decoded.SecurityParams)
+}
+
+func TestInspectCelestiaReceipt(t *testing.T) {
+	res, err := Inspect(_common.CelestiaType, "2075034:00"+bytesHex(0x00, 18)+"0000446170704c696e6b:"+bytesHex(0xcc, 32))
+	require.NoError(t, err)
+	decoded := res.(*CelestiaReceipt)
+	require.Equal(t, uint64(2075034), decoded.Height)
+	require.Equal(t, uint8(0), *decoded.NamespaceVersion)
+	require.Equal(t, "0x"+bytesHex(0xcc, 32), decoded.Commitment)
+
+	res, err = Inspect(_common.CelestiaType, "2075034:00"+bytesHex(0x00, 18)+"0000446170704c696e6b:"+bytesHex(0xcc, 32)+":1920utia")
+	require.NoError(t, err)
+	require.Equal(t, uint64(1920), res.(*CelestiaReceipt).FeeUtia)
+
+	res, err = Inspect(_common.CelestiaType, "2075034")
+	require.NoError(t, err)
+	require.Equal(t, &CelestiaReceipt{Height: 2075034}, res)
+}
+
+func TestInspectNearDAFrameRef(t *testing.T) {
+	frameRef := append(bytes.Repeat([]byte{0x01}, 32), bytes.Repeat([]byte{0x02}, 32)...)
+
+	res, err := Inspect(_common.NearDAType, base64.StdEncoding.EncodeToString(frameRef))
+	require.NoError(t, err)
+	decoded := res.(*NearDAFrameRef)
+	require.Equal(t, "0x"+bytesHex(0x01, 32), decoded.TxID)
+	require.Equal(t, "0x"+bytesHex(0x02, 32), decoded.Commitment)
+
+	_, err = Inspect(_common.NearDAType, base64.StdEncoding.EncodeToString(frameRef[:32]))
+	require.Error(t, err)
+}
+
+func TestInspectEip4844Ref(t *testing.T) {
+	res, err := Inspect(_common.Eip4844Type, "0x"+bytesHex(0x02, 32))
+	require.NoError(t, err)
+	require.Equal(t, &Eip4844Ref{TxHash: "0x" + bytesHex(0x02, 32)}, res)
+
+	res, err = Inspect(_common.Eip4844Type, "0x"+bytesHex(0x01, 32))
+	require.NoError(t, err)
+	require.Equal(t, "0x"+bytesHex(0x01, 32), res.(*Eip4844Ref).VersionedHash)
+}
+
+func bytesHex(b byte, n int) string {
+	return fmt.Sprintf("%x", bytes.Repeat([]byte{b}, n))
+}
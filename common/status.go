@@ -0,0 +1,10 @@
+package common
+
+// SubmissionStatus is the progress of data submitted to a DA which confirms it asynchronously.
+type SubmissionStatus struct {
+	DAType int    `json:"da_type"`
+	Status string `json:"status"`
+	// Final is set once the status won't change anymore
+	Final  bool              `json:"final"`
+	Detail map[string]string `json:"detail,omitempty"`
+}
@@ -0,0 +1,344 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/eniac-x-labs/rollup-node/common/inflight"
+	"github.com/eniac-x-labs/rollup-node/retry"
+)
+
+// State is the lifecycle state of a supervised DA backend.
+type State string
+
+const (
+	// StateInitializing means the client was never built successfully yet.
+	StateInitializing State = "initializing"
+	// StateReady means the client is built and its last health check passed.
+	StateReady State = "ready"
+	// StateDegraded means the client failed its health checks or is being rebuilt.
+	StateDegraded State = "degraded"
+	// StateFailed means building the client failed FailAfter times in a row, it is still retried.
+	StateFailed State = "failed"
+	// StateDisabled is reported by a nil supervisor, which stands for a backend disabled by config.
+	StateDisabled State = "disabled"
+)
+
+type Config struct {
+	// Backoff is the delay between attempts to build the client.
+	Backoff retry.Strategy
+	// FailAfter consecutive failed builds move the backend to StateFailed.
+	FailAfter int
+	// CheckInterval is the delay between health checks of a built client.
+	CheckInterval time.Duration
+	// CheckTimeout bounds a single health check.
+	CheckTimeout time.Duration
+	// ReconnectAfter consecutive failed health checks close the client and build a new one.
+	ReconnectAfter int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Backoff: &retry.ExponentialStrategy{
+			Min:       time.Second,
+			Max:       time.Minute,
+			MaxJitter: time.Second,
+		},
+		FailAfter:      5,
+		CheckInterval:  15 * time.Second,
+		CheckTimeout:   5 * time.Second,
+		ReconnectAfter: 3,
+	}
+}
+
+// generation is a built client and the requests holding it, a replaced client is closed once
+// they released it.
+type generation[T any] struct {
+	client   T
+	inflight inflight.Tracker
+}
+
+// Supervisor owns a DA client: it builds the client in the background with backoff
+// until it succeeds, health checks it and rebuilds it when its connection breaks.
+// It implements cliapp.Lifecycle.
+type Supervisor[T any] struct {
+	name  string
+	cfg   Config
+	build func(ctx context.Context) (T, error)
+	check func(ctx context.Context, client T) error
+	close func(ctx context.Context, client T) error
+	log   log.Logger
+
+	mu  sync.RWMutex
+	gen *generation[T]
+	// stale is set once gen failed ReconnectAfter health checks, it is served until its replacement is built
+	stale    bool
+	state    State
+	lastErr  error
+	failures int
+
+	// inflight counts the requests holding any client, Stop waits for them before closing it
+	inflight inflight.Tracker
+	// retiring counts the replaced clients waiting for their requests to be closed
+	retiring sync.WaitGroup
+
+	kick    chan struct{}
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+// New creates a supervisor for the client produced by build. check and close may be nil.
+func New[T any](name string, cfg Config, build func(ctx context.Context) (T, error),
+	check func(ctx context.Context, client T) error, close func(ctx context.Context, client T) error) *Supervisor[T] {
+	return &Supervisor[T]{
+		name:  name,
+		cfg:   cfg,
+		build: build,
+		check: check,
+		close: close,
+		log:   log.Root().With("da-type", name),
+		state: StateInitializing,
+		kick:  make(chan struct{}, 1),
+	}
+}
+
+// Init makes a first attempt to build the client, so that a reachable backend is usable
+// before Start. The error is informational, a failed build is retried once started.
+func (s *Supervisor[T]) Init(ctx context.Context) error {
+	return s.tryBuild(ctx)
+}
+
+// Get returns the current client, false while it is not built.
+func (s *Supervisor[T]) Get() (T, bool) {
+	if s == nil {
+		var empty T
+		return empty, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.gen == nil {
+		var empty T
+		return empty, false
+	}
+	return s.gen.client, true
+}
+
+// Acquire returns the current client for a request, release must be called once the request
+// is done with it. A replaced client is only closed once every request holding it released it.
+// It returns false while the client is not built or once Stop was called.
+func (s *Supervisor[T]) Acquire() (client T, release func(), ok bool) {
+	if s == nil || !s.inflight.Begin() {
+		return client, nil, false
+	}
+	for {
+		s.mu.RLock()
+		gen := s.gen
+		s.mu.RUnlock()
+		if gen == nil {
+			s.inflight.Done()
+			return client, nil, false
+		}
+		if gen.inflight.Begin() {
+			return gen.client, func() {
+				gen.inflight.Done()
+				s.inflight.Done()
+			}, true
+		}
+		// gen was replaced since it was loaded, take its replacement
+	}
+}
+
+// State returns the current state and the error which caused it, if any.
+func (s *Supervisor[T]) State() (State, error) {
+	if s == nil {
+		return StateDisabled, nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state, s.lastErr
+}
+
+// NotifyError asks for an early health check after a request on the client failed,
+// so that a broken connection is detected without waiting for CheckInterval.
+func (s *Supervisor[T]) NotifyError() {
+	if s == nil {
+		return
+	}
+	select {
+	case s.kick <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Supervisor[T]) Start(ctx context.Context) error {
+	runCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(runCtx)
+	return nil
+}
+
+// Stop ends supervision, waits for the requests which acquired the client and closes it.
+func (s *Supervisor[T]) Stop(ctx context.Context) error {
+	if s.stopped.Swap(true) {
+		return nil
+	}
+	if s.cancel != nil {
+		s.cancel()
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return fmt.Errorf("supervisor of %s did not stop: %w", s.name, ctx.Err())
+		}
+	}
+
+	// the client is closed even if some requests did not finish in time, shutdown must stay bounded
+	result := s.inflight.Drain(ctx)
+
+	s.mu.Lock()
+	gen := s.gen
+	s.gen = nil
+	s.mu.Unlock()
+
+	// the replaced clients stop waiting for their requests once supervision is canceled
+	retired := make(chan struct{})
+	go func() {
+		s.retiring.Wait()
+		close(retired)
+	}()
+	select {
+	case <-retired:
+	case <-ctx.Done():
+		result = errors.Join(result, fmt.Errorf("replaced clients of %s not closed: %w", s.name, ctx.Err()))
+	}
+
+	if gen != nil && s.close != nil {
+		result = errors.Join(result, s.close(ctx, gen.client))
+	}
+	return result
+}
+
+func (s *Supervisor[T]) Stopped() bool {
+	return s.stopped.Load()
+}
+
+func (s *Supervisor[T]) run(ctx context.Context) {
+	defer close(s.done)
+	for {
+		var wait time.Duration
+		s.mu.RLock()
+		serving := s.gen != nil && !s.stale
+		s.mu.RUnlock()
+		if serving {
+			s.probe(ctx)
+			wait = s.cfg.CheckInterval
+		} else if err := s.tryBuild(ctx); err != nil {
+			s.mu.RLock()
+			wait = s.cfg.Backoff.Duration(s.failures - 1)
+			s.mu.RUnlock()
+		} else {
+			wait = s.cfg.CheckInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.kick:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *Supervisor[T]) tryBuild(ctx context.Context) error {
+	client, err := s.build(ctx)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failures++
+		s.lastErr = err
+		if s.failures >= s.cfg.FailAfter {
+			s.state = StateFailed
+		} else if s.state == StateReady {
+			s.state = StateDegraded
+		}
+		s.log.Warn("failed to build DA client", "attempt", s.failures, "state", s.state, "err", err)
+		return err
+	}
+
+	old := s.gen
+	s.gen, s.stale = &generation[T]{client: client}, false
+	s.state, s.lastErr, s.failures = StateReady, nil, 0
+	s.log.Info("DA client ready")
+	if old != nil {
+		s.retire(ctx, old)
+	}
+	return nil
+}
+
+// retire closes a replaced client once the requests holding it released it, or supervision ends.
+func (s *Supervisor[T]) retire(ctx context.Context, gen *generation[T]) {
+	s.retiring.Add(1)
+	go func() {
+		defer s.retiring.Done()
+		if err := gen.inflight.Drain(ctx); err != nil {
+			s.log.Warn("closing replaced DA client with requests in flight", "err", err)
+		}
+		if s.close == nil {
+			return
+		}
+		if err := s.close(context.Background(), gen.client); err != nil {
+			s.log.Warn("failed to close replaced DA client", "err", err)
+		}
+	}()
+}
+
+func (s *Supervisor[T]) probe(ctx context.Context) {
+	client, built := s.Get()
+	if !built || s.check == nil {
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, s.cfg.CheckTimeout)
+	err := s.check(checkCtx, client)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	if err == nil {
+		if s.state != StateReady {
+			s.log.Info("DA client recovered")
+		}
+		s.state, s.lastErr, s.failures = StateReady, nil, 0
+		s.mu.Unlock()
+		return
+	}
+
+	s.failures++
+	s.state, s.lastErr = StateDegraded, err
+	s.log.Warn("DA client health check failed", "failures", s.failures, "err", err)
+	reconnect := s.failures >= s.cfg.ReconnectAfter
+	if reconnect {
+		// the broken client is served until its replacement is built, the next build attempt counts
+		// its own failures
+		s.stale, s.failures = true, 0
+	}
+	s.mu.Unlock()
+
+	if reconnect {
+		s.log.Warn("reconnecting DA client")
+		s.NotifyError()
+	}
+}
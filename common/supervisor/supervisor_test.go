@@ -0,0 +1,150 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eniac-x-labs/rollup-node/retry"
+)
+
+type fakeClient struct {
+	id     int32
+	closed atomic.Bool
+}
+
+func testConfig() Config {
+	return Config{
+		Backoff:        retry.Fixed(5 * time.Millisecond),
+		FailAfter:      2,
+		CheckInterval:  5 * time.Millisecond,
+		CheckTimeout:   time.Second,
+		ReconnectAfter: 2,
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSupervisorRetriesBuild(t *testing.T) {
+	var attempts atomic.Int32
+	s := New("test", testConfig(), func(ctx context.Context) (*fakeClient, error) {
+		if attempts.Add(1) <= 3 {
+			return nil, errors.New("unreachable")
+		}
+		return &fakeClient{}, nil
+	}, nil, nil)
+
+	if err := s.Init(context.Background()); err == nil {
+		t.Fatal("expected first build to fail")
+	}
+	if state, _ := s.State(); state != StateInitializing {
+		t.Fatalf("expected %s, got %s", StateInitializing, state)
+	}
+	if _, ok := s.Get(); ok {
+		t.Fatal("client should not be built")
+	}
+
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(context.Background())
+
+	waitFor(t, func() bool {
+		_, ok := s.Get()
+		return ok
+	})
+	if state, err := s.State(); state != StateReady || err != nil {
+		t.Fatalf("expected %s, got %s (%v)", StateReady, state, err)
+	}
+}
+
+func TestSupervisorReportsFailed(t *testing.T) {
+	s := New("test", testConfig(), func(ctx context.Context) (*fakeClient, error) {
+		return nil, errors.New("unreachable")
+	}, nil, nil)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop(context.Background())
+
+	waitFor(t, func() bool {
+		state, err := s.State()
+		return state == StateFailed && err != nil
+	})
+}
+
+func TestSupervisorReconnects(t *testing.T) {
+	var built atomic.Int32
+	var healthy atomic.Bool
+	healthy.Store(true)
+	first := make(chan *fakeClient, 1)
+
+	s := New("test", testConfig(), func(ctx context.Context) (*fakeClient, error) {
+		c := &fakeClient{id: built.Add(1)}
+		if c.id == 1 {
+			first <- c
+		}
+		return c, nil
+	}, func(ctx context.Context, c *fakeClient) error {
+		if c.id == 1 && !healthy.Load() {
+			return errors.New("connection reset")
+		}
+		return nil
+	}, func(ctx context.Context, c *fakeClient) error {
+		c.closed.Store(true)
+		return nil
+	})
+
+	if err := s.Init(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// a request holding the broken client keeps it open until it releases it
+	held, release, ok := s.Acquire()
+	if !ok || held.id != 1 {
+		t.Fatal("expected to acquire the first client")
+	}
+	healthy.Store(false)
+	s.NotifyError()
+	waitFor(t, func() bool {
+		c, ok := s.Get()
+		return ok && c.id == 2
+	})
+	broken := <-first
+	time.Sleep(20 * time.Millisecond)
+	if broken.closed.Load() {
+		t.Fatal("broken client closed while a request holds it")
+	}
+	if c, release, ok := s.Acquire(); !ok || c.id != 2 {
+		t.Fatal("new requests should get the new client")
+	} else {
+		release()
+	}
+	release()
+	waitFor(t, broken.closed.Load)
+	waitFor(t, func() bool {
+		state, _ := s.State()
+		return state == StateReady
+	})
+
+	if err := s.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Get(); ok {
+		t.Fatal("client should be released on stop")
+	}
+}
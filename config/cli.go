@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/urfave/cli/v2"
+
+	eth "github.com/eniac-x-labs/rollup-node/eth-serivce"
+)
+
+const (
+	ConfigFlagName = "config"
+	DevnetFlagName = "devnet"
+)
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    ConfigFlagName,
+			Usage:   "Path of the config file with the DA backend sections",
+			Value:   DefaultConfigFile,
+			EnvVars: eth.PrefixEnvVar(envPrefix, "CONFIG"),
+		},
+		&cli.BoolFlag{
+			Name:    DevnetFlagName,
+			Usage:   "Serve the DAs with the mock backends of the [devnet] section, every DA unless it lists some. No DA service is needed and the config file is optional",
+			EnvVars: eth.PrefixEnvVar(envPrefix, "DEVNET"),
+		},
+	}
+}
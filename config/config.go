@@ -1,33 +1,74 @@
 package config
 
 import (
+	_ "embed"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/pelletier/go-toml/v2"
+	"github.com/spf13/viper"
 
 	"github.com/eniac-x-labs/anytrustDA/das"
 	"github.com/eniac-x-labs/anytrustDA/util/signature"
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/cache"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/common/content"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
 	cli_config "github.com/eniac-x-labs/rollup-node/config/cli-config"
 	"github.com/eniac-x-labs/rollup-node/x/anytrust"
 	"github.com/eniac-x-labs/rollup-node/x/celestia"
-	"github.com/eniac-x-labs/rollup-node/x/eip4844"
-
-	//"github.com/eniac-x-labs/anytrustDA/das"
-	//"github.com/eniac-x-labs/anytrustDA/util/signature"
 	"github.com/eniac-x-labs/rollup-node/x/eigenda"
+	"github.com/eniac-x-labs/rollup-node/x/eip4844"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
 	"github.com/eniac-x-labs/rollup-node/x/nearda"
-	"github.com/spf13/viper"
 )
 
+// RollupConfig is the config of every DA client, a nil config means the backend is disabled.
 type RollupConfig struct {
 	AnytrustDAConfig        *anytrust.AnytrustConfig //*AnytrustConfig
 	AnytrustCommitteeConfig *das.DataAvailabilityConfig
 	CelestiaDAConfig        *celestia.CelestiaConfig
-	CelestiaCLICfg          *cli_config.CLIConfig
 	EigenDAConfig           *eigenda.EigenDAConfig
 	Eip4844Config           *eip4844.Eip4844Config
 	Eip4844CLICfg           *cli_config.CLIConfig
 	NearDAConfig            *nearda.NearDAConfig
+	// Codecs is the codec name of every enabled DA by da type, payloads are compressed with it before dispersal
+	Codecs map[int]string
+	// Keyring holds the keys payloads are encrypted with, nil when no keyring is configured
+	Keyring *encryption.Keyring
+	// DefaultKeyID encrypts the rollup requests not naming a key, they are stored in clear when empty
+	DefaultKeyID string
+	// Aggregator packs the small submissions to its DAs in a single blob
+	Aggregator aggregate.Config
+	// Chunking splits the payloads above the ceiling of its DAs in chunks listed by a manifest
+	Chunking chunk.Config
+	// Erasure spreads the shards of the payloads submitted with the erasure da type over its DAs
+	Erasure erasure.Config
+	// ContentIndexFile keeps the content index across restarts, it is only read on start
+	ContentIndexFile string
+	// ContentMaxEntries bounds the receipts of the content index, content.DefaultMaxEntries when 0
+	ContentMaxEntries int
+	// Cache holds the data retrieved from the DAs, it is only read on start
+	Cache cache.Config
+	// Devnet serves the DAs it lists with mock backends in place of the real ones, nil when disabled
+	Devnet *mockda.Config
+	// Faults are injected in the requests to the DAs by da type, a reload replaces the ones set at runtime
+	Faults map[int]fault.Config
+	// Pricing prices the DAs without a fee market and converts the cost estimates to fiat
+	Pricing pricing.Config
 }
 
 type AnytrustConfig struct {
@@ -36,127 +77,453 @@ type AnytrustConfig struct {
 	DataRetentionTime uint64 // second
 }
 
-var (
-	AnytrustConfigDir           = defaultConfigDir
-	AnytrustConfigFile          = "anytrust"
-	AnytrustCommitteeConfigFile = "anytrust_aggregator"
-	CelestiaConfigDir           = defaultConfigDir
-	CelestiaConfigFile          = "celestia"
-	EigenDAConfigDir            = defaultConfigDir
-	EigenDAConfigFile           = "eigenda"
-	Eip4844ConfigDir            = defaultConfigDir
-	Eip4844ConfigFile           = "eip4844"
-	NearDAConfigDir             = defaultConfigDir
-	NearDAConfigFile            = "nearda"
-	ApiConfigDir                = defaultConfigDir
-	ApiConfigFile               = "api"
-)
-
 const (
-	defaultConfigDir = "./config"
-	ConfigType       = "toml"
-	EnvVarPrefix     = "ROLLUP"
-	AnytrustPrefix   = "anytrust"
-	CelestiaPrefix   = "celestia"
-	EigenDAPrefix    = "eigenda"
-	Eip4844Prefix    = "eip4844"
-	NearDAPrefix     = "nearda"
-	ApiPrefix        = "api"
+	DefaultConfigFile = "./config/rollup.toml"
+	ConfigType        = "toml"
+	// EnvVarPrefix prefixes the env override of every field: ROLLUP_<SECTION>_<FIELD>, e.g. ROLLUP_EIGENDA_RPC.
+	EnvVarPrefix = "ROLLUP"
 )
 
-func NewRollupConfig() *RollupConfig {
-	// Anytrust Single DAS
-	anytrustDAConf := &anytrust.AnytrustConfig{}
-	if err := PrepareConfig(AnytrustConfigDir, AnytrustConfigFile, anytrustDAConf, AnytrustPrefix, anytrust.AnytrustDAEnvFlags); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "AnytrustDA")
+// Template is the documented config file written by `rollup-node config init`.
+//
+//go:embed rollup.toml
+var Template []byte
+
+// Config is the schema of the config file, every DA backend has its own section. Every section names
+// the codec its payloads are compressed with before dispersal, see the codec package.
+type Config struct {
+	Anytrust          AnytrustSection          `mapstructure:"anytrust"`
+	AnytrustCommittee AnytrustCommitteeSection `mapstructure:"anytrust_committee"`
+	Celestia          CelestiaSection          `mapstructure:"celestia"`
+	EigenDA           EigenDASection           `mapstructure:"eigenda"`
+	Eip4844           Eip4844Section           `mapstructure:"eip4844"`
+	NearDA            NearDASection            `mapstructure:"nearda"`
+	Encryption        EncryptionSection        `mapstructure:"encryption"`
+	Aggregator        aggregate.Config         `mapstructure:"aggregator"`
+	Chunking          chunk.Config             `mapstructure:"chunking"`
+	Erasure           erasure.Config           `mapstructure:"erasure"`
+	Content           ContentSection           `mapstructure:"content"`
+	Cache             cache.Config             `mapstructure:"cache"`
+	Devnet            DevnetSection            `mapstructure:"devnet"`
+	// Faults are the faults injected in the requests to the DAs by da name, see the fault package
+	Faults  map[string]fault.Config `mapstructure:"faults"`
+	Pricing pricing.Config          `mapstructure:"pricing"`
+
+	// settings are the effective values after env overrides, kept for printing
+	settings map[string]interface{}
+}
+
+type AnytrustSection struct {
+	Enabled                 bool   `mapstructure:"enabled"`
+	Codec                   string `mapstructure:"codec"`
+	anytrust.AnytrustConfig `mapstructure:",squash"`
+}
+
+type AnytrustCommitteeSection struct {
+	Enabled                    bool   `mapstructure:"enabled"`
+	Codec                      string `mapstructure:"codec"`
+	das.DataAvailabilityConfig `mapstructure:",squash"`
+}
+
+type CelestiaSection struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	Codec     string `mapstructure:"codec"`
+	DaRpc     string `mapstructure:"da_rpc"`
+	AuthToken string `mapstructure:"auth_token"`
+	Namespace string `mapstructure:"namespace"`
+	// Tenants maps tenant names to their namespace, a rollup request names its tenant to be stored apart
+	Tenants    map[string]string         `mapstructure:"tenants"`
+	Fee        celestia.FeeConfig        `mapstructure:"fee"`
+	Blobstream celestia.BlobstreamConfig `mapstructure:"blobstream"`
+}
+
+type EigenDASection struct {
+	Enabled               bool   `mapstructure:"enabled"`
+	Codec                 string `mapstructure:"codec"`
+	eigenda.EigenDAConfig `mapstructure:",squash"`
+}
+
+type Eip4844Section struct {
+	Enabled                bool   `mapstructure:"enabled"`
+	Codec                  string `mapstructure:"codec"`
+	L1Rpc                  string `mapstructure:"l1_rpc"`
+	L1ChainID              uint64 `mapstructure:"l1_chain_id"`
+	PrivateKey             string `mapstructure:"private_key"`
+	UseBlobs               bool   `mapstructure:"use_blobs"`
+	L1BeaconAddr           string `mapstructure:"l1_beacon_addr"`
+	ShouldFetchAllSidecars bool   `mapstructure:"should_fetch_all_sidecars"`
+	BatchInboxAddress      string `mapstructure:"batch_inbox_address"`
+	BatcherAddr            string `mapstructure:"batcher_addr"`
+}
+
+type NearDASection struct {
+	Enabled             bool   `mapstructure:"enabled"`
+	Codec               string `mapstructure:"codec"`
+	nearda.NearDAConfig `mapstructure:",squash"`
+}
+
+// EncryptionSection configures the encryption of payloads before dispersal, it applies to every DA.
+type EncryptionSection struct {
+	// Keyring is the path of the JSON keyring file, see encryption.KeyringFile
+	Keyring    string `mapstructure:"keyring"`
+	DefaultKey string `mapstructure:"default_key"`
+}
+
+// ContentSection configures the index resolving payload hashes to the receipts of this node.
+type ContentSection struct {
+	// IndexFile is the file the index is kept in across restarts, it is held in memory only when empty
+	IndexFile string `mapstructure:"index_file"`
+	// MaxEntries bounds the receipts held by the index, the payloads rolled up the longest ago are
+	// dropped first
+	MaxEntries int `mapstructure:"max_entries"`
+}
+
+// DevnetSection serves DAs with the mock backends of the mockda package, the sections of the DAs it
+// serves are disabled.
+type DevnetSection struct {
+	Enabled       bool `mapstructure:"enabled"`
+	mockda.Config `mapstructure:",squash"`
+}
+
+// secretKeys are redacted by WriteTOML.
+var secretKeys = []string{
+	"anytrust.signing_key",
+	"anytrust_committee.key.privkey",
+	"celestia.auth_token",
+	"eip4844.private_key",
+	"nearda.key",
+}
+
+// DefaultConfig returns the values used for fields missing from the config file, every backend is disabled.
+func DefaultConfig() *Config {
+	return &Config{
+		Anytrust: AnytrustSection{
+			AnytrustConfig: anytrust.AnytrustConfig{
+				DataRetentionTime: 9223372036854775807,
+			},
+		},
+		Celestia: CelestiaSection{
+			DaRpc: "localhost:26650",
+			Fee:   celestia.DefaultFeeConfig(),
+			Blobstream: celestia.BlobstreamConfig{
+				LogRange: 5000,
+			},
+		},
+		EigenDA: EigenDASection{
+			EigenDAConfig: eigenda.EigenDAConfig{
+				StatusQueryTimeout:       60 * time.Second,
+				StatusQueryRetryInterval: 5 * time.Second,
+			},
+		},
+		Eip4844: Eip4844Section{
+			UseBlobs: true,
+		},
+		NearDA: NearDASection{
+			NearDAConfig: nearda.NearDAConfig{
+				Network: "Testnet",
+			},
+		},
+		Aggregator: aggregate.DefaultConfig(),
+		Chunking:   chunk.DefaultConfig(),
+		Erasure:    erasure.DefaultConfig(),
+		Content:    ContentSection{MaxEntries: content.DefaultMaxEntries},
+		Cache:      cache.DefaultConfig(),
+		Devnet:     DevnetSection{Config: mockda.DefaultConfig()},
+		Pricing:    pricing.DefaultConfig(),
 	}
+}
 
-	// Anytrust DAS Committee
-	anytrustCommitteeConf := &das.DataAvailabilityConfig{}
-	if err := PrepareConfig(AnytrustConfigDir, AnytrustCommitteeConfigFile, anytrustCommitteeConf, AnytrustPrefix, []string{}); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "AnytrustDA-Aggregator")
+// Load reads the config file at path and applies the ROLLUP_* env overrides, it does not validate the result.
+// Every call uses its own viper instance so loads don't leak state into each other.
+func Load(path string) (*Config, error) {
+	return load(path, false)
+}
+
+// LoadDevnet is Load with the devnet enabled, a missing config file stands for the defaults.
+func LoadDevnet(path string) (*Config, error) {
+	return load(path, true)
+}
+
+func load(path string, devnet bool) (*Config, error) {
+	if len(path) == 0 {
+		path = DefaultConfigFile
 	}
+	log.Debug("Loading config", "file", path)
 
-	// Celestia
-	var celestiaConfig *celestia.CelestiaConfig
-	celestiaParseConf := &celestia.ParseCelestiaConfig{}
-	if err := PrepareConfig(CelestiaConfigDir, CelestiaConfigFile, celestiaParseConf, CelestiaPrefix, []string{}); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "Celestia")
-	} else {
-		celestiaConfig, err = celestia.ProcessCelestiaConfig(celestiaParseConf, log.Root())
-		if err != nil {
-			log.Error("Process celestia config failed", "err", err)
-		}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType(ConfigType)
+	tables, err := setDefaults(v, reflect.ValueOf(*DefaultConfig()), "")
+	if err != nil {
+		return nil, err
 	}
-	celestiaCliCfg := cli_config.ParseCLIConfig(celestiaParseConf.BlockTime)
 
-	// Eigen
-	eigendaConf := &eigenda.EigenDAConfig{}
-	if err := PrepareConfig(EigenDAConfigDir, EigenDAConfigFile, eigendaConf, EigenDAPrefix, eigenda.EigenDAEnvFlags); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "EigenDA")
+	if devnet {
+		v.Set("devnet.enabled", true)
 	}
 
-	// EIP-4844
-	var eip4844Config *eip4844.Eip4844Config
-	eip4844ParseConf := &eip4844.ParseEip4844Config{}
-	if err := PrepareConfig(EigenDAConfigDir, EigenDAConfigFile, eip4844ParseConf, Eip4844Prefix, []string{}); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "eip-4844")
-	} else {
-		eip4844Config, err = eip4844.ProcessEip4844Config(eip4844ParseConf, log.Root())
-		if err != nil {
-			log.Error("Process eip4844 config failed", "err", err)
+	if err := v.ReadInConfig(); err != nil {
+		if !devnet || !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("read config file %s: %w", path, err)
 		}
+		log.Info("config file not found, the devnet runs with the defaults", "file", path)
+	}
+	// a table set by env holds the JSON of its entries, e.g. ROLLUP_CACHE_TTLS='{"nearda": "48h"}'
+	for _, key := range tables {
+		raw, ok := v.Get(key).(string)
+		if !ok {
+			continue
+		}
+		table := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(raw), &table); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", EnvVar(key), err)
+		}
+		v.Set(key, table)
 	}
-	eip4844CliCfg := cli_config.ParseCLIConfig(eip4844ParseConf.BlockTime)
 
-	// Near
-	neardaConf := &nearda.NearDAConfig{}
-	if err := PrepareConfig(NearDAConfigDir, NearDAConfigFile, neardaConf, NearDAPrefix, nearda.NearDAEnvFlags); err != nil {
-		log.Error("PrepareConfig failed", "da-type", "NearDA")
+	conf := &Config{}
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, fmt.Errorf("decode config file %s: %w", path, err)
 	}
-	return &RollupConfig{
-		AnytrustDAConfig:        anytrustDAConf,
-		AnytrustCommitteeConfig: anytrustCommitteeConf,
-		CelestiaDAConfig:        celestiaConfig,
-		CelestiaCLICfg:          celestiaCliCfg,
-		EigenDAConfig:           eigendaConf,
-		Eip4844Config:           eip4844Config,
-		Eip4844CLICfg:           eip4844CliCfg,
-		NearDAConfig:            neardaConf,
+	conf.disableMocked()
+	conf.settings = v.AllSettings()
+	return conf, nil
+}
+
+// LoadRollupConfig loads and validates the config file at path, see LoadDevnet for devnet.
+func LoadRollupConfig(path string, devnet bool) (*RollupConfig, error) {
+	load := Load
+	if devnet {
+		load = LoadDevnet
+	}
+	conf, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
 	}
+	return conf.RollupConfig()
 }
 
-func PrepareConfig(dir, fileName string, target interface{}, envPrefix string, envFlags []string) error {
-	log.Debug("Preparing config", "dir", dir, "file_name", fileName, "config_type", ConfigType)
+// setDefaults registers the default and the env var of every leaf field of val, following mapstructure tags.
+// Map fields are tables overridden as a whole by env, their keys are returned.
+func setDefaults(v *viper.Viper, val reflect.Value, prefix string) ([]string, error) {
+	var tables []string
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if name == "-" {
+			continue
+		}
+		fieldVal := val.Field(i)
+		if opts == "squash" {
+			squashed, err := setDefaults(v, fieldVal, prefix)
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, squashed...)
+			continue
+		}
+		if len(name) == 0 {
+			name = field.Name
+		}
+		key := strings.ToLower(prefix + name)
 
-	// 1. set viper args and read config file into 'fileConf'
-	if len(dir) == 0 || len(fileName) == 0 {
-		log.Error("config dir or file name with empty string")
-		return errors.New("config dir or file name with empty string")
+		switch field.Type.Kind() {
+		case reflect.Struct:
+			nested, err := setDefaults(v, fieldVal, key+".")
+			if err != nil {
+				return nil, err
+			}
+			tables = append(tables, nested...)
+			continue
+		case reflect.Map:
+			if err := v.BindEnv(key, EnvVar(key)); err != nil {
+				return nil, err
+			}
+			tables = append(tables, key)
+			continue
+		case reflect.Func, reflect.Chan, reflect.Interface, reflect.Pointer:
+			continue
+		}
+		v.SetDefault(key, fieldVal.Interface())
+		if err := v.BindEnv(key, EnvVar(key)); err != nil {
+			return nil, err
+		}
 	}
+	return tables, nil
+}
 
-	viper.SetConfigName(fileName)
-	viper.AddConfigPath(dir)
-	viper.SetConfigType(ConfigType)
+// EnvVar returns the env var overriding the config key, e.g. "eigenda.rpc" is ROLLUP_EIGENDA_RPC.
+func EnvVar(key string) string {
+	return EnvVarPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// RollupConfig converts the enabled sections into the DA client configs.
+func (c *Config) RollupConfig() (*RollupConfig, error) {
+	conf := &RollupConfig{
+		Codecs:            make(map[int]string),
+		Aggregator:        c.Aggregator,
+		Chunking:          c.Chunking,
+		Erasure:           c.Erasure,
+		ContentIndexFile:  c.Content.IndexFile,
+		ContentMaxEntries: c.Content.MaxEntries,
+		Cache:             c.Cache,
+		Pricing:           c.Pricing,
+	}
+	faults, err := fault.ParseConfigs(c.Faults)
+	if err != nil {
+		return nil, ValidationError{{Field: "faults", Msg: err.Error()}}
+	}
+	conf.Faults = faults
+	if c.Devnet.Enabled {
+		devnetConf := c.Devnet.Config
+		conf.Devnet = &devnetConf
+		daTypes, err := devnetConf.DATypes()
+		if err != nil {
+			return nil, err
+		}
+		for _, daType := range daTypes {
+			_, conf.Codecs[daType] = c.codec(daType)
+		}
+	}
+	if c.Anytrust.Enabled {
+		anytrustConf := c.Anytrust.AnytrustConfig
+		conf.AnytrustDAConfig = &anytrustConf
+		conf.Codecs[_common.AnytrustType] = c.Anytrust.Codec
+	}
+	if c.AnytrustCommittee.Enabled {
+		committeeConf := c.AnytrustCommittee.DataAvailabilityConfig
+		conf.AnytrustCommitteeConfig = &committeeConf
+		conf.Codecs[_common.AnytrustCommitteeType] = c.AnytrustCommittee.Codec
+	}
+	if c.Celestia.Enabled {
+		celestiaConf, err := celestia.ProcessCelestiaConfig(&celestia.ParseCelestiaConfig{
+			DaRpc:      c.Celestia.DaRpc,
+			AuthToken:  c.Celestia.AuthToken,
+			Namespace:  c.Celestia.Namespace,
+			Tenants:    c.Celestia.Tenants,
+			Fee:        c.Celestia.Fee,
+			Blobstream: c.Celestia.Blobstream,
+		}, log.Root())
+		if err != nil {
+			return nil, err
+		}
+		conf.CelestiaDAConfig = celestiaConf
+		conf.Codecs[_common.CelestiaType] = c.Celestia.Codec
+	}
+	if c.EigenDA.Enabled {
+		eigendaConf := c.EigenDA.EigenDAConfig
+		conf.EigenDAConfig = &eigendaConf
+		conf.Codecs[_common.EigenDAType] = c.EigenDA.Codec
+	}
+	if c.Eip4844.Enabled {
+		eip4844Conf, err := eip4844.ProcessEip4844Config(&eip4844.ParseEip4844Config{
+			UseBlobs:               c.Eip4844.UseBlobs,
+			L1BeaconAddr:           c.Eip4844.L1BeaconAddr,
+			ShouldFetchAllSidecars: c.Eip4844.ShouldFetchAllSidecars,
+			BatchInboxAddress:      c.Eip4844.BatchInboxAddress,
+			BatcherAddr:            c.Eip4844.BatcherAddr,
+			L1ChainIdFlagName:      c.Eip4844.L1ChainID,
+		}, log.Root())
+		if err != nil {
+			return nil, err
+		}
+		conf.Eip4844Config = eip4844Conf
+		conf.Eip4844CLICfg = &cli_config.CLIConfig{
+			L1Rpc:      c.Eip4844.L1Rpc,
+			L1ChainID:  new(big.Int).SetUint64(c.Eip4844.L1ChainID),
+			PrivateKey: c.Eip4844.PrivateKey,
+		}
+		conf.Codecs[_common.Eip4844Type] = c.Eip4844.Codec
+	}
+	if c.NearDA.Enabled {
+		neardaConf := c.NearDA.NearDAConfig
+		conf.NearDAConfig = &neardaConf
+		conf.Codecs[_common.NearDAType] = c.NearDA.Codec
+	}
+	if len(c.Encryption.Keyring) != 0 {
+		keyring, err := encryption.LoadKeyring(c.Encryption.Keyring)
+		if err != nil {
+			return nil, ValidationError{{Field: "encryption.keyring", Msg: err.Error()}}
+		}
+		if len(c.Encryption.DefaultKey) != 0 && !keyring.Has(c.Encryption.DefaultKey) {
+			return nil, ValidationError{{Field: "encryption.default_key", Msg: fmt.Sprintf("key %q is not in the keyring", c.Encryption.DefaultKey)}}
+		}
+		conf.Keyring = keyring
+		conf.DefaultKeyID = c.Encryption.DefaultKey
+	}
+	return conf, nil
+}
 
-	// env flag: envFlags_f
-	if envFlags != nil && len(envFlags) != 0 {
-		viper.SetEnvPrefix(envPrefix)
-		for _, f := range envFlags {
-			viper.BindEnv(f)
+// disableMocked disables the sections of the DAs served by the devnet, they need none of their services.
+func (c *Config) disableMocked() {
+	if !c.Devnet.Enabled {
+		return
+	}
+	daTypes, _ := c.Devnet.DATypes()
+	for _, daType := range daTypes {
+		switch daType {
+		case _common.AnytrustType:
+			c.Anytrust.Enabled = false
+		case _common.AnytrustCommitteeType:
+			c.AnytrustCommittee.Enabled = false
+		case _common.CelestiaType:
+			c.Celestia.Enabled = false
+		case _common.EigenDAType:
+			c.EigenDA.Enabled = false
+		case _common.Eip4844Type:
+			c.Eip4844.Enabled = false
+		case _common.NearDAType:
+			c.NearDA.Enabled = false
 		}
 	}
+}
 
-	// read config file
-	if err := viper.ReadInConfig(); err != nil {
-		log.Error("reading config file failed", "err", err)
-		return err
+// codec returns the codec configured in the section of daType, and its key.
+func (c *Config) codec(daType int) (key string, codec string) {
+	switch daType {
+	case _common.AnytrustType:
+		return "anytrust.codec", c.Anytrust.Codec
+	case _common.AnytrustCommitteeType:
+		return "anytrust_committee.codec", c.AnytrustCommittee.Codec
+	case _common.CelestiaType:
+		return "celestia.codec", c.Celestia.Codec
+	case _common.EigenDAType:
+		return "eigenda.codec", c.EigenDA.Codec
+	case _common.Eip4844Type:
+		return "eip4844.codec", c.Eip4844.Codec
+	case _common.NearDAType:
+		return "nearda.codec", c.NearDA.Codec
 	}
+	return "", ""
+}
 
-	if err := viper.Unmarshal(target); err != nil {
-		log.Error("unmarshal config file failed", "err", err)
-		return err
+// WriteTOML writes the effective config, including defaults and env overrides, with secrets redacted.
+func (c *Config) WriteTOML(w io.Writer) error {
+	for _, key := range secretKeys {
+		redact(c.settings, strings.Split(key, "."))
 	}
+	return toml.NewEncoder(w).Encode(c.settings)
+}
 
-	return nil
+func redact(settings map[string]interface{}, path []string) {
+	value, ok := settings[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) > 1 {
+		if section, ok := value.(map[string]interface{}); ok {
+			redact(section, path[1:])
+		}
+		return
+	}
+	if s, ok := value.(string); ok && len(s) != 0 {
+		settings[path[0]] = "<redacted>"
+	}
 }
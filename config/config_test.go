@@ -1,18 +1,385 @@
 package config
 
-//import (
-//	"testing"
-//
-//	_das "github.com/eniac-x-labs/anytrustDA/das"
-//
-//	//_das "github.com/eniac-x-labs/rollup-node/x/anytrust/anytrustDA/das"
-//	"github.com/stretchr/testify/assert"
-//)
-//
-//func Test_PrepareConfig(t *testing.T) {
-//	ast := assert.New(t)
-//	conf := &_das.DataAvailabilityConfig{}
-//	err := PrepareConfig("./", "anytrust", conf, "", nil)
-//	ast.NoError(err)
-//	t.Log(conf)
-//}
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "rollup.toml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func Test_TemplateIsValid(t *testing.T) {
+	conf, err := Load(writeConfig(t, string(Template)))
+	require.NoError(t, err)
+	assert.NoError(t, conf.Validate())
+
+	rollupConf, err := conf.RollupConfig()
+	require.NoError(t, err)
+	assert.NotNil(t, rollupConf.EigenDAConfig)
+	assert.Equal(t, 60*time.Second, rollupConf.EigenDAConfig.StatusQueryTimeout)
+	assert.Nil(t, rollupConf.CelestiaDAConfig)
+	assert.Nil(t, rollupConf.Eip4844Config)
+	assert.Equal(t, "none", rollupConf.Codecs[_common.EigenDAType])
+	assert.NotContains(t, rollupConf.Codecs, _common.CelestiaType)
+	assert.Equal(t, 2*time.Second, rollupConf.Aggregator.Window)
+	assert.Empty(t, rollupConf.Aggregator.DAs)
+}
+
+func Test_EnvOverridesFile(t *testing.T) {
+	path := writeConfig(t, `
+[eigenda]
+enabled = false
+rpc = "disperser-holesky.eigenda.xyz:443"
+`)
+	t.Setenv("ROLLUP_EIGENDA_ENABLED", "true")
+	t.Setenv("ROLLUP_EIGENDA_STATUS_QUERY_TIMEOUT", "2m")
+	// fields missing from the file can be set by env as well
+	t.Setenv("ROLLUP_CELESTIA_NAMESPACE", "deadbeef")
+	t.Setenv("ROLLUP_EIGENDA_CODEC", "zstd")
+
+	conf, err := Load(path)
+	require.NoError(t, err)
+	assert.True(t, conf.EigenDA.Enabled)
+	assert.Equal(t, 2*time.Minute, conf.EigenDA.StatusQueryTimeout)
+	assert.Equal(t, 5*time.Second, conf.EigenDA.StatusQueryRetryInterval)
+	assert.Equal(t, "deadbeef", conf.Celestia.Namespace)
+	assert.Equal(t, "zstd", conf.EigenDA.Codec)
+
+	// a second load does not see the values of the first file
+	conf, err = Load(writeConfig(t, "[nearda]\nenabled = false\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "", conf.EigenDA.RPC)
+}
+
+func Test_ValidateReportsEveryField(t *testing.T) {
+	conf, err := Load(writeConfig(t, `
+[celestia]
+enabled = true
+da_rpc = "localhost"
+codec = "lz4"
+
+[celestia.tenants]
+appchain_a = "0a0a"
+appchain_b = "not hex"
+
+[celestia.fee]
+gas_price_bump = 0.5
+
+[eip4844]
+enabled = true
+l1_rpc = "localhost:8545"
+batcher_addr = "0x1234"
+
+[nearda]
+enabled = false
+network = "Devnet"
+
+[aggregator]
+das = ["celestia"]
+max_size = 1024
+
+[chunking.chunk_sizes]
+avail = 1024
+
+[erasure]
+das = ["celestia", "eigenda"]
+
+[cache.ttls]
+avail = "1h"
+
+[faults.celestia]
+error_rate = 2
+
+[pricing]
+currency = "usd"
+source = "oracle"
+`))
+	require.NoError(t, err)
+
+	var validationErr ValidationError
+	require.True(t, errors.As(conf.Validate(), &validationErr))
+	fields := make([]string, len(validationErr))
+	for i, fieldErr := range validationErr {
+		fields[i] = fieldErr.Field
+	}
+	assert.ElementsMatch(t, []string{
+		"celestia.da_rpc",
+		"celestia.codec",
+		"celestia.tenants.appchain_b",
+		"celestia.fee",
+		"eip4844.l1_rpc",
+		"eip4844.l1_chain_id",
+		"eip4844.private_key",
+		"eip4844.l1_beacon_addr",
+		"eip4844.batcher_addr",
+		"aggregator",
+		"chunking",
+		"erasure",
+		"cache",
+		"faults",
+		"pricing",
+	}, fields)
+}
+
+func Test_EncryptionKeyring(t *testing.T) {
+	keyring := filepath.Join(t.TempDir(), "keyring.json")
+	require.NoError(t, os.WriteFile(keyring, []byte(`{"keys": [{"id": "appchain_a", "key": "`+
+		strings.Repeat("0a", 32)+`", "readers": ["token-a"]}]}`), 0o600))
+
+	conf, err := Load(writeConfig(t, fmt.Sprintf("[encryption]\nkeyring = %q\ndefault_key = \"appchain_a\"\n", keyring)))
+	require.NoError(t, err)
+	require.NoError(t, conf.Validate())
+	rollupConf, err := conf.RollupConfig()
+	require.NoError(t, err)
+	assert.True(t, rollupConf.Keyring.Has("appchain_a"))
+	assert.Equal(t, "appchain_a", rollupConf.DefaultKeyID)
+
+	// a default key missing from the keyring, or one without a keyring
+	var validationErr ValidationError
+	conf.Encryption.DefaultKey = "appchain_b"
+	_, err = conf.RollupConfig()
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "encryption.default_key", validationErr[0].Field)
+
+	conf.Encryption.Keyring = ""
+	require.True(t, errors.As(conf.Validate(), &validationErr))
+	assert.Equal(t, "encryption.keyring", validationErr[0].Field)
+
+	conf.Encryption.Keyring = filepath.Join(t.TempDir(), "missing.json")
+	_, err = conf.RollupConfig()
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "encryption.keyring", validationErr[0].Field)
+}
+
+func Test_WriteTOMLRedactsSecrets(t *testing.T) {
+	conf, err := Load(writeConfig(t, string(Template)))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, conf.WriteTOML(&buf))
+	assert.Contains(t, buf.String(), "<redacted>")
+	assert.NotContains(t, buf.String(), "ed25519:")
+	assert.Contains(t, buf.String(), "disperser-holesky.eigenda.xyz:443")
+}
+
+func Test_Devnet(t *testing.T) {
+	// the devnet runs without a config file
+	conf, err := LoadDevnet(filepath.Join(t.TempDir(), "missing.toml"))
+	require.NoError(t, err)
+	require.NoError(t, conf.Validate())
+	rollupConf, err := conf.RollupConfig()
+	require.NoError(t, err)
+	require.NotNil(t, rollupConf.Devnet)
+	assert.Equal(t, "memory", rollupConf.Devnet.Store)
+	assert.Equal(t, "", rollupConf.Codecs[_common.CelestiaType])
+
+	_, err = Load(filepath.Join(t.TempDir(), "missing.toml"))
+	require.Error(t, err)
+
+	// the mocked DAs need none of their services, the other ones keep theirs
+	path := writeConfig(t, `
+[celestia]
+enabled = true
+codec = "zstd"
+
+[eigenda]
+enabled = true
+rpc = "disperser-holesky.eigenda.xyz:443"
+
+[devnet]
+das = ["celestia"]
+failure_rate = 0.1
+`)
+	rollupConf, err = LoadRollupConfig(path, true)
+	require.NoError(t, err)
+	assert.Nil(t, rollupConf.CelestiaDAConfig)
+	assert.NotNil(t, rollupConf.EigenDAConfig)
+	assert.Equal(t, "zstd", rollupConf.Codecs[_common.CelestiaType])
+	assert.Equal(t, 0.1, rollupConf.Devnet.FailureRate)
+	assert.True(t, rollupConf.Devnet.Serves(_common.CelestiaType))
+	assert.False(t, rollupConf.Devnet.Serves(_common.EigenDAType))
+
+	// the devnet section enables it as well as the flag
+	t.Setenv("ROLLUP_DEVNET_ENABLED", "true")
+	t.Setenv("ROLLUP_DEVNET_FAILURE_MODE", "flaky")
+	conf, err = Load(path)
+	require.NoError(t, err)
+	var validationErr ValidationError
+	require.True(t, errors.As(conf.Validate(), &validationErr))
+	assert.Equal(t, "devnet", validationErr[0].Field)
+}
+
+func Test_Faults(t *testing.T) {
+	conf, err := Load(writeConfig(t, `
+[faults.eigenda]
+ops = ["rollup", "status"]
+latency = "200ms"
+error_rate = 0.1
+
+[faults.celestia]
+pending = true
+`))
+	require.NoError(t, err)
+	require.NoError(t, conf.Validate())
+	rollupConf, err := conf.RollupConfig()
+	require.NoError(t, err)
+	assert.Equal(t, map[int]fault.Config{
+		_common.EigenDAType:  {Ops: []string{fault.OpRollup, fault.OpStatus}, Latency: 200 * time.Millisecond, ErrorRate: 0.1},
+		_common.CelestiaType: {Pending: true},
+	}, rollupConf.Faults)
+
+	conf.Faults["erasure"] = fault.Config{}
+	var validationErr ValidationError
+	require.True(t, errors.As(conf.Validate(), &validationErr))
+	assert.Equal(t, "faults", validationErr[0].Field)
+}
+
+func Test_Pricing(t *testing.T) {
+	conf, err := Load(writeConfig(t, `
+[pricing]
+currency = "usd"
+
+[pricing.prices]
+ETH = 3000
+NEAR = 5.5
+
+[pricing.rates.nearda]
+per_byte = 0.00001
+`))
+	require.NoError(t, err)
+	require.NoError(t, conf.Validate())
+	rollupConf, err := conf.RollupConfig()
+	require.NoError(t, err)
+	assert.Equal(t, pricing.SourceStatic, rollupConf.Pricing.Source)
+	assert.Equal(t, map[string]float64{"eth": 3000, "near": 5.5}, rollupConf.Pricing.Prices)
+	rate, ok := rollupConf.Pricing.Rate(_common.NearDAType)
+	assert.True(t, ok)
+	assert.Equal(t, 0.00001, rate.PerByte)
+}
+
+func Test_EnvOverridesEveryPrintedKey(t *testing.T) {
+	file := strings.NewReplacer(
+		"[celestia.tenants]\n", "[celestia.tenants]\nappchain_a = \"0a0a\"\n",
+		"[chunking]\n", "[chunking.chunk_sizes]\neip4844 = 130044\n\n[chunking]\n",
+		"[cache.ttls]\n", "[cache.ttls]\nnearda = \"48h\"\n",
+		"[pricing.prices]\n", "[pricing.prices]\neth = 3000.0\n",
+	).Replace(string(Template)) + "\n[pricing.rates.nearda]\nper_byte = 0.00001\n\n[faults.eigenda]\nerror_rate = 0.1\n"
+	path := writeConfig(t, file)
+	base, err := Load(path)
+	require.NoError(t, err)
+
+	var printed bytes.Buffer
+	require.NoError(t, base.WriteTOML(&printed))
+	var settings map[string]interface{}
+	require.NoError(t, toml.Unmarshal(printed.Bytes(), &settings))
+
+	keys := printedKeys(t, reflect.ValueOf(*base), settings, "")
+	for _, key := range []string{"celestia.tenants", "chunking.chunk_sizes", "cache.ttls", "faults", "pricing.prices", "pricing.rates", "eigenda.rpc"} {
+		require.Contains(t, keys, key)
+	}
+	for key, field := range keys {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(EnvVar(key), envSample(field))
+			conf, err := Load(path)
+			require.NoError(t, err)
+			overridden, ok := configField(reflect.ValueOf(*conf), key)
+			require.True(t, ok)
+			require.NotEqual(t, field.Interface(), overridden.Interface(), "%s has no effect", EnvVar(key))
+		})
+	}
+}
+
+// printedKeys maps the keys of the printed settings to their config field, a table being a single key.
+func printedKeys(t *testing.T, conf reflect.Value, settings map[string]interface{}, prefix string) map[string]reflect.Value {
+	keys := make(map[string]reflect.Value)
+	for name, value := range settings {
+		key := prefix + name
+		field, ok := configField(conf, key)
+		require.True(t, ok, "printed key %s is no config field", key)
+		if section, ok := value.(map[string]interface{}); ok && field.Kind() == reflect.Struct {
+			for key, field := range printedKeys(t, conf, section, key+".") {
+				keys[key] = field
+			}
+			continue
+		}
+		keys[key] = field
+	}
+	return keys
+}
+
+// configField returns the field of val at key, following mapstructure tags.
+func configField(val reflect.Value, key string) (reflect.Value, bool) {
+	name, rest, nested := strings.Cut(key, ".")
+	for i := 0; i < val.NumField(); i++ {
+		tag, opts, _ := strings.Cut(val.Type().Field(i).Tag.Get("mapstructure"), ",")
+		if len(tag) == 0 {
+			tag = val.Type().Field(i).Name
+		}
+		if opts == "squash" {
+			if field, ok := configField(val.Field(i), key); ok {
+				return field, true
+			}
+			continue
+		}
+		if !strings.EqualFold(tag, name) {
+			continue
+		}
+		if nested {
+			return configField(val.Field(i), rest)
+		}
+		return val.Field(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// envSample returns an env value of the type of field, differing from field.
+func envSample(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(!field.Bool())
+	case reflect.Slice:
+		return envSample(reflect.New(field.Type().Elem()).Elem())
+	case reflect.Map:
+		raw, _ := json.Marshal(map[string]interface{}{"override": jsonSample(field.Type().Elem())})
+		return string(raw)
+	}
+	return fmt.Sprint(jsonSample(field.Type()))
+}
+
+func jsonSample(t reflect.Type) interface{} {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "7777s"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return true
+	case reflect.String:
+		return "override"
+	case reflect.Float32, reflect.Float64:
+		return 0.777
+	case reflect.Struct:
+		tag, _, _ := strings.Cut(t.Field(0).Tag.Get("mapstructure"), ",")
+		return map[string]interface{}{tag: jsonSample(t.Field(0).Type)}
+	}
+	return 7777
+}
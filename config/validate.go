@@ -0,0 +1,232 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+)
+
+// FieldError is a validation error of a single config field.
+type FieldError struct {
+	Field string
+	Msg   string
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Msg)
+}
+
+// ValidationError lists every invalid field of a config.
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fieldErr := range e {
+		msgs[i] = fieldErr.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+type validator struct {
+	errs ValidationError
+}
+
+func (v *validator) fail(field, format string, args ...interface{}) {
+	v.errs = append(v.errs, &FieldError{Field: field, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field, value string) bool {
+	if len(value) == 0 {
+		v.fail(field, "is required")
+		return false
+	}
+	return true
+}
+
+func (v *validator) url(field, value string, schemes ...string) {
+	if !v.required(field, value) {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil {
+		v.fail(field, "invalid url: %v", err)
+		return
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return
+		}
+	}
+	v.fail(field, "url scheme must be one of %s", strings.Join(schemes, ", "))
+}
+
+func (v *validator) hostPort(field, value string) {
+	if !v.required(field, value) {
+		return
+	}
+	_, port, err := net.SplitHostPort(value)
+	if err != nil {
+		v.fail(field, "must be host:port: %v", err)
+		return
+	}
+	if _, err := net.LookupPort("tcp", port); err != nil {
+		v.fail(field, "invalid port: %v", err)
+	}
+}
+
+func (v *validator) address(field, value string) {
+	if len(value) != 0 && !common.IsHexAddress(value) {
+		v.fail(field, "invalid address %q", value)
+	}
+}
+
+func (v *validator) codec(field, value string) {
+	if _, err := codec.Lookup(value); err != nil {
+		v.fail(field, "%v", err)
+	}
+}
+
+func (v *validator) namespace(field, value string) {
+	if _, err := celestia.ParseNamespace(value); err != nil {
+		v.fail(field, "invalid namespace, want the hex of at most 10 bytes or of a 29 bytes namespace: %v", err)
+	}
+}
+
+// Validate checks every enabled section and returns a ValidationError listing all invalid fields.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.Anytrust.Enabled {
+		v.codec("anytrust.codec", c.Anytrust.Codec)
+		v.url("anytrust.rpc_url", c.Anytrust.RpcUrl, "http", "https", "ws", "wss")
+		v.url("anytrust.restful_url", c.Anytrust.RestfulUrl, "http", "https")
+		if c.Anytrust.DataRetentionTime == 0 {
+			v.fail("anytrust.data_retention_time", "must be positive")
+		}
+		if c.Anytrust.RandomMessageSize < 0 {
+			v.fail("anytrust.random_message_size", "must not be negative")
+		}
+		if key, ok := strings.CutPrefix(c.Anytrust.SigningKey, "0x"); ok {
+			if _, err := crypto.HexToECDSA(key); err != nil {
+				v.fail("anytrust.signing_key", "invalid private key: %v", err)
+			}
+		}
+	}
+
+	if c.AnytrustCommittee.Enabled {
+		v.codec("anytrust_committee.codec", c.AnytrustCommittee.Codec)
+	}
+
+	if c.Celestia.Enabled {
+		v.codec("celestia.codec", c.Celestia.Codec)
+		v.hostPort("celestia.da_rpc", c.Celestia.DaRpc)
+		if len(c.Celestia.Namespace) != 0 {
+			v.namespace("celestia.namespace", c.Celestia.Namespace)
+		}
+		for tenant, namespace := range c.Celestia.Tenants {
+			v.namespace("celestia.tenants."+tenant, namespace)
+		}
+		if err := c.Celestia.Fee.Check(); err != nil {
+			v.fail("celestia.fee", "%v", err)
+		}
+		if c.Celestia.Blobstream.Enabled() {
+			v.url("celestia.blobstream.l1_rpc", c.Celestia.Blobstream.L1Rpc, "http", "https", "ws", "wss")
+			if v.required("celestia.blobstream.contract", c.Celestia.Blobstream.Contract) {
+				v.address("celestia.blobstream.contract", c.Celestia.Blobstream.Contract)
+			}
+		}
+	}
+
+	if c.EigenDA.Enabled {
+		v.codec("eigenda.codec", c.EigenDA.Codec)
+		v.hostPort("eigenda.rpc", c.EigenDA.RPC)
+		if c.EigenDA.StatusQueryTimeout <= 0 {
+			v.fail("eigenda.status_query_timeout", "must be positive")
+		}
+		if c.EigenDA.StatusQueryRetryInterval <= 0 {
+			v.fail("eigenda.status_query_retry_interval", "must be positive")
+		} else if c.EigenDA.StatusQueryRetryInterval > c.EigenDA.StatusQueryTimeout {
+			v.fail("eigenda.status_query_retry_interval", "must not exceed status_query_timeout")
+		}
+	}
+
+	if c.Eip4844.Enabled {
+		v.codec("eip4844.codec", c.Eip4844.Codec)
+		v.url("eip4844.l1_rpc", c.Eip4844.L1Rpc, "http", "https", "ws", "wss")
+		if c.Eip4844.L1ChainID == 0 {
+			v.fail("eip4844.l1_chain_id", "is required")
+		}
+		if v.required("eip4844.private_key", c.Eip4844.PrivateKey) {
+			if _, err := crypto.HexToECDSA(strings.TrimPrefix(c.Eip4844.PrivateKey, "0x")); err != nil {
+				v.fail("eip4844.private_key", "invalid private key: %v", err)
+			}
+		}
+		if c.Eip4844.UseBlobs || len(c.Eip4844.L1BeaconAddr) != 0 {
+			v.url("eip4844.l1_beacon_addr", c.Eip4844.L1BeaconAddr, "http", "https")
+		}
+		v.address("eip4844.batch_inbox_address", c.Eip4844.BatchInboxAddress)
+		v.address("eip4844.batcher_addr", c.Eip4844.BatcherAddr)
+	}
+
+	if c.NearDA.Enabled {
+		v.codec("nearda.codec", c.NearDA.Codec)
+		v.required("nearda.account", c.NearDA.Account)
+		v.required("nearda.contract", c.NearDA.Contract)
+		v.required("nearda.key", c.NearDA.Key)
+		switch c.NearDA.Network {
+		case "Mainnet", "Testnet", "Localnet":
+		default:
+			v.fail("nearda.network", "must be one of Mainnet, Testnet, Localnet, got %q", c.NearDA.Network)
+		}
+	}
+
+	if err := c.Aggregator.Check(); err != nil {
+		v.fail("aggregator", "%v", err)
+	}
+	if err := c.Chunking.Check(); err != nil {
+		v.fail("chunking", "%v", err)
+	}
+	if err := c.Erasure.Check(); err != nil {
+		v.fail("erasure", "%v", err)
+	}
+	if c.Content.MaxEntries <= 0 {
+		v.fail("content.max_entries", "must be positive")
+	}
+	if err := c.Cache.Check(); err != nil {
+		v.fail("cache", "%v", err)
+	}
+	if _, err := fault.ParseConfigs(c.Faults); err != nil {
+		v.fail("faults", "%v", err)
+	}
+	if err := c.Pricing.Check(); err != nil {
+		v.fail("pricing", "%v", err)
+	}
+	if c.Devnet.Enabled {
+		if err := c.Devnet.Check(); err != nil {
+			v.fail("devnet", "%v", err)
+		} else {
+			// the mocked DAs still compress their payloads with the codec of their section
+			daTypes, _ := c.Devnet.DATypes()
+			for _, daType := range daTypes {
+				v.codec(c.codec(daType))
+			}
+		}
+	}
+
+	if len(c.Encryption.DefaultKey) != 0 {
+		v.required("encryption.keyring", c.Encryption.Keyring)
+	}
+
+	if len(v.errs) != 0 {
+		return v.errs
+	}
+	return nil
+}
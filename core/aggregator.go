@@ -0,0 +1,246 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+// aggregator buffers the small submissions to a DA and rolls them up packed in a single blob,
+// once the first one waited for the window or the blob is full.
+type aggregator struct {
+	r      *RollupModule
+	daType int
+	conf   aggregate.Config
+
+	mu      sync.Mutex
+	pending *batch
+}
+
+// batch is the set of submissions packed in the same blob.
+type batch struct {
+	payloads []*pendingPayload
+	// size is the size of the packed blob
+	size  int
+	timer *time.Timer
+}
+
+type pendingPayload struct {
+	data []byte
+	// done receives the receipt of the payload once its batch is stored
+	done chan aggregateResult
+}
+
+type aggregateResult struct {
+	res []interface{}
+	err error
+}
+
+// aggregatorFor returns the aggregator of daType, nil when its submissions aren't aggregated.
+func (r *RollupModule) aggregatorFor(daType int) *aggregator {
+	conf := r.config().Aggregator
+	if daTypes, err := conf.DATypes(); err != nil || !daTypes[daType] {
+		return nil
+	}
+	r.aggregatorsMu.Lock()
+	defer r.aggregatorsMu.Unlock()
+	a := r.aggregators[daType]
+	if a == nil || !reflect.DeepEqual(a.conf, conf) {
+		// the pending batch of a replaced aggregator is stored right away, a shutdown doesn't see it
+		if a != nil {
+			a.flush()
+		}
+		if r.aggregators == nil {
+			r.aggregators = make(map[int]*aggregator)
+		}
+		a = &aggregator{r: r, daType: daType, conf: conf}
+		r.aggregators[daType] = a
+	}
+	return a
+}
+
+// flushAggregators stores the pending batches of every aggregator.
+func (r *RollupModule) flushAggregators() {
+	r.aggregatorsMu.Lock()
+	defer r.aggregatorsMu.Unlock()
+	for _, a := range r.aggregators {
+		a.flush()
+	}
+}
+
+// submit adds data to the pending batch and waits for its receipt. The waiting caller counts as
+// in flight, so a shutdown stores the pending batches before closing the DA clients. A caller
+// giving up doesn't take its payload out of the batch.
+func (a *aggregator) submit(ctx context.Context, data []byte) ([]interface{}, error) {
+	if !a.r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer a.r.inflight.Done()
+
+	p := &pendingPayload{data: data, done: make(chan aggregateResult, 1)}
+	a.add(p)
+	select {
+	case res := <-p.done:
+		return res.res, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (a *aggregator) add(p *pendingPayload) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending != nil && a.pending.size+4+len(p.data) > a.conf.MaxSize {
+		a.flushLocked()
+	}
+	if a.pending == nil {
+		b := &batch{size: aggregate.Overhead(0)}
+		b.timer = time.AfterFunc(a.conf.Window, func() { a.flushBatch(b) })
+		a.pending = b
+	}
+	a.pending.payloads = append(a.pending.payloads, p)
+	a.pending.size += 4 + len(p.data)
+	// a shutdown doesn't wait for the window
+	if a.pending.size >= a.conf.MaxSize || a.r.stopped.Load() {
+		a.flushLocked()
+	}
+}
+
+// flushBatch stores b when its window elapsed, unless it was flushed for being full meanwhile.
+func (a *aggregator) flushBatch(b *batch) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending == b {
+		a.flushLocked()
+	}
+}
+
+// flush stores the pending batch right away.
+func (a *aggregator) flush() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending != nil {
+		a.flushLocked()
+	}
+}
+
+func (a *aggregator) flushLocked() {
+	b := a.pending
+	a.pending = nil
+	b.timer.Stop()
+	a.r.flushing.Add(1)
+	go func() {
+		defer a.r.flushing.Done()
+		a.store(b)
+	}()
+}
+
+// store rolls up the packed batch and hands every submission its receipt.
+func (a *aggregator) store(b *batch) {
+	payloads := make([][]byte, len(b.payloads))
+	for i, p := range b.payloads {
+		payloads[i] = p.data
+	}
+	blob, receipts := aggregate.Pack(payloads)
+
+	// the DA request outlives the callers and the module context, a shutdown waits for it
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(a.r.ctx), a.conf.FlushTimeout)
+	defer cancel()
+	ctx, span := tracer.Start(ctx, "core.StoreAggregate", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(a.daType)),
+		attribute.Int("aggregate.count", len(payloads)),
+		attribute.Int("data.size", len(blob)),
+	))
+	res, err := a.r.dispatchRollup(ctx, blob, a.daType)
+	var daArgs interface{}
+	if err == nil {
+		daArgs, err = retrieveArgs(a.daType, res)
+	}
+	tracing.EndSpan(span, err)
+	if err != nil {
+		log.Error("store aggregate failed", "da-type", _common.DATypeName(a.daType), "count", len(payloads), "err", err)
+		for _, p := range b.payloads {
+			p.done <- aggregateResult{err: err}
+		}
+		return
+	}
+	log.Debug("stored aggregate", "da-type", _common.DATypeName(a.daType), "count", len(payloads), "size", len(blob))
+	for i, p := range b.payloads {
+		receipts[i].DAArgs = daArgs
+		p.done <- aggregateResult{res: compositeResult(a.daType, res, receipts[i].String())}
+	}
+}
+
+// retrieveArgs picks the receipt retrieving a blob from the DA out of the rollup result.
+func retrieveArgs(daType int, res []interface{}) (interface{}, error) {
+	i := receiptIndex(daType)
+	if len(res) <= i {
+		return nil, fmt.Errorf("%s returned %d receipts", _common.DATypeName(daType), len(res))
+	}
+	return res[i], nil
+}
+
+// receiptIndex is the position of the receipt in the rollup result of daType, celestia returns the
+// height of the blob before it.
+func receiptIndex(daType int) int {
+	if daType == _common.CelestiaType {
+		return 1
+	}
+	return 0
+}
+
+// compositeResult is the rollup result of an aggregated or chunked submission stored in the blob
+// the DA returned res for. It keeps the shape of the DA's own results, with the composite receipt
+// in place of the receipt of the blob, res was checked by retrieveArgs.
+func compositeResult(daType int, res []interface{}, receipt string) []interface{} {
+	out := append([]interface{}(nil), res...)
+	out[receiptIndex(daType)] = receipt
+	return out
+}
+
+// retrieveAggregated retrieves the blob an aggregate receipt points at and extracts the payload
+// after checking its inclusion proof.
+func (r *RollupModule) retrieveAggregated(ctx context.Context, daType int, s string) ([]byte, error) {
+	receipt, err := aggregate.ParseReceipt(s)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := r.retrieveFromDAWithType(ctx, daType, receipt.DAArgs)
+	if err != nil {
+		return nil, err
+	}
+	return aggregate.Extract(blob, receipt)
+}
+
+// blobArgs turns an aggregate receipt into the DA receipt of its packed blob and a chunked one into
+// the DA receipt of its manifest, the status, proof and attestation of these submissions are those
+// of that blob. Other args are returned as is.
+func blobArgs(args interface{}) (interface{}, error) {
+	switch {
+	case aggregate.IsReceipt(args):
+		receipt, err := aggregate.ParseReceipt(args.(string))
+		if err != nil {
+			return nil, err
+		}
+		return receipt.DAArgs, nil
+	case chunk.IsReceipt(args):
+		receipt, err := chunk.ParseReceipt(args.(string))
+		if err != nil {
+			return nil, err
+		}
+		return receipt.DAArgs, nil
+	}
+	return args, nil
+}
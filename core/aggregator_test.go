@@ -0,0 +1,191 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestAggregatorBatches(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Aggregator: aggregate.Config{
+		DAs:            []string{"eigenda"},
+		Window:         time.Hour,
+		MaxSize:        aggregate.Overhead(3) + 300,
+		MaxPayloadSize: 100,
+		FlushTimeout:   time.Minute,
+	}})
+	require.NoError(t, err)
+	require.Nil(t, r.aggregatorFor(_common.CelestiaType))
+	a := r.aggregatorFor(_common.EigenDAType)
+	require.NotNil(t, a)
+	require.Same(t, a, r.aggregatorFor(_common.EigenDAType))
+
+	// the batch is stored once the next payload wouldn't fit
+	payloads := make([]*pendingPayload, 4)
+	for i := range payloads {
+		payloads[i] = &pendingPayload{data: bytes.Repeat([]byte{byte(i)}, 100), done: make(chan aggregateResult, 1)}
+		a.add(payloads[i])
+	}
+	a.mu.Lock()
+	require.Len(t, a.pending.payloads, 1)
+	require.Same(t, payloads[3], a.pending.payloads[0])
+	a.mu.Unlock()
+	// eigenda isn't enabled, every submission of the batch gets the error
+	for _, p := range payloads[:3] {
+		res := <-p.done
+		require.ErrorIs(t, res.err, _errors.DANotPreparedErr)
+	}
+
+	// the window stores the batch even when it isn't full
+	r.configMu.Lock()
+	r.RollupConfig = &_config.RollupConfig{Aggregator: aggregate.Config{
+		DAs:            []string{"eigenda"},
+		Window:         10 * time.Millisecond,
+		MaxSize:        aggregate.Overhead(3) + 300,
+		MaxPayloadSize: 100,
+		FlushTimeout:   time.Minute,
+	}}
+	r.configMu.Unlock()
+	require.NotSame(t, a, r.aggregatorFor(_common.EigenDAType))
+	_, err = r.RollupWithTypeContext(ctx, []byte("small batch"), _common.EigenDAType)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+}
+
+func TestAggregatorFlushOnStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	devnet := mockda.DefaultConfig()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet: &devnet,
+		Aggregator: aggregate.Config{
+			DAs:            []string{"eigenda"},
+			Window:         time.Hour,
+			MaxSize:        aggregate.Overhead(3) + 300,
+			MaxPayloadSize: 100,
+			FlushTimeout:   time.Minute,
+		},
+	})
+	require.NoError(t, err)
+
+	type result struct {
+		res []interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := r.RollupWithTypeContext(context.Background(), []byte("small batch"), _common.EigenDAType)
+		done <- result{res, err}
+	}()
+	a := r.aggregatorFor(_common.EigenDAType)
+	require.Eventually(t, func() bool {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		return a.pending != nil
+	}, 5*time.Second, time.Millisecond)
+
+	// the module context ending doesn't drop the batch, the shutdown stores it without waiting for the window
+	cancel()
+	require.NoError(t, r.Stop(context.Background()))
+	select {
+	case res := <-done:
+		require.NoError(t, res.err)
+		require.True(t, aggregate.IsReceipt(res.res[0]))
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending batch not stored on stop")
+	}
+}
+
+func TestRollupResultShape(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	var (
+		names      []string
+		chunkSizes = make(map[string]int)
+	)
+	for _, daType := range _common.DATypes {
+		names = append(names, _common.DATypeName(daType))
+		chunkSizes[_common.DATypeName(daType)] = 4096
+	}
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet: &devnet,
+		Aggregator: aggregate.Config{
+			DAs:            names,
+			Window:         10 * time.Millisecond,
+			MaxSize:        aggregate.Overhead(4) + 400,
+			MaxPayloadSize: 100,
+			FlushTimeout:   time.Minute,
+		},
+		Chunking: chunk.Config{DAs: names, ChunkSizes: chunkSizes, Parallelism: 2},
+		Erasure:  erasure.Config{DataShards: 2, ParityShards: 1, DAs: []string{"celestia", "eigenda", "nearda"}},
+	})
+	require.NoError(t, err)
+
+	direct := bytes.Repeat([]byte("rollup batch "), 100)
+	small := []byte("small rollup batch")
+	large := bytes.Repeat([]byte("large rollup batch "), 1000)
+	for _, daType := range _common.DATypes {
+		name := _common.DATypeName(daType)
+		want, err := r.RollupWithTypeContext(ctx, direct, daType)
+		require.NoError(t, err, name)
+
+		// aggregated and chunked submissions answer like the DA, their receipt in place of the DA's one
+		for _, data := range [][]byte{small, large} {
+			res, err := r.RollupWithTypeContext(ctx, data, daType)
+			require.NoError(t, err, name)
+			require.Len(t, res, len(want), name)
+			for i := range res {
+				if i == receiptIndex(daType) {
+					require.True(t, aggregate.IsReceipt(res[i]) || chunk.IsReceipt(res[i]), name)
+					continue
+				}
+				require.IsType(t, want[i], res[i], name)
+			}
+			args, err := retrieveArgs(daType, res)
+			require.NoError(t, err, name)
+			got, err := r.RetrieveFromDAWithTypeContext(ctx, daType, args)
+			require.NoError(t, err, name)
+			require.Equal(t, data, got, name)
+		}
+	}
+
+	res, err := r.RollupWithTypeContext(ctx, large, _common.ErasureType)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.True(t, erasure.IsReceipt(res[0]))
+	args, err := retrieveArgs(_common.ErasureType, res)
+	require.NoError(t, err)
+	got, err := r.RetrieveFromDAWithTypeContext(ctx, _common.ErasureType, args)
+	require.NoError(t, err)
+	require.Equal(t, large, got)
+}
+
+func TestAggregateReceiptArgs(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{})
+	require.NoError(t, err)
+
+	_, receipts := aggregate.Pack([][]byte{[]byte("small batch")})
+	receipts[0].DAArgs = uint64(12)
+	args, err := blobArgs(receipts[0].String())
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), args)
+	args, err = blobArgs("0x0a0b")
+	require.NoError(t, err)
+	require.Equal(t, "0x0a0b", args)
+
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.CelestiaType, receipts[0].String())
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.CelestiaType, aggregate.ReceiptPrefix+"!")
+	require.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+)
+
+func (r *RollupModule) AttestationWithType(daType int, args interface{}) (*_common.Attestation, error) {
+	return r.AttestationWithTypeContext(r.ctx, daType, args)
+}
+
+// AttestationWithTypeContext proves that the data of a receipt returned by RollupWithType was attested
+// on L1 and returns the calldata of the on-chain verifier. Only celestia is attested, by blobstream.
+func (r *RollupModule) AttestationWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error) {
+	ctx, span := tracer.Start(ctx, "core.AttestationWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+	))
+	res, err := r.attestationWithType(ctx, daType, args)
+	r.checkBackendOnError(daType, err)
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+func (r *RollupModule) attestationWithType(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error) {
+	if !r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+
+	args, err := blobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if r.mocked(daType) {
+		return nil, _errors.AttestationNotSupportedErr
+	}
+	switch daType {
+	case _common.CelestiaType:
+		celestiaDA, release, ok := acquire(&r.celestiaDA)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "celestiaDA")
+			return nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		receiptStr, ok := args.(string)
+		if !ok {
+			log.Error("args is not string type")
+			return nil, _errors.WrongArgTypeErr
+		}
+		receipt, err := celestia.ParseReceipt(receiptStr)
+		if err != nil {
+			log.Error("parse celestia receipt failed", "err", err, "receipt", receiptStr)
+			return nil, err
+		}
+
+		proof, err := celestiaDA.VerifyBlobstream(ctx, receipt)
+		if err != nil {
+			log.Error("verify celestia blob against blobstream failed", "err", err, "receipt", receiptStr)
+			return nil, err
+		}
+		return &_common.Attestation{
+			DAType: _common.CelestiaType,
+			Detail: map[string]string{
+				"height":          strconv.FormatUint(receipt.Height, 10),
+				"nonce":           strconv.FormatUint(proof.Nonce, 10),
+				"start_block":     strconv.FormatUint(proof.StartBlock, 10),
+				"end_block":       strconv.FormatUint(proof.EndBlock, 10),
+				"data_root":       proof.DataRoot.Hex(),
+				"data_commitment": proof.DataCommitment.Hex(),
+			},
+			Calldata: hexutil.Encode(proof.Calldata),
+		}, nil
+
+	case _common.AnytrustType, _common.EigenDAType, _common.Eip4844Type, _common.NearDAType, _common.AnytrustCommitteeType,
+		_common.ErasureType:
+		return nil, _errors.AttestationNotSupportedErr
+	default:
+		log.Error("AttestationWithType got unknown da type", "daType", daType, "expected", "[0,5]")
+	}
+	return nil, _errors.UnknownDATypeErr
+}
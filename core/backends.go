@@ -0,0 +1,282 @@
+package core
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/cliapp"
+	"github.com/eniac-x-labs/rollup-node/common/supervisor"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/anytrust"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+	"github.com/eniac-x-labs/rollup-node/x/eigenda"
+	"github.com/eniac-x-labs/rollup-node/x/eip4844"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+	"github.com/eniac-x-labs/rollup-node/x/nearda"
+)
+
+type daClient interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type closableClient interface {
+	daClient
+	Close() error
+}
+
+type stoppableClient interface {
+	daClient
+	Stop(ctx context.Context) error
+}
+
+func closeClient[T closableClient](_ context.Context, client T) error {
+	return client.Close()
+}
+
+func stopClient[T stoppableClient](ctx context.Context, client T) error {
+	return client.Stop(ctx)
+}
+
+// supervise makes a first attempt to build the client, a failed one is retried in the
+// background once the module started.
+func supervise[T daClient](ctx context.Context, daType int, build func(ctx context.Context) (T, error),
+	close func(ctx context.Context, client T) error) *supervisor.Supervisor[T] {
+	return superviseNamed(ctx, _common.DATypeName(daType), build, close)
+}
+
+func superviseNamed[T daClient](ctx context.Context, name string, build func(ctx context.Context) (T, error),
+	close func(ctx context.Context, client T) error) *supervisor.Supervisor[T] {
+	s := supervisor.New(name, supervisor.DefaultConfig(), build, func(ctx context.Context, client T) error {
+		return client.HealthCheck(ctx)
+	}, close)
+	if err := s.Init(ctx); err != nil {
+		log.Error("DA client not ready, retrying in background", "da-type", name, "err", err)
+	} else {
+		log.Debug("DA client ready", "da-type", name)
+	}
+	return s
+}
+
+// backend is a DA client slot of the module, its supervisor is replaced when its config changes.
+type backend struct {
+	name string
+	// changed reports whether the config of the backend differs between prev and next
+	changed func(prev, next *_config.RollupConfig) bool
+	// current returns the supervisor in the slot, nil when the backend is disabled
+	current func() cliapp.Lifecycle
+	// replace supervises the client configured by conf in place of the current one
+	replace func(ctx context.Context, conf *_config.RollupConfig) error
+}
+
+func newBackend[T any](r *RollupModule, name string, slot *atomic.Pointer[supervisor.Supervisor[T]],
+	config func(conf *_config.RollupConfig) interface{},
+	supervise func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[T]) backend {
+	return backend{
+		name: name,
+		changed: func(prev, next *_config.RollupConfig) bool {
+			return !reflect.DeepEqual(config(prev), config(next))
+		},
+		current: func() cliapp.Lifecycle {
+			if s := slot.Load(); s != nil {
+				return s
+			}
+			return nil
+		},
+		replace: func(ctx context.Context, conf *_config.RollupConfig) error {
+			next := supervise(ctx, conf)
+			if next != nil && r.running {
+				if err := next.Start(ctx); err != nil {
+					return err
+				}
+			}
+			// new requests use next from now on, prev closes its client once its requests finished
+			if prev := slot.Swap(next); prev != nil {
+				r.retire(name, prev)
+			}
+			return nil
+		},
+	}
+}
+
+// retire stops a replaced supervisor in the background, its drain is bounded by RetireTimeout
+// rather than the context of the reload so a slow request doesn't hold up the reload.
+func (r *RollupModule) retire(name string, prev cliapp.Lifecycle) {
+	r.retiring.Add(1)
+	go func() {
+		defer r.retiring.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), RetireTimeout)
+		defer cancel()
+		if err := prev.Stop(ctx); err != nil {
+			r.Log.Warn("failed to stop replaced DA backend", "backend", name, "err", err)
+			return
+		}
+		r.Log.Debug("replaced DA backend stopped", "backend", name)
+	}()
+}
+
+// acquire returns the client in slot for a request, release must be called once the request is done.
+func acquire[T any](slot *atomic.Pointer[supervisor.Supervisor[T]]) (client T, release func(), ok bool) {
+	for {
+		s := slot.Load()
+		client, release, ok = s.Acquire()
+		// a failed acquire may race with a reload stopping s, retry with its replacement
+		if ok || slot.Load() == s {
+			return client, release, ok
+		}
+	}
+}
+
+// backends lists the DA client slots in start order.
+func (r *RollupModule) backends() []backend {
+	return []backend{
+		newBackend(r, "devnet", &r.devnet, func(conf *_config.RollupConfig) interface{} {
+			return conf.Devnet
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[*mockda.Devnet] {
+			if conf.Devnet == nil {
+				return nil
+			}
+			return superviseNamed(ctx, "devnet", func(ctx context.Context) (*mockda.Devnet, error) {
+				return mockda.NewDevnet(*conf.Devnet)
+			}, closeClient[*mockda.Devnet])
+		}),
+		newBackend(r, "celestia", &r.celestiaDA, func(conf *_config.RollupConfig) interface{} {
+			return conf.CelestiaDAConfig
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[*celestia.CelestiaRollup] {
+			if conf.CelestiaDAConfig == nil {
+				return nil
+			}
+			return supervise(ctx, _common.CelestiaType, func(ctx context.Context) (*celestia.CelestiaRollup, error) {
+				return celestia.NewCelestiaRollupWithConfig(ctx, conf.CelestiaDAConfig)
+			}, stopClient[*celestia.CelestiaRollup])
+		}),
+		newBackend(r, "eip4844", &r.eip4844, func(conf *_config.RollupConfig) interface{} {
+			return []interface{}{conf.Eip4844Config, conf.Eip4844CLICfg}
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[*eip4844.Eip4844Rollup] {
+			if conf.Eip4844Config == nil {
+				return nil
+			}
+			return supervise(ctx, _common.Eip4844Type, func(ctx context.Context) (*eip4844.Eip4844Rollup, error) {
+				e, err := eip4844.NewEip4844WithConfig(ctx, conf.Eip4844CLICfg, conf.Eip4844Config)
+				if err != nil {
+					return nil, err
+				}
+				e.Metrics = r.metrics
+				return e, nil
+			}, stopClient[*eip4844.Eip4844Rollup])
+		}),
+		newBackend(r, "eigenda", &r.eigenDA, func(conf *_config.RollupConfig) interface{} {
+			return conf.EigenDAConfig
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[eigenda.IEigenDA] {
+			if conf.EigenDAConfig == nil {
+				return nil
+			}
+			return supervise(ctx, _common.EigenDAType, func(ctx context.Context) (eigenda.IEigenDA, error) {
+				client, err := eigenda.NewEigenDAClient(conf.EigenDAConfig)
+				if err != nil {
+					return nil, err
+				}
+				if c, ok := client.(*eigenda.EigenDAClient); ok {
+					c.Metrics = r.metrics
+				}
+				return client, nil
+			}, closeClient[eigenda.IEigenDA])
+		}),
+		newBackend(r, "anytrust", &r.anytrustDA, func(conf *_config.RollupConfig) interface{} {
+			return conf.AnytrustDAConfig
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[anytrust.IAnytrustDA] {
+			if conf.AnytrustDAConfig == nil {
+				return nil
+			}
+			return supervise(ctx, _common.AnytrustType, func(ctx context.Context) (anytrust.IAnytrustDA, error) {
+				return anytrust.NewAnytrustDA(conf.AnytrustDAConfig)
+			}, closeClient[anytrust.IAnytrustDA])
+		}),
+		newBackend(r, "anytrust-das-committee", &r.anytrustCommittee, func(conf *_config.RollupConfig) interface{} {
+			return conf.AnytrustCommitteeConfig
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[anytrust.IAnytrustDA] {
+			if conf.AnytrustCommitteeConfig == nil {
+				return nil
+			}
+			// the committee's background services live as long as the module, not the build attempt
+			moduleCtx := r.ctx
+			return supervise(ctx, _common.AnytrustCommitteeType, func(ctx context.Context) (anytrust.IAnytrustDA, error) {
+				return anytrust.NewAnytrustDAWithCommittee(moduleCtx, conf.AnytrustCommitteeConfig, nil)
+			}, closeClient[anytrust.IAnytrustDA])
+		}),
+		newBackend(r, "nearda", &r.nearDA, func(conf *_config.RollupConfig) interface{} {
+			return conf.NearDAConfig
+		}, func(ctx context.Context, conf *_config.RollupConfig) *supervisor.Supervisor[nearda.INearDA] {
+			if conf.NearDAConfig == nil {
+				return nil
+			}
+			return supervise(ctx, _common.NearDAType, func(ctx context.Context) (nearda.INearDA, error) {
+				return nearda.NewNearDAClient(conf.NearDAConfig)
+			}, closeClient[nearda.INearDA])
+		}),
+	}
+}
+
+// superviseBackends builds every DA client enabled in the config under a supervisor,
+// the slots of disabled backends stay empty.
+func (r *RollupModule) superviseBackends(ctx context.Context) {
+	for _, b := range r.backends() {
+		// nothing to start or stop before the module runs
+		_ = b.replace(ctx, r.RollupConfig)
+	}
+}
+
+// checkBackendOnError triggers an early health check of daType when err may come from a broken connection.
+func (r *RollupModule) checkBackendOnError(daType int, err error) {
+	switch errorCode(err) {
+	case "da_error", "timeout":
+		r.notifyError(daType)
+	}
+}
+
+// notifyError asks the supervisor of daType for an early health check after a failed request.
+func (r *RollupModule) notifyError(daType int) {
+	if r.mocked(daType) {
+		r.devnet.Load().NotifyError()
+		return
+	}
+	switch daType {
+	case _common.AnytrustType:
+		r.anytrustDA.Load().NotifyError()
+	case _common.CelestiaType:
+		r.celestiaDA.Load().NotifyError()
+	case _common.EigenDAType:
+		r.eigenDA.Load().NotifyError()
+	case _common.Eip4844Type:
+		r.eip4844.Load().NotifyError()
+	case _common.NearDAType:
+		r.nearDA.Load().NotifyError()
+	case _common.AnytrustCommitteeType:
+		r.anytrustCommittee.Load().NotifyError()
+	}
+}
+
+// backendState returns the supervisor state of daType.
+func (r *RollupModule) backendState(daType int) (supervisor.State, error) {
+	if r.mocked(daType) {
+		return r.devnet.Load().State()
+	}
+	switch daType {
+	case _common.AnytrustType:
+		return r.anytrustDA.Load().State()
+	case _common.CelestiaType:
+		return r.celestiaDA.Load().State()
+	case _common.EigenDAType:
+		return r.eigenDA.Load().State()
+	case _common.Eip4844Type:
+		return r.eip4844.Load().State()
+	case _common.NearDAType:
+		return r.nearDA.Load().State()
+	case _common.AnytrustCommitteeType:
+		return r.anytrustCommittee.Load().State()
+	}
+	return "", nil
+}
@@ -0,0 +1,35 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/cache"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+func TestRetrieveThroughCache(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Cache: cache.Config{MemoryBytes: 1 << 10}})
+	require.NoError(t, err)
+
+	// anytrust isn't enabled, only the cache serves its data
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.AnytrustType, "0x0A0B")
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+
+	key, ok := cache.Key(_common.AnytrustType, "0x0A0B")
+	require.True(t, ok)
+	r.cache.Put(_common.AnytrustType, key, []byte("cached batch"), time.Time{})
+	data, err := r.RetrieveFromDAWithTypeContext(ctx, _common.AnytrustType, "0a0b")
+	require.NoError(t, err)
+	require.Equal(t, []byte("cached batch"), data)
+
+	// a bare celestia height is never served from the cache
+	_, ok = cache.Key(_common.CelestiaType, uint64(42))
+	require.False(t, ok)
+}
@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+)
+
+// rollupChunked stores data too large for the DA in chunks of size bytes, then the manifest listing
+// them, and returns the result of the manifest with the chunked receipt in place of its receipt.
+func (r *RollupModule) rollupChunked(ctx context.Context, data []byte, daType int, size int) ([]interface{}, error) {
+	chunks := chunk.Split(data, size)
+	manifest := chunk.NewManifest(data, chunks)
+	// rejected before any chunk is stored, the manifest must fit a chunk once it lists their receipts
+	if bound := manifest.EncodedSizeBound(chunk.MaxReceiptSizes[daType]); bound > size {
+		return nil, fmt.Errorf("manifest of %d chunks may take %d bytes, above the chunk size of %s", len(chunks), bound, _common.DATypeName(daType))
+	}
+	err := r.eachChunk(ctx, len(chunks), func(ctx context.Context, i int) error {
+		res, err := r.rollupWithType(ctx, chunks[i], daType)
+		if err != nil {
+			return fmt.Errorf("store chunk %d: %w", i, err)
+		}
+		manifest.Chunks[i].DAArgs, err = retrieveArgs(daType, res)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := manifest.Encode()
+	if len(encoded) > size {
+		return nil, fmt.Errorf("manifest of %d chunks takes %d bytes, above the chunk size of %s", len(chunks), len(encoded), _common.DATypeName(daType))
+	}
+	res, err := r.rollupWithType(ctx, encoded, daType)
+	if err != nil {
+		return nil, fmt.Errorf("store manifest: %w", err)
+	}
+	args, err := retrieveArgs(daType, res)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug("stored chunked data", "da-type", _common.DATypeName(daType), "chunks", len(chunks), "size", len(data))
+	return compositeResult(daType, res, chunk.NewReceipt(args, encoded).String()), nil
+}
+
+// retrieveChunked retrieves the manifest a chunked receipt points at, then its chunks, and returns
+// them joined once each of them and the whole object match the manifest.
+func (r *RollupModule) retrieveChunked(ctx context.Context, daType int, s string) ([]byte, error) {
+	receipt, err := chunk.ParseReceipt(s)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.retrieveFromDAWithType(ctx, daType, receipt.DAArgs)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve manifest: %w", err)
+	}
+	manifest, err := receipt.OpenManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([][]byte, len(manifest.Chunks))
+	err = r.eachChunk(ctx, len(chunks), func(ctx context.Context, i int) error {
+		data, err := r.retrieveFromDAWithType(ctx, daType, manifest.Chunks[i].DAArgs)
+		if err != nil {
+			return fmt.Errorf("retrieve chunk %d: %w", i, err)
+		}
+		if err := manifest.Check(i, data); err != nil {
+			return err
+		}
+		chunks[i] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Assemble(chunks)
+}
+
+// eachChunk runs fn for the n chunks, at most parallelism of them at once. The first error cancels
+// the chunks left and is returned.
+func (r *RollupModule) eachChunk(ctx context.Context, n int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	parallelism := r.config().Chunking.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	sem := make(chan struct{}, parallelism)
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := fn(ctx, i); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
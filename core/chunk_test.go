@@ -0,0 +1,82 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+func TestEachChunk(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Chunking: chunk.Config{Parallelism: 2}})
+	require.NoError(t, err)
+
+	var running, peak, calls atomic.Int32
+	err = r.eachChunk(ctx, 8, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 8, calls.Load())
+	require.EqualValues(t, 2, peak.Load())
+
+	// the first error cancels the chunks left
+	failed := errors.New("chunk failed")
+	calls.Store(0)
+	err = r.eachChunk(ctx, 8, func(ctx context.Context, i int) error {
+		calls.Add(1)
+		if i == 1 {
+			return failed
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.ErrorIs(t, err, failed)
+	require.Less(t, calls.Load(), int32(8))
+}
+
+func TestChunkedRollup(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Chunking: chunk.Config{
+		DAs:         []string{"eigenda"},
+		ChunkSizes:  map[string]int{"eigenda": 1024},
+		Parallelism: 2,
+	}})
+	require.NoError(t, err)
+
+	// eigenda isn't enabled, the first chunk fails the submission
+	_, err = r.RollupWithTypeContext(ctx, bytes.Repeat([]byte("large rollup batch "), 100), _common.EigenDAType)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+	require.Equal(t, "not_prepared", errorCode(err))
+
+	// a manifest that may not fit a chunk is rejected before any chunk is submitted
+	_, err = r.RollupWithTypeContext(ctx, bytes.Repeat([]byte("large rollup batch "), 1000), _common.EigenDAType)
+	require.ErrorContains(t, err, "manifest of 19 chunks")
+	require.NotErrorIs(t, err, _errors.DANotPreparedErr)
+
+	receipt := chunk.NewReceipt("0x0a0b", []byte("manifest")).String()
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.EigenDAType, receipt)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+	args, err := blobArgs(receipt)
+	require.NoError(t, err)
+	require.Equal(t, "0x0a0b", args)
+}
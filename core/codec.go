@@ -0,0 +1,38 @@
+package core
+
+import (
+	"context"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	"github.com/eniac-x-labs/rollup-node/metrics"
+)
+
+// codecFor returns the codec a rollup request to daType is compressed with, the codec named in ctx
+// overrides the one configured for the DA. A nil codec stores the payload raw.
+func (r *RollupModule) codecFor(ctx context.Context, daType int) (codec.Codec, error) {
+	name := _common.CodecFromContext(ctx)
+	if len(name) == 0 {
+		name = r.config().Codecs[daType]
+	}
+	return codec.Lookup(name)
+}
+
+// encode compresses the payload of a rollup request behind the codec envelope.
+func (r *RollupModule) encode(ctx context.Context, daType int, data []byte) ([]byte, codec.ID, error) {
+	c, err := r.codecFor(ctx, daType)
+	if err != nil {
+		return nil, codec.None, err
+	}
+	return codec.Encode(c, data)
+}
+
+// decode strips the codec envelope of a retrieved payload, payloads without one were stored raw.
+func (r *RollupModule) decode(daType int, data []byte) ([]byte, error) {
+	decoded, id, err := codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	r.metrics.RecordCodec(metrics.OpRetrieve, _common.DATypeName(daType), codec.Name(id), len(decoded), len(data))
+	return decoded, nil
+}
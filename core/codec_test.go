@@ -0,0 +1,89 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestCodecPipeline(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Codecs: map[int]string{_common.CelestiaType: "zstd", _common.EigenDAType: "none"},
+	})
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("rollup batch "), 256)
+
+	// the DA's configured codec
+	encoded, id, err := r.encode(ctx, _common.CelestiaType, data)
+	require.NoError(t, err)
+	require.Equal(t, codec.Zstd, id)
+	decoded, err := r.decode(_common.CelestiaType, encoded)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	encoded, id, err = r.encode(ctx, _common.EigenDAType, data)
+	require.NoError(t, err)
+	require.Equal(t, codec.None, id)
+	decoded, err = r.decode(_common.EigenDAType, encoded)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	// the request's codec overrides it, retrieval decodes whatever the payload was stored with
+	encoded, id, err = r.encode(_common.WithCodec(ctx, "brotli"), _common.EigenDAType, data)
+	require.NoError(t, err)
+	require.Equal(t, codec.Brotli, id)
+	decoded, err = r.decode(_common.EigenDAType, encoded)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	_, err = r.RollupWithTypeContext(_common.WithCodec(ctx, "lz4"), data, _common.CelestiaType)
+	require.ErrorIs(t, err, codec.ErrUnknownCodec)
+	require.Equal(t, "unknown_codec", errorCode(err))
+
+	// payloads stored before codecs existed read back as is
+	decoded, err = r.decode(_common.CelestiaType, []byte("no envelope"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("no envelope"), decoded)
+}
+
+func TestRetrieveBaselinePayload(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet: &devnet,
+		Codecs: map[int]string{_common.EigenDAType: "zstd"},
+	})
+	require.NoError(t, err)
+	da, release, ok := r.mockFor(_common.EigenDAType)
+	require.True(t, ok)
+	defer release()
+
+	// payloads stored as they were before codecs and encryption, straight on the DA
+	for _, data := range [][]byte{
+		[]byte("plain rollup batch"),
+		{0x01, 0x02, 0x03},
+		append([]byte{0x11}, []byte("not zstd")...),
+	} {
+		res, err := da.Store(ctx, data)
+		require.NoError(t, err)
+		retrieved, err := r.RetrieveFromDAWithTypeContext(ctx, _common.EigenDAType, res[0])
+		require.NoError(t, err)
+		require.Equal(t, data, retrieved)
+	}
+
+	// and without codec the node stores them the same way
+	data := []byte("plain rollup batch")
+	res, err := r.RollupWithTypeContext(_common.WithCodec(ctx, "none"), data, _common.EigenDAType)
+	require.NoError(t, err)
+	stored, err := da.Retrieve(ctx, res[0])
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+}
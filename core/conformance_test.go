@@ -0,0 +1,53 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/conformance"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+// moduleBackend runs the conformance suite through the rollup module, over the mock backends of a devnet.
+type moduleBackend struct {
+	r      *RollupModule
+	daType int
+}
+
+func (b *moduleBackend) Store(ctx context.Context, data []byte) (interface{}, error) {
+	res, err := b.r.RollupWithTypeContext(ctx, data, b.daType)
+	if err != nil {
+		return nil, err
+	}
+	return retrieveArgs(b.daType, res)
+}
+
+func (b *moduleBackend) Retrieve(ctx context.Context, receipt interface{}) ([]byte, error) {
+	return b.r.RetrieveFromDAWithTypeContext(ctx, b.daType, receipt)
+}
+
+func (b *moduleBackend) Close() error {
+	return b.r.Stop(context.Background())
+}
+
+func TestConformance(t *testing.T) {
+	for _, daType := range append(_common.DATypes, _common.ErasureType) {
+		t.Run(_common.DATypeName(daType), func(t *testing.T) {
+			conformance.Run(t, func(t *testing.T) conformance.Backend {
+				devnet := mockda.DefaultConfig()
+				r, err := NewRollupModuleWithConfig(context.Background(), &_config.RollupConfig{
+					Devnet:  &devnet,
+					Erasure: erasure.Config{DataShards: 2, ParityShards: 1, DAs: []string{"celestia", "eigenda", "nearda"}},
+				})
+				require.NoError(t, err)
+				t.Cleanup(func() { _ = r.Stop(context.Background()) })
+				return &moduleBackend{r: r, daType: daType}
+			}, conformance.Options{})
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/content"
+	"github.com/eniac-x-labs/rollup-node/common/supervisor"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+// indexContent records the receipt returned for data, so it can be retrieved by its hash.
+func (r *RollupModule) indexContent(data []byte, daType int, res []interface{}) {
+	args, err := retrieveArgs(daType, res)
+	if err == nil {
+		err = r.contentIndex.Add(data, daType, args)
+	}
+	if err != nil {
+		log.Warn("failed to index content", "da-type", _common.DATypeName(daType), "err", err)
+	}
+}
+
+// RetrieveByHashContext resolves the sha256 or keccak256 of a payload to the receipts this node
+// returned for it and retrieves it from the best available backend, checking the hash on the data.
+func (r *RollupModule) RetrieveByHashContext(ctx context.Context, hash string) (*_common.Content, error) {
+	ctx, span := tracer.Start(ctx, "core.RetrieveByHash", trace.WithAttributes(
+		attribute.String("content.hash", hash),
+	))
+	res, err := r.retrieveByHash(ctx, hash)
+	if res != nil {
+		span.SetAttributes(tracing.DATypeAttr(_common.DATypeName(res.DAType)), attribute.Int("data.size", len(res.Data)))
+	}
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+func (r *RollupModule) retrieveByHash(ctx context.Context, s string) (*_common.Content, error) {
+	hash, err := content.ParseHash(s)
+	if err != nil {
+		return nil, err
+	}
+	entries := r.contentIndex.Lookup(hash)
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w %s", content.ErrNotFound, s)
+	}
+	// the ready backends first, the newest receipt first among equals
+	sort.SliceStable(entries, func(i, j int) bool {
+		return r.backendRank(entries[i].DAType) < r.backendRank(entries[j].DAType)
+	})
+
+	var errs []error
+	for _, entry := range entries {
+		data, err := r.RetrieveFromDAWithTypeContext(ctx, entry.DAType, entry.Receipt)
+		if err == nil && !content.Matches(data, hash) {
+			err = content.ErrHashMismatch
+		}
+		if err == nil {
+			return &_common.Content{DAType: entry.DAType, Receipt: entry.Receipt, Data: data}, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Warn("failed to retrieve content", "da-type", _common.DATypeName(entry.DAType), "err", err)
+		errs = append(errs, fmt.Errorf("%s: %w", _common.DATypeName(entry.DAType), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// backendRank orders the backends to retrieve content from, the lowest first.
+func (r *RollupModule) backendRank(daType int) int {
+	state, _ := r.backendState(daType)
+	switch state {
+	case supervisor.StateReady:
+		return 0
+	case supervisor.StateFailed, supervisor.StateDisabled:
+		return 2
+	}
+	return 1
+}
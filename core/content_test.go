@@ -0,0 +1,85 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/common/content"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestRetrieveByHash(t *testing.T) {
+	ctx := context.Background()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{})
+	require.NoError(t, err)
+	data := []byte("rollup batch")
+	hash := hex.EncodeToString(crypto.Keccak256(data))
+
+	_, err = r.RetrieveByHashContext(ctx, "0x0a0b")
+	require.ErrorIs(t, err, content.ErrInvalidHash)
+	_, err = r.RetrieveByHashContext(ctx, hash)
+	require.ErrorIs(t, err, content.ErrNotFound)
+
+	// the receipts only count once the rollup succeeded
+	_, err = r.RollupWithTypeContext(ctx, data, _common.EigenDAType)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+	_, err = r.RetrieveByHashContext(ctx, hash)
+	require.ErrorIs(t, err, content.ErrNotFound)
+
+	// every receipt is tried, none of the backends is enabled
+	r.indexContent(data, _common.CelestiaType, []interface{}{uint64(12), "12:deadbeef:0a0b:100utia"})
+	r.indexContent(data, _common.EigenDAType, []interface{}{"AAEC"})
+	entries := r.contentIndex.Lookup(crypto.Keccak256(data))
+	require.Len(t, entries, 2)
+	require.Equal(t, "12:deadbeef:0a0b:100utia", entries[1].Receipt)
+	_, err = r.RetrieveByHashContext(ctx, hash)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+	require.ErrorContains(t, err, "celestia")
+	require.ErrorContains(t, err, "eigenda")
+}
+
+func TestRetrieveByHashComposite(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet: &devnet,
+		Aggregator: aggregate.Config{
+			DAs:            []string{"celestia"},
+			Window:         10 * time.Millisecond,
+			MaxSize:        aggregate.Overhead(4) + 400,
+			MaxPayloadSize: 100,
+			FlushTimeout:   time.Minute,
+		},
+		Chunking: chunk.Config{
+			DAs:         []string{"celestia"},
+			ChunkSizes:  map[string]int{"celestia": 2048},
+			Parallelism: 2,
+		},
+	})
+	require.NoError(t, err)
+
+	small := []byte("small rollup batch")
+	large := bytes.Repeat([]byte("large rollup batch "), 200)
+	for _, data := range [][]byte{small, large} {
+		res, err := r.RollupWithTypeContext(ctx, data, _common.CelestiaType)
+		require.NoError(t, err)
+		require.Len(t, res, 2)
+		require.True(t, aggregate.IsReceipt(res[1]) || chunk.IsReceipt(res[1]))
+
+		got, err := r.RetrieveByHashContext(ctx, hex.EncodeToString(crypto.Keccak256(data)))
+		require.NoError(t, err)
+		require.Equal(t, data, got.Data)
+		require.Equal(t, res[1], got.Receipt)
+	}
+}
@@ -0,0 +1,152 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+func (r *RollupModule) EstimateCostWithType(daType int, size int) (*_common.CostEstimate, error) {
+	return r.EstimateCostWithTypeContext(r.ctx, daType, size)
+}
+
+// EstimateCostWithTypeContext estimates what storing size bytes on daType costs at current prices, in the
+// native token of the DA and in the currency of the price source when one is configured. eip4844 and
+// celestia are priced by their fee market, the other DAs by the rates of the pricing config, which
+// override the fee market when set. A size above the chunk size of the DA is priced as its chunks,
+// the manifest aside.
+func (r *RollupModule) EstimateCostWithTypeContext(ctx context.Context, daType int, size int) (*_common.CostEstimate, error) {
+	ctx, span := tracer.Start(ctx, "core.EstimateCostWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+		attribute.Int("data.size", size),
+	))
+	res, err := r.estimateCost(ctx, daType, size)
+	r.checkBackendOnError(daType, err)
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+func (r *RollupModule) estimateCost(ctx context.Context, daType int, size int) (*_common.CostEstimate, error) {
+	if !r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", _errors.WrongArgTypeErr)
+	}
+	if daType == _common.ErasureType {
+		return nil, _errors.CostNotSupportedErr
+	}
+	token, ok := pricing.NativeTokens[daType]
+	if !ok {
+		log.Error("EstimateCostWithType got unknown da type", "daType", daType, "expected", "[0,5]")
+		return nil, _errors.UnknownDATypeErr
+	}
+
+	var (
+		amount *big.Int
+		detail map[string]string
+		err    error
+	)
+	if chunkSize := r.config().Chunking.ChunkSize(daType); chunkSize > 0 && size > chunkSize {
+		amount, detail, err = r.estimateChunked(ctx, daType, size, chunkSize)
+	} else {
+		amount, detail, err = r.estimateSubmission(ctx, daType, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &_common.CostEstimate{
+		DAType:   daType,
+		Size:     size,
+		Amount:   amount.String(),
+		Token:    token.Symbol,
+		Decimals: token.Decimals,
+		Detail:   detail,
+	}
+	if prices := r.prices.Load(); prices != nil {
+		price, err := prices.Price(ctx, token)
+		if err != nil {
+			// the estimate in native tokens stands on its own
+			log.Warn("failed to convert the cost estimate", "token", token.Symbol, "currency", prices.Currency(), "err", err)
+			res.Detail["price_error"] = err.Error()
+		} else {
+			res.Fiat = pricing.Fiat(amount, token, price)
+			res.Currency = prices.Currency()
+			res.Detail["price"] = strconv.FormatFloat(price, 'f', -1, 64)
+		}
+	}
+	return res, nil
+}
+
+// estimateChunked prices the full chunks and the remainder a payload of size bytes is split into.
+func (r *RollupModule) estimateChunked(ctx context.Context, daType int, size int, chunkSize int) (*big.Int, map[string]string, error) {
+	full, rest := size/chunkSize, size%chunkSize
+	amount, detail, err := r.estimateSubmission(ctx, daType, chunkSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	amount.Mul(amount, big.NewInt(int64(full)))
+	if rest > 0 {
+		restAmount, _, err := r.estimateSubmission(ctx, daType, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		amount.Add(amount, restAmount)
+		full++
+	}
+	detail["chunks"] = strconv.Itoa(full)
+	return amount, detail, nil
+}
+
+// estimateSubmission prices a single submission of size bytes in the smallest unit of the native token.
+func (r *RollupModule) estimateSubmission(ctx context.Context, daType int, size int) (*big.Int, map[string]string, error) {
+	if rate, ok := r.config().Pricing.Rate(daType); ok {
+		return rate.Amount(size, pricing.NativeTokens[daType]), map[string]string{
+			"base":     strconv.FormatFloat(rate.Base, 'f', -1, 64),
+			"per_byte": strconv.FormatFloat(rate.PerByte, 'f', -1, 64),
+		}, nil
+	}
+	if r.mocked(daType) {
+		// the mock backends store for free
+		return new(big.Int), map[string]string{"devnet": "true"}, nil
+	}
+
+	switch daType {
+	case _common.CelestiaType:
+		celestiaDA, release, ok := acquire(&r.celestiaDA)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "celestiaDA")
+			return nil, nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		return celestiaDA.EstimateCost(ctx, size)
+
+	case _common.Eip4844Type:
+		eip4844, release, ok := acquire(&r.eip4844)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eip4844")
+			return nil, nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		amount, detail, err := eip4844.EstimateCost(ctx, size)
+		if err != nil {
+			log.Error("estimate eip4844 cost failed", "err", err, "size", size)
+			return nil, nil, err
+		}
+		return amount, detail, nil
+	}
+	return nil, nil, fmt.Errorf("%w for %s, set [pricing.rates.%s]", pricing.ErrNoRate, _common.DATypeName(daType), _common.DATypeName(daType))
+}
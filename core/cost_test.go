@@ -0,0 +1,68 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestEstimateCost(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	devnet.DAs = []string{"celestia", "eip4844", "nearda"}
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet:   &devnet,
+		Chunking: chunk.Config{DAs: []string{"eip4844"}},
+		Pricing: pricing.Config{
+			Rates: map[string]pricing.Rate{
+				"nearda":  {PerByte: 0.00001},
+				"eip4844": {PerByte: 0.000000001},
+			},
+			Currency: "usd",
+			Source:   pricing.SourceStatic,
+			Prices:   map[string]float64{"near": 5, "eth": 3000},
+		},
+	})
+	require.NoError(t, err)
+
+	res, err := r.EstimateCostWithTypeContext(ctx, _common.NearDAType, 1000)
+	require.NoError(t, err)
+	require.Equal(t, "10000000000000000000000", res.Amount)
+	require.Equal(t, "NEAR", res.Token)
+	require.Equal(t, 24, res.Decimals)
+	require.Equal(t, "usd", res.Currency)
+	require.InDelta(t, 0.05, res.Fiat, 1e-9)
+
+	// priced as its chunks of 130044 bytes
+	res, err = r.EstimateCostWithTypeContext(ctx, _common.Eip4844Type, 300_000)
+	require.NoError(t, err)
+	require.Equal(t, "300000000000000", res.Amount)
+	require.Equal(t, "3", res.Detail["chunks"])
+	require.InDelta(t, 0.9, res.Fiat, 1e-9)
+
+	// the mock backends are free, the price of TIA is missing
+	res, err = r.EstimateCostWithTypeContext(ctx, _common.CelestiaType, 1000)
+	require.NoError(t, err)
+	require.Equal(t, "0", res.Amount)
+	require.Equal(t, "TIA", res.Token)
+	require.Empty(t, res.Currency)
+	require.Contains(t, res.Detail, "price_error")
+
+	_, err = r.EstimateCostWithTypeContext(ctx, _common.EigenDAType, 1000)
+	require.ErrorIs(t, err, pricing.ErrNoRate)
+	require.Equal(t, "not_supported", errorCode(err))
+	_, err = r.EstimateCostWithTypeContext(ctx, _common.ErasureType, 1000)
+	require.ErrorIs(t, err, _errors.CostNotSupportedErr)
+	_, err = r.EstimateCostWithTypeContext(ctx, 42, 1000)
+	require.ErrorIs(t, err, _errors.UnknownDATypeErr)
+	_, err = r.EstimateCostWithTypeContext(ctx, _common.NearDAType, 0)
+	require.Error(t, err)
+}
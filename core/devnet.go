@@ -0,0 +1,28 @@
+package core
+
+import (
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+// mocked reports whether daType is served by the devnet mock backends.
+func (r *RollupModule) mocked(daType int) bool {
+	conf := r.config().Devnet
+	return conf != nil && conf.Serves(daType)
+}
+
+// mockFor returns the mock backend serving daType for a request, release must be called once the
+// request is done. ok is false when daType isn't mocked or the devnet isn't ready.
+func (r *RollupModule) mockFor(daType int) (da *mockda.DA, release func(), ok bool) {
+	if !r.mocked(daType) {
+		return nil, nil, false
+	}
+	devnet, release, ok := acquire(&r.devnet)
+	if !ok {
+		return nil, nil, false
+	}
+	if da, ok = devnet.DA(daType); !ok {
+		release()
+		return nil, nil, false
+	}
+	return da, release, true
+}
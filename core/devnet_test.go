@@ -0,0 +1,54 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestDevnet(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet:   &devnet,
+		Codecs:   map[int]string{_common.CelestiaType: "zstd"},
+		Erasure:  erasure.Config{DataShards: 2, ParityShards: 2, DAs: []string{"celestia", "eigenda", "nearda", "anytrust"}},
+		Chunking: chunk.Config{DAs: []string{"eip4844"}, ChunkSizes: map[string]int{"eip4844": 1024}, Parallelism: 2},
+	})
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("rollup batch "), 200)
+	for _, daType := range append(_common.DATypes, _common.ErasureType) {
+		res, err := r.RollupWithTypeContext(ctx, data, daType)
+		require.NoError(t, err, _common.DATypeName(daType))
+		args, err := retrieveArgs(daType, res)
+		require.NoError(t, err)
+		got, err := r.RetrieveFromDAWithTypeContext(ctx, daType, args)
+		require.NoError(t, err, _common.DATypeName(daType))
+		require.Equal(t, data, got)
+	}
+
+	res, err := r.RollupWithTypeContext(ctx, data, _common.EigenDAType)
+	require.NoError(t, err)
+	status, err := r.StatusWithTypeContext(ctx, _common.EigenDAType, res[0])
+	require.NoError(t, err)
+	require.True(t, status.Final)
+	_, err = r.ProofWithTypeContext(ctx, _common.CelestiaType, "1:00000000000000000000000000000000000000000000deadbeef:00")
+	require.ErrorIs(t, err, _errors.ProofNotSupportedErr)
+
+	report, err := r.HealthCheck(ctx)
+	require.NoError(t, err)
+	for _, backend := range report.Backends {
+		require.Equal(t, health.StatusOK, backend.Status, backend.Name)
+	}
+}
@@ -0,0 +1,31 @@
+package core
+
+import (
+	"context"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+// seal encrypts the payload of a rollup request with the key named in ctx, or the default key.
+// It returns the id of the key used, payloads are stored in clear when there is none.
+func (r *RollupModule) seal(ctx context.Context, data []byte) ([]byte, string, error) {
+	conf := r.config()
+	keyID := _common.KeyIDFromContext(ctx)
+	if len(keyID) == 0 {
+		keyID = conf.DefaultKeyID
+	}
+	if len(keyID) == 0 {
+		return data, "", nil
+	}
+	sealed, err := conf.Keyring.Seal(keyID, data)
+	if err != nil {
+		return nil, "", err
+	}
+	return sealed, keyID, nil
+}
+
+// open decrypts a retrieved payload for the caller presenting the token in ctx, payloads stored
+// in clear are returned as is.
+func (r *RollupModule) open(ctx context.Context, data []byte) ([]byte, error) {
+	return r.config().Keyring.Open(data, _common.AuthTokenFromContext(ctx))
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+func TestEncryptionPipeline(t *testing.T) {
+	ctx := context.Background()
+	keyring, err := encryption.NewKeyring(encryption.KeyringFile{Keys: []encryption.KeyConfig{
+		{ID: "appchain_a", Key: strings.Repeat("0a", encryption.KeySize), Readers: []string{"token-a"}},
+		{ID: "appchain_b", Algorithm: "xchacha20-poly1305", Key: strings.Repeat("0b", encryption.KeySize)},
+	}})
+	require.NoError(t, err)
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Codecs:       map[int]string{_common.CelestiaType: "zstd"},
+		Keyring:      keyring,
+		DefaultKeyID: "appchain_a",
+	})
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("private appchain batch "), 256)
+
+	// compressed, then sealed with the default key
+	encoded, _, err := r.encode(ctx, _common.CelestiaType, data)
+	require.NoError(t, err)
+	sealed, keyID, err := r.seal(ctx, encoded)
+	require.NoError(t, err)
+	require.Equal(t, "appchain_a", keyID)
+	require.Less(t, len(sealed), len(data))
+
+	_, err = r.open(ctx, sealed)
+	require.ErrorIs(t, err, encryption.ErrUnauthorized)
+	opened, err := r.open(_common.WithAuthToken(ctx, "token-a"), sealed)
+	require.NoError(t, err)
+	decoded, err := r.decode(_common.CelestiaType, opened)
+	require.NoError(t, err)
+	require.Equal(t, data, decoded)
+
+	// the request's key overrides the default one, a key without readers opens for every caller
+	sealed, keyID, err = r.seal(_common.WithKeyID(ctx, "appchain_b"), data)
+	require.NoError(t, err)
+	require.Equal(t, "appchain_b", keyID)
+	opened, err = r.open(ctx, sealed)
+	require.NoError(t, err)
+	require.Equal(t, data, opened)
+
+	_, err = r.RollupWithTypeContext(_common.WithKeyID(ctx, "appchain_c"), data, _common.CelestiaType)
+	require.ErrorIs(t, err, encryption.ErrUnknownKey)
+	require.Equal(t, "unknown_key", errorCode(err))
+
+	// without a keyring payloads are stored in clear
+	r, err = NewRollupModuleWithConfig(ctx, &_config.RollupConfig{})
+	require.NoError(t, err)
+	stored, keyID, err := r.seal(ctx, data)
+	require.NoError(t, err)
+	require.Empty(t, keyID)
+	require.Equal(t, data, stored)
+	opened, err = r.open(ctx, stored)
+	require.NoError(t, err)
+	require.Equal(t, data, opened)
+
+	// only a sealed envelope asks for a key
+	_, err = r.open(ctx, append([]byte{encryption.Magic, byte(encryption.AES256GCM), 0x01}, "zz"...))
+	require.ErrorIs(t, err, encryption.ErrUnknownKey)
+}
@@ -0,0 +1,118 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+)
+
+// rollupErasure erasure codes data and stores every shard on its DA at once. It fails unless every
+// shard was stored, and returns the receipt listing them alone, in the place retrieveArgs reads it.
+func (r *RollupModule) rollupErasure(ctx context.Context, data []byte) ([]interface{}, error) {
+	conf := r.config().Erasure
+	if !conf.Enabled() {
+		log.Error(_errors.DANotPreparedErrMsg, "da-type", "erasure")
+		return nil, _errors.DANotPreparedErr
+	}
+	daTypes, err := conf.ShardDATypes()
+	if err != nil {
+		return nil, err
+	}
+	shards, err := erasure.Encode(data, conf.DataShards, conf.ParityShards)
+	if err != nil {
+		return nil, err
+	}
+	receipt := erasure.NewReceipt(data, shards, conf.DataShards, conf.ParityShards)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(shards))
+	)
+	for i := range shards {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			daType := daTypes[i]
+			res, err := r.rollupWithType(ctx, shards[i], daType)
+			r.checkBackendOnError(daType, err)
+			if err == nil {
+				receipt.Shards[i].DAArgs, err = retrieveArgs(daType, res)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("store shard %d on %s: %w", i, _common.DATypeName(daType), err)
+				return
+			}
+			receipt.Shards[i].DAType = daType
+		}(i)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	log.Debug("stored erasure coded data", "data-shards", conf.DataShards, "parity-shards", conf.ParityShards, "size", len(data))
+	return []interface{}{receipt.String()}, nil
+}
+
+// retrieveErasure fetches the shards an erasure receipt lists at once and rebuilds the payload as
+// soon as enough of them arrived and matched their hash.
+func (r *RollupModule) retrieveErasure(ctx context.Context, args interface{}) ([]byte, error) {
+	if !erasure.IsReceipt(args) {
+		log.Error("args is not an erasure receipt")
+		return nil, _errors.WrongArgTypeErr
+	}
+	receipt, err := erasure.ParseReceipt(args.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		i     int
+		shard []byte
+		err   error
+	}
+	results := make(chan result, len(receipt.Shards))
+	for i, shard := range receipt.Shards {
+		go func(i int, shard erasure.Shard) {
+			data, err := r.retrieveFromDAWithType(ctx, shard.DAType, shard.DAArgs)
+			if err == nil {
+				err = receipt.CheckShard(i, data)
+			} else if ctx.Err() == nil {
+				r.checkBackendOnError(shard.DAType, err)
+			}
+			if err != nil {
+				err = fmt.Errorf("retrieve shard %d from %s: %w", i, _common.DATypeName(shard.DAType), err)
+			}
+			results <- result{i: i, shard: data, err: err}
+		}(i, shard)
+	}
+
+	shards := make([][]byte, len(receipt.Shards))
+	var errs []error
+	for received := 0; received < receipt.DataShards; {
+		if len(errs) > receipt.ParityShards {
+			// the shards of a canceled retrieval fail with it, the caller gets the cancellation
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", erasure.ErrNotEnoughShards, errors.Join(errs...))
+		}
+		res := <-results
+		if res.err != nil {
+			log.Warn("shard unavailable", "err", res.err)
+			errs = append(errs, res.err)
+			continue
+		}
+		shards[res.i] = res.shard
+		received++
+	}
+	return receipt.Reconstruct(shards)
+}
@@ -0,0 +1,48 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+func TestErasureRollup(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("rollup batch")
+
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Erasure: erasure.DefaultConfig()})
+	require.NoError(t, err)
+	_, err = r.RollupWithTypeContext(ctx, data, _common.ErasureType)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+
+	// the shards go to backends which aren't enabled
+	conf := erasure.DefaultConfig()
+	conf.DAs = []string{"celestia", "eigenda", "eip4844"}
+	r, err = NewRollupModuleWithConfig(ctx, &_config.RollupConfig{Erasure: conf})
+	require.NoError(t, err)
+	_, err = r.RollupWithTypeContext(ctx, data, _common.ErasureType)
+	require.ErrorIs(t, err, _errors.DANotPreparedErr)
+
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.ErasureType, "0x0a0b")
+	require.ErrorIs(t, err, _errors.WrongArgTypeErr)
+
+	shards, err := erasure.Encode(data, 2, 1)
+	require.NoError(t, err)
+	receipt := erasure.NewReceipt(data, shards, 2, 1)
+	for i := range receipt.Shards {
+		receipt.Shards[i].DAType = _common.EigenDAType
+		receipt.Shards[i].DAArgs = "AAEC"
+	}
+	_, err = r.RetrieveFromDAWithTypeContext(ctx, _common.ErasureType, receipt.String())
+	require.ErrorIs(t, err, erasure.ErrNotEnoughShards)
+	require.Equal(t, "not_enough_shards", errorCode(err))
+
+	_, err = r.StatusWithTypeContext(ctx, _common.ErasureType, receipt.String())
+	require.ErrorIs(t, err, _errors.StatusNotTrackedErr)
+}
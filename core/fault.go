@@ -0,0 +1,51 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+)
+
+// Faults returns the faults injected in the requests to the DAs by da name.
+func (r *RollupModule) Faults() map[string]fault.Config {
+	faults := r.faults.All()
+	res := make(map[string]fault.Config, len(faults))
+	for daType, conf := range faults {
+		res[_common.DATypeName(daType)] = conf
+	}
+	return res
+}
+
+// SetFault replaces the faults injected in the requests to daType until the next reload.
+func (r *RollupModule) SetFault(daType int, conf fault.Config) error {
+	if err := faultDAType(daType); err != nil {
+		return err
+	}
+	if err := conf.Check(); err != nil {
+		return err
+	}
+	r.faults.Set(daType, conf)
+	r.Log.Warn("fault injection set", "da-type", _common.DATypeName(daType), "faults", conf)
+	return nil
+}
+
+// ClearFault stops injecting faults in the requests to daType.
+func (r *RollupModule) ClearFault(daType int) error {
+	if err := faultDAType(daType); err != nil {
+		return err
+	}
+	r.faults.Clear(daType)
+	r.Log.Info("fault injection cleared", "da-type", _common.DATypeName(daType))
+	return nil
+}
+
+// faultDAType rejects the da types faults can't be injected in, erasure goes through other DAs.
+func faultDAType(daType int) error {
+	if slices.Contains(_common.DATypes, daType) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d", _errors.UnknownDATypeErr, daType)
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
+)
+
+func TestFaultInjection(t *testing.T) {
+	ctx := context.Background()
+	devnet := mockda.DefaultConfig()
+	r, err := NewRollupModuleWithConfig(ctx, &_config.RollupConfig{
+		Devnet: &devnet,
+		Faults: map[int]fault.Config{_common.EigenDAType: {ErrorRate: 1, Ops: []string{fault.OpRollup}}},
+	})
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("rollup batch "), 100)
+	_, err = r.RollupWithTypeContext(ctx, data, _common.EigenDAType)
+	require.ErrorIs(t, err, fault.ErrInjected)
+	require.Equal(t, "injected", errorCode(err))
+
+	// retrieved data is altered on its way out, the cache keeps what the DA returned
+	res, err := r.RollupWithTypeContext(ctx, data, _common.CelestiaType)
+	require.NoError(t, err)
+	args, err := retrieveArgs(_common.CelestiaType, res)
+	require.NoError(t, err)
+	for _, conf := range []fault.Config{{TruncateRate: 1}, {CorruptRate: 1}} {
+		require.NoError(t, r.SetFault(_common.CelestiaType, conf))
+		got, err := r.RetrieveFromDAWithTypeContext(ctx, _common.CelestiaType, args)
+		if err == nil {
+			require.NotEqual(t, data, got, "%+v", conf)
+		}
+	}
+	require.NoError(t, r.ClearFault(_common.CelestiaType))
+	got, err := r.RetrieveFromDAWithTypeContext(ctx, _common.CelestiaType, args)
+	require.NoError(t, err)
+	require.Equal(t, data, got)
+
+	// stuck submissions
+	require.NoError(t, r.SetFault(_common.EigenDAType, fault.Config{Pending: true}))
+	res, err = r.RollupWithTypeContext(ctx, data, _common.EigenDAType)
+	require.NoError(t, err)
+	status, err := r.StatusWithTypeContext(ctx, _common.EigenDAType, res[0])
+	require.NoError(t, err)
+	require.Equal(t, "pending", status.Status)
+	require.False(t, status.Final)
+
+	require.Equal(t, map[string]fault.Config{"eigenda": {Pending: true}}, r.Faults())
+	require.Error(t, r.SetFault(_common.ErasureType, fault.Config{}))
+	require.Error(t, r.SetFault(_common.NearDAType, fault.Config{ErrorRate: 2}))
+	require.Error(t, r.ClearFault(42))
+}
+
+func TestReloadResetsFaults(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rollup.toml")
+	require.NoError(t, os.WriteFile(path, []byte("[faults.nearda]\nerror_rate = 0.5\n"), 0o600))
+	conf, err := _config.LoadRollupConfig(path, true)
+	require.NoError(t, err)
+	r, err := NewRollupModuleWithConfig(ctx, conf)
+	require.NoError(t, err)
+	r.ConfigFile = path
+	r.Devnet = true
+
+	require.NoError(t, r.SetFault(_common.CelestiaType, fault.Config{Pending: true}))
+	require.Len(t, r.Faults(), 2)
+	_, err = r.Reload(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]fault.Config{"nearda": {ErrorRate: 0.5}}, r.Faults())
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+)
+
+const healthCheckTimeout = 5 * time.Second
+
+// checkers returns the health checker of every built DA client keyed by da type.
+func (r *RollupModule) checkers() map[int]health.Checker {
+	checkers := make(map[int]health.Checker)
+	if client, ok := r.anytrustDA.Load().Get(); ok {
+		checkers[_common.AnytrustType] = client
+	}
+	if client, ok := r.celestiaDA.Load().Get(); ok {
+		checkers[_common.CelestiaType] = client
+	}
+	if client, ok := r.eigenDA.Load().Get(); ok {
+		checkers[_common.EigenDAType] = client
+	}
+	if client, ok := r.eip4844.Load().Get(); ok {
+		checkers[_common.Eip4844Type] = client
+	}
+	if client, ok := r.nearDA.Load().Get(); ok {
+		checkers[_common.NearDAType] = client
+	}
+	if client, ok := r.anytrustCommittee.Load().Get(); ok {
+		checkers[_common.AnytrustCommitteeType] = client
+	}
+	if devnet, ok := r.devnet.Load().Get(); ok {
+		for _, daType := range _common.DATypes {
+			if da, ok := devnet.DA(daType); ok {
+				checkers[daType] = da
+			}
+		}
+	}
+	return checkers
+}
+
+// HealthCheck probes all prepared DA backends concurrently and aggregates the results, a failed
+// probe is reported on its backend rather than returned.
+func (r *RollupModule) HealthCheck(ctx context.Context) (*health.Report, error) {
+	checkers := r.checkers()
+	report := &health.Report{
+		Backends: make([]health.BackendStatus, len(_common.DATypes)),
+	}
+
+	var wg sync.WaitGroup
+	for i, daType := range _common.DATypes {
+		state, stateErr := r.backendState(daType)
+		report.Backends[i] = health.BackendStatus{
+			Name:   _common.DATypeName(daType),
+			DAType: daType,
+			State:  string(state),
+			Status: health.StatusNotPrepared,
+		}
+		checker, ok := checkers[daType]
+		if !ok {
+			if stateErr != nil {
+				report.Backends[i].Error = stateErr.Error()
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(status *health.BackendStatus) {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checker.HealthCheck(probeCtx)
+			status.LatencyMs = time.Since(start).Milliseconds()
+			if err != nil {
+				log.Warn("DA backend health check failed", "da-type", status.Name, "err", err)
+				status.Status = health.StatusUnhealthy
+				status.Error = err.Error()
+				return
+			}
+			status.Status = health.StatusOK
+		}(&report.Backends[i])
+	}
+	wg.Wait()
+
+	report.Ready = len(checkers) > 0
+	for _, backend := range report.Backends {
+		if backend.Status == health.StatusUnhealthy {
+			report.Ready = false
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/eniac-x-labs/rollup-node/common/cliapp"
+)
+
+// DefaultShutdownTimeout bounds Stop when the caller's context carries no deadline.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// service is a named sub-service owned by the RollupModule.
+type service struct {
+	name string
+	cliapp.Lifecycle
+}
+
+// backendServices lists the supervisors of the enabled DA clients in start order.
+func (r *RollupModule) backendServices() []service {
+	var out []service
+	for _, b := range r.backends() {
+		if s := b.current(); s != nil {
+			out = append(out, service{b.name, s})
+		}
+	}
+	return out
+}
+
+// AddServer registers a server exposing the module, servers are started after the DA
+// clients and stopped before the in-flight requests are drained.
+func (r *RollupModule) AddServer(name string, srv cliapp.Lifecycle) {
+	r.servers = append(r.servers, service{name, srv})
+}
+
+func startServices(ctx context.Context, services []service) ([]service, error) {
+	for i, s := range services {
+		if err := s.Start(ctx); err != nil {
+			return services[:i], fmt.Errorf("failed to start %s: %w", s.name, err)
+		}
+	}
+	return services, nil
+}
+
+// wait waits for wg, it reports false when ctx was done first.
+func wait(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// stopServices stops the services in reverse order.
+func (r *RollupModule) stopServices(ctx context.Context, services []service) error {
+	var result error
+	for i := len(services) - 1; i >= 0; i-- {
+		s := services[i]
+		if s.Stopped() {
+			continue
+		}
+		if err := s.Stop(ctx); err != nil {
+			r.Log.Error("failed to stop service", "service", s.name, "err", err)
+			result = errors.Join(result, fmt.Errorf("failed to stop %s: %w", s.name, err))
+			continue
+		}
+		r.Log.Debug("service stopped", "service", s.name)
+	}
+	return result
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	"github.com/eniac-x-labs/rollup-node/common/encryption"
+	"github.com/eniac-x-labs/rollup-node/common/erasure"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	"github.com/eniac-x-labs/rollup-node/metrics"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+	"github.com/eniac-x-labs/rollup-node/x/eigenda"
+)
+
+// errorCode classifies a rollup or retrieve error for the da errors metric.
+func errorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, fault.ErrInjected):
+		return "injected"
+	case errors.Is(err, _errors.DANotPreparedErr):
+		return "not_prepared"
+	case errors.Is(err, _errors.UnknownDATypeErr):
+		return "unknown_da_type"
+	case errors.Is(err, _errors.WrongArgTypeErr):
+		return "wrong_arg_type"
+	case errors.Is(err, _errors.ShuttingDownErr):
+		return "shutting_down"
+	case errors.Is(err, codec.ErrUnknownCodec):
+		return "unknown_codec"
+	case errors.Is(err, codec.ErrTooLarge):
+		return "too_large"
+	case errors.Is(err, encryption.ErrUnknownKey):
+		return "unknown_key"
+	case errors.Is(err, encryption.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, encryption.ErrDecrypt):
+		return "decrypt_failed"
+	case errors.Is(err, aggregate.ErrInvalidBlob), errors.Is(err, aggregate.ErrInvalidProof):
+		return "invalid_aggregate"
+	case errors.Is(err, chunk.ErrInvalidManifest), errors.Is(err, chunk.ErrChunkMismatch):
+		return "invalid_chunks"
+	case errors.Is(err, erasure.ErrNotEnoughShards):
+		return "not_enough_shards"
+	case errors.Is(err, erasure.ErrShardMismatch):
+		return "invalid_shards"
+	case errors.Is(err, _errors.ProofNotSupportedErr), errors.Is(err, _errors.AttestationNotSupportedErr),
+		errors.Is(err, _errors.CostNotSupportedErr), errors.Is(err, pricing.ErrNoRate),
+		errors.Is(err, celestia.ErrBlobstreamDisabled):
+		return "not_supported"
+	case errors.Is(err, celestia.ErrNotAttested):
+		return "not_attested"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "da_error"
+	}
+}
+
+func (r *RollupModule) recordDARequest(op string, daType int, size int, start time.Time, err error) {
+	r.metrics.RecordDARequest(op, _common.DATypeName(daType), size, time.Since(start), errorCode(err))
+}
+
+// setMetrics hands the metricer to the DA clients which record backend specific metrics,
+// clients rebuilt by their supervisor pick it up on construction.
+func (r *RollupModule) setMetrics(m metrics.RollupMetricer) {
+	r.metrics = m
+	if client, ok := r.eigenDA.Load().Get(); ok {
+		if eigenDA, ok := client.(*eigenda.EigenDAClient); ok {
+			eigenDA.Metrics = m
+		}
+	}
+	if eip4844, ok := r.eip4844.Load().Get(); ok {
+		eip4844.Metrics = m
+	}
+}
@@ -0,0 +1,98 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+)
+
+func (r *RollupModule) ProofWithType(daType int, args interface{}) (*_common.InclusionProof, error) {
+	return r.ProofWithTypeContext(r.ctx, daType, args)
+}
+
+// ProofWithTypeContext returns the inclusion proof of the data of a receipt returned by RollupWithType,
+// checked against the DA node. Only celestia serves proofs.
+func (r *RollupModule) ProofWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error) {
+	ctx, span := tracer.Start(ctx, "core.ProofWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+	))
+	res, err := r.proofWithType(ctx, daType, args)
+	r.checkBackendOnError(daType, err)
+	if res != nil {
+		span.SetAttributes(attribute.Bool("included", res.Included))
+	}
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+func (r *RollupModule) proofWithType(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error) {
+	if !r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+
+	args, err := blobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if r.mocked(daType) {
+		return nil, _errors.ProofNotSupportedErr
+	}
+	switch daType {
+	case _common.CelestiaType:
+		celestiaDA, release, ok := acquire(&r.celestiaDA)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "celestiaDA")
+			return nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		receiptStr, ok := args.(string)
+		if !ok {
+			log.Error("args is not string type")
+			return nil, _errors.WrongArgTypeErr
+		}
+		receipt, err := celestia.ParseReceipt(receiptStr)
+		if err != nil {
+			log.Error("parse celestia receipt failed", "err", err, "receipt", receiptStr)
+			return nil, err
+		}
+
+		proof, err := celestiaDA.GetProof(ctx, receipt)
+		if err != nil {
+			log.Error("get celestia inclusion proof failed", "err", err, "receipt", receiptStr)
+			return nil, err
+		}
+		proofJSON, err := json.Marshal(proof.Proof)
+		if err != nil {
+			return nil, fmt.Errorf("encode celestia proof: %w", err)
+		}
+		return &_common.InclusionProof{
+			DAType:   _common.CelestiaType,
+			Included: proof.Included,
+			Detail: map[string]string{
+				"height":     strconv.FormatUint(receipt.Height, 10),
+				"namespace":  receipt.Namespace.String(),
+				"commitment": fmt.Sprintf("%x", []byte(receipt.Commitment)),
+				"index":      strconv.Itoa(proof.Index),
+			},
+			Proof: proofJSON,
+		}, nil
+
+	case _common.AnytrustType, _common.EigenDAType, _common.Eip4844Type, _common.NearDAType, _common.AnytrustCommitteeType,
+		_common.ErasureType:
+		return nil, _errors.ProofNotSupportedErr
+	default:
+		log.Error("ProofWithType got unknown da type", "daType", daType, "expected", "[0,5]")
+	}
+	return nil, _errors.UnknownDATypeErr
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/eniac-x-labs/rollup-node/common/cliapp"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+// DefaultReloadTimeout bounds a reload triggered by SIGHUP.
+const DefaultReloadTimeout = time.Minute
+
+// RetireTimeout bounds the wait of a replaced backend for its in-flight requests, its client is
+// closed afterwards even if some did not finish.
+const RetireTimeout = time.Minute
+
+var ErrReloadDisabled = errors.New("reload needs the module to be created from a config file")
+
+// Reload re-reads ConfigFile and rebuilds the DA clients whose section changed, the other ones
+// keep serving. New requests go to the replacement of a client right away, the replaced one is
+// closed in the background once its in-flight requests finished. An invalid config leaves every backend untouched.
+// It returns the names of the rebuilt backends.
+func (r *RollupModule) Reload(ctx context.Context) ([]string, error) {
+	if len(r.ConfigFile) == 0 {
+		return nil, ErrReloadDisabled
+	}
+	next, err := _config.LoadRollupConfig(r.ConfigFile, r.Devnet)
+	if err != nil {
+		r.Log.Error("reload config failed", "file", r.ConfigFile, "err", err)
+		return nil, err
+	}
+
+	r.backendsMu.Lock()
+	defer r.backendsMu.Unlock()
+	if r.stopped.Load() {
+		return nil, ErrAlreadyStopped
+	}
+
+	prev := r.config()
+	r.configMu.Lock()
+	r.RollupConfig = next
+	r.configMu.Unlock()
+	// the faults of the file replace the ones set at runtime
+	r.faults.Reset(next.Faults)
+	if !reflect.DeepEqual(prev.Pricing, next.Pricing) {
+		// the fetched prices are dropped with the previous source
+		r.prices.Store(pricing.NewPrices(next.Pricing))
+	}
+
+	var (
+		reloaded []string
+		result   error
+	)
+	for _, b := range r.backends() {
+		if !b.changed(prev, next) {
+			continue
+		}
+		r.Log.Info("reloading DA backend", "backend", b.name)
+		if err := b.replace(ctx, next); err != nil {
+			r.Log.Error("failed to reload DA backend", "backend", b.name, "err", err)
+			result = errors.Join(result, err)
+		}
+		reloaded = append(reloaded, b.name)
+	}
+	r.Log.Info("config reloaded", "file", r.ConfigFile, "reloaded", reloaded)
+	return reloaded, result
+}
+
+// ReloadOnSIGHUP makes the module reload its config file on SIGHUP while it runs.
+func (r *RollupModule) ReloadOnSIGHUP() {
+	r.AddServer("sighup", &sighupReloader{module: r})
+}
+
+// sighupReloader calls Reload on every SIGHUP.
+type sighupReloader struct {
+	module  *RollupModule
+	signals chan os.Signal
+	done    chan struct{}
+	stopped atomic.Bool
+}
+
+func (s *sighupReloader) Start(ctx context.Context) error {
+	s.signals = make(chan os.Signal, 1)
+	signal.Notify(s.signals, syscall.SIGHUP)
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		for range s.signals {
+			s.module.Log.Info("received SIGHUP, reloading config")
+			ctx, cancel := context.WithTimeout(context.Background(), DefaultReloadTimeout)
+			_, _ = s.module.Reload(ctx)
+			cancel()
+		}
+	}()
+	return nil
+}
+
+// Stop stops listening for SIGHUP and waits for a running reload.
+func (s *sighupReloader) Stop(ctx context.Context) error {
+	if s.stopped.Swap(true) || s.signals == nil {
+		return nil
+	}
+	signal.Stop(s.signals)
+	close(s.signals)
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("reload did not finish: %w", ctx.Err())
+	}
+}
+
+func (s *sighupReloader) Stopped() bool {
+	return s.stopped.Load()
+}
+
+var _ cliapp.Lifecycle = (*sighupReloader)(nil)
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_config "github.com/eniac-x-labs/rollup-node/config"
+)
+
+func writeEigenDAConfig(t *testing.T, path, rpc string) {
+	conf := fmt.Sprintf("[eigenda]\nenabled = true\nrpc = %q\n", rpc)
+	require.NoError(t, os.WriteFile(path, []byte(conf), 0o600))
+}
+
+func TestReloadRebuildsChangedBackends(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rollup.toml")
+	writeEigenDAConfig(t, path, "127.0.0.1:1")
+
+	conf, err := _config.LoadRollupConfig(path, false)
+	require.NoError(t, err)
+	r, err := NewRollupModuleWithConfig(ctx, conf)
+	require.NoError(t, err)
+	r.ConfigFile = path
+	require.NoError(t, r.Start(ctx))
+	defer r.Stop(ctx)
+
+	prev, release, ok := acquire(&r.eigenDA)
+	require.True(t, ok)
+
+	// a reload doesn't wait for a slow request on the old client, the request keeps its client
+	// past the deadline of the reload
+	writeEigenDAConfig(t, path, "127.0.0.1:2")
+	reloadCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	reloaded, err := r.Reload(reloadCtx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"eigenda"}, reloaded)
+	next, releaseNext, ok := acquire(&r.eigenDA)
+	require.True(t, ok)
+	releaseNext()
+	require.True(t, next != prev, "new requests should use the rebuilt client")
+
+	retired := make(chan struct{})
+	go func() {
+		r.retiring.Wait()
+		close(retired)
+	}()
+	<-reloadCtx.Done()
+	select {
+	case <-retired:
+		t.Fatal("old client stopped before the in-flight request finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-retired:
+	case <-time.After(5 * time.Second):
+		t.Fatal("old client not stopped once the request finished")
+	}
+
+	// nothing changed
+	reloaded, err = r.Reload(ctx)
+	require.NoError(t, err)
+	require.Empty(t, reloaded)
+
+	// an invalid config keeps the current backends
+	current := r.eigenDA.Load()
+	writeEigenDAConfig(t, path, "no-port")
+	_, err = r.Reload(ctx)
+	var validationErr _config.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Same(t, current, r.eigenDA.Load())
+}
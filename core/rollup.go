@@ -9,62 +9,197 @@ import (
 	"fmt"
 	"github.com/urfave/cli/v2"
 	"os"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
 	"github.com/eniac-x-labs/rollup-node/api"
+	"github.com/eniac-x-labs/rollup-node/api/common/httputil"
 
 	"github.com/eniac-x-labs/anytrustDA/das"
 	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/aggregate"
+	"github.com/eniac-x-labs/rollup-node/common/cache"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
 	"github.com/eniac-x-labs/rollup-node/common/cliapp"
+	"github.com/eniac-x-labs/rollup-node/common/codec"
+	"github.com/eniac-x-labs/rollup-node/common/content"
 	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/common/inflight"
+	"github.com/eniac-x-labs/rollup-node/common/pricing"
+	"github.com/eniac-x-labs/rollup-node/common/supervisor"
 	_config "github.com/eniac-x-labs/rollup-node/config"
+	"github.com/eniac-x-labs/rollup-node/metrics"
 	_rpc "github.com/eniac-x-labs/rollup-node/rpc"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 	"github.com/eniac-x-labs/rollup-node/x/anytrust"
 	"github.com/eniac-x-labs/rollup-node/x/celestia"
 	"github.com/eniac-x-labs/rollup-node/x/eigenda"
 	"github.com/eniac-x-labs/rollup-node/x/eip4844"
+	"github.com/eniac-x-labs/rollup-node/x/mockda"
 	"github.com/eniac-x-labs/rollup-node/x/nearda"
 
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var ErrAlreadyStopped = errors.New("already stopped")
 
+var tracer = tracing.Tracer("core")
+
 type RollupModule struct {
 	ctx context.Context
 
+	// RollupConfig is replaced by Reload, read it through config
 	RollupConfig *_config.RollupConfig
-
-	anytrustDA        anytrust.IAnytrustDA
-	anytrustCommittee anytrust.IAnytrustDA
-	celestiaDA        *celestia.CelestiaRollup
-	eigenDA           eigenda.IEigenDA
-	eip4844           *eip4844.Eip4844Rollup
-	nearDA            nearda.INearDA
-	stopped           atomic.Bool
-	Log               log.Logger
+	// ConfigFile is the config file Reload reads, reloading is disabled when empty
+	ConfigFile string
+	// Devnet serves the DAs with mock backends, it applies to the config reloaded from ConfigFile too
+	Devnet   bool
+	configMu sync.RWMutex
+
+	// the supervisors are swapped by Reload, a nil one stands for a disabled backend
+	anytrustDA        atomic.Pointer[supervisor.Supervisor[anytrust.IAnytrustDA]]
+	anytrustCommittee atomic.Pointer[supervisor.Supervisor[anytrust.IAnytrustDA]]
+	celestiaDA        atomic.Pointer[supervisor.Supervisor[*celestia.CelestiaRollup]]
+	eigenDA           atomic.Pointer[supervisor.Supervisor[eigenda.IEigenDA]]
+	eip4844           atomic.Pointer[supervisor.Supervisor[*eip4844.Eip4844Rollup]]
+	nearDA            atomic.Pointer[supervisor.Supervisor[nearda.INearDA]]
+	// devnet serves the DAs listed by the devnet config in place of their backends
+	devnet          atomic.Pointer[supervisor.Supervisor[*mockda.Devnet]]
+	metrics         metrics.RollupMetricer
+	metricsSrv      *httputil.HTTPServer
+	tracingShutdown func(context.Context) error
+
+	// aggregators pack the small submissions of each DA, one is replaced when its config changes
+	aggregators   map[int]*aggregator
+	aggregatorsMu sync.Mutex
+	// flushing counts the packed blobs being stored, their callers may have given up
+	flushing sync.WaitGroup
+	// contentIndex resolves payload hashes to the receipts returned for them
+	contentIndex *content.Index
+	// cache holds the data retrieved from the DAs, nil when disabled
+	cache *cache.Cache
+	// faults are injected in the requests to the DAs, they are set by the config and /admin/faults
+	faults *fault.Injector
+	// prices convert the cost estimates to fiat, nil without a currency, replaced by Reload
+	prices atomic.Pointer[pricing.Prices]
+
+	// backends and servers are started in order and stopped in reverse order,
+	// backendsMu serializes starting and stopping the backends with Reload
+	backendsMu sync.Mutex
+	running    bool
+	// retiring counts the replaced backends still waiting for their requests
+	retiring        sync.WaitGroup
+	servers         []service
+	inflight        inflight.Tracker
+	ShutdownTimeout time.Duration
+
+	stopped atomic.Bool
+	Log     log.Logger
 }
 
+// Start starts the DA clients, then the servers exposing them.
 func (r *RollupModule) Start(ctx context.Context) error {
+	r.backendsMu.Lock()
+	backends := r.backendServices()
+	started, err := startServices(ctx, backends)
+	if err != nil {
+		r.backendsMu.Unlock()
+		return errors.Join(err, r.stopServices(ctx, started))
+	}
+	r.running = true
+	r.backendsMu.Unlock()
+
+	started, err = startServices(ctx, r.servers)
+	if err != nil {
+		return errors.Join(err, r.stopServices(ctx, started), r.stopBackends(ctx))
+	}
+	r.Log.Info("rollup node service started", "backends", len(backends), "servers", len(r.servers))
 	return nil
 }
 
+// Stop stops the servers so no new request comes in, waits for the in-flight rollup and
+// retrieve requests, then closes the DA clients, the metrics server and the trace exporter.
+// The whole shutdown is bounded by ShutdownTimeout.
 func (r *RollupModule) Stop(ctx context.Context) error {
-	if r.stopped.Load() {
+	if r.stopped.Swap(true) {
 		return ErrAlreadyStopped
 	}
 	r.Log.Info("Stopping rollup node service")
 
-	r.stopped.Store(true)
+	timeout := r.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = DefaultShutdownTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := r.stopServices(ctx, r.servers)
+
+	// the buffered submissions are stored before the DA clients close
+	r.flushAggregators()
+	if err := r.inflight.Drain(ctx); err != nil {
+		r.Log.Error("failed to drain in-flight requests", "err", err)
+		result = errors.Join(result, err)
+	}
+	if !wait(ctx, &r.flushing) {
+		r.Log.Error("failed to store the aggregated submissions", "err", ctx.Err())
+		result = errors.Join(result, fmt.Errorf("aggregated submissions not stored: %w", ctx.Err()))
+	}
+
+	result = errors.Join(result, r.stopBackends(ctx))
+
+	if r.metricsSrv != nil {
+		if err := r.metricsSrv.Stop(ctx); err != nil {
+			r.Log.Error("failed to stop metrics server", "err", err)
+			result = errors.Join(result, err)
+		}
+	}
+
+	if err := r.contentIndex.Close(); err != nil {
+		r.Log.Error("failed to close content index", "err", err)
+		result = errors.Join(result, err)
+	}
+
+	if r.tracingShutdown != nil {
+		if err := r.tracingShutdown(ctx); err != nil {
+			r.Log.Error("failed to flush traces", "err", err)
+			result = errors.Join(result, err)
+		}
+	}
+
 	r.Log.Info("rollup node service stopped")
-	return nil
+	return result
 }
 
 func (r *RollupModule) Stopped() bool {
 	return r.stopped.Load()
 }
 
+// stopBackends stops the current DA client supervisors and waits for the replaced ones, a Reload
+// can't start new ones afterwards.
+func (r *RollupModule) stopBackends(ctx context.Context) error {
+	r.backendsMu.Lock()
+	defer r.backendsMu.Unlock()
+	r.running = false
+	result := r.stopServices(ctx, r.backendServices())
+	if !wait(ctx, &r.retiring) {
+		result = errors.Join(result, fmt.Errorf("replaced DA backends not stopped: %w", ctx.Err()))
+	}
+	return result
+}
+
+// config returns the current config of the DA clients.
+func (r *RollupModule) config() *_config.RollupConfig {
+	r.configMu.RLock()
+	defer r.configMu.RUnlock()
+	return r.RollupConfig
+}
+
 func RunRollupModuleForCLI(cliCtx *cli.Context, shutdown context.CancelCauseFunc) (cliapp.Lifecycle, error) {
 	logger := log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stdout, log.LevelDebug, true))
 	log.SetDefault(logger)
@@ -76,69 +211,92 @@ func RunRollupModuleForCLI(cliCtx *cli.Context, shutdown context.CancelCauseFunc
 	}
 	log.Info("finished new rollup module")
 
+	metricsCfg := metrics.ReadCLIConfig(cliCtx)
+	if err := metricsCfg.Check(); err != nil {
+		log.Error("invalid metrics config", "err", err)
+		return nil, err
+	}
+	if metricsCfg.Enabled {
+		registry := metrics.NewRegistry()
+		rollupModule.setMetrics(metrics.NewRollupMetrics(registry))
+		rollupModule.metricsSrv, err = metrics.StartServer(registry, metricsCfg.ListenAddr, metricsCfg.ListenPort)
+		if err != nil {
+			log.Error("failed to start metrics server", "err", err)
+			return nil, err
+		}
+		log.Info("started metrics server", "addr", rollupModule.metricsSrv.Addr().String())
+	}
+
+	tracingCfg := tracing.ReadCLIConfig(cliCtx)
+	if err := tracingCfg.Check(); err != nil {
+		log.Error("invalid tracing config", "err", err)
+		return nil, err
+	}
+	rollupModule.tracingShutdown, err = tracing.Setup(cliCtx.Context, tracingCfg)
+	if err != nil {
+		log.Error("failed to set up tracing", "err", err)
+		return nil, err
+	}
+	if tracingCfg.Enabled {
+		log.Info("tracing enabled", "exporter", tracingCfg.Exporter, "sampleRatio", tracingCfg.SampleRatio)
+	}
+
 	rpcAddress := cliCtx.String("rpcAddress")
 	apiAddress := cliCtx.String("apiAddress")
 	log.Debug("exposed address config", "rpcAddress", rpcAddress, "apiAddress", apiAddress)
 
+	rollupModule.ShutdownTimeout = cliCtx.Duration("shutdownTimeout")
+
 	if len(rpcAddress) != 0 {
-		go _rpc.NewAndStartRollupRpcServer(cliCtx.Context, rpcAddress, rollupModule)
+		rpcServer, err := _rpc.NewRollupRpcServer(rpcAddress, rollupModule)
+		if err != nil {
+			log.Error("NewRollupRpcServer failed", "err", err)
+			return nil, err
+		}
+		rollupModule.AddServer("rpc", rpcServer)
 	}
 
-	err = api.NewApi(cliCtx.Context, logger, apiAddress, rollupModule)
+	apiServer, err := api.NewApi(cliCtx.Context, logger, apiAddress, rollupModule)
 	if err != nil {
 		log.Error("NewApi failed", "err", err)
 		return nil, err
 	}
+	if adminToken := cliCtx.String("adminToken"); len(adminToken) != 0 {
+		apiServer.EnableAdmin(adminToken)
+	}
+	rollupModule.AddServer("api", apiServer)
+	rollupModule.ReloadOnSIGHUP()
 
 	return rollupModule, nil
 }
 
-func NewRollupModuleWithConfig(ctx context.Context, conf *_config.RollupConfig) (_rpc.RollupInter, error) {
+func NewRollupModuleWithConfig(ctx context.Context, conf *_config.RollupConfig) (*RollupModule, error) {
 	if ctx == nil || conf == nil {
 		return nil, _errors.NilPointerErr
 	}
 
-	anytrustDA, err := anytrust.NewAnytrustDA(conf.AnytrustDAConfig)
-	if err != nil {
-		log.Error("NewAnytrustDA failed", "err", err)
-	}
-
-	anytrustDACommittee, err := anytrust.NewAnytrustDAWithCommittee(ctx, conf.AnytrustCommitteeConfig, nil)
+	contentIndex, err := content.OpenIndex(conf.ContentIndexFile, conf.ContentMaxEntries)
 	if err != nil {
-		log.Error("NewAnytrustDACommittee failed", "err", err)
-	}
-
-	celestiaDA, err := celestia.NewCelestiaRollupWithConfig(ctx, conf.CelestiaDAConfig)
-	if err != nil {
-		log.Error("NewCelestiaRollupWithConfig failed", "err", err)
-	}
-
-	eigenDA, err := eigenda.NewEigenDAClient(conf.EigenDAConfig)
-	if err != nil {
-		log.Error("NewEigenDA failed", "err", err)
-	}
-
-	eip4844, err := eip4844.NewEip4844WithConfig(ctx, conf.Eip4844CLICfg, conf.Eip4844Config)
-	if err != nil {
-		log.Error("NewEip4844WithConfig failed", "err", err)
+		return nil, err
 	}
-
-	nearDA, err := nearda.NewNearDAClient(conf.NearDAConfig)
+	retrieveCache, err := cache.New(conf.Cache)
 	if err != nil {
-		log.Error("NewNearDA failed", "err", err)
+		return nil, errors.Join(err, contentIndex.Close())
 	}
-
-	return &RollupModule{
+	r := &RollupModule{
 		ctx:          ctx,
 		RollupConfig: conf,
-
-		anytrustDA:        anytrustDA,
-		anytrustCommittee: anytrustDACommittee,
-		celestiaDA:        celestiaDA,
-		eigenDA:           eigenDA,
-		eip4844:           eip4844,
-		nearDA:            nearDA,
-	}, nil
+		contentIndex: contentIndex,
+		cache:        retrieveCache,
+		metrics:      metrics.NoopRollupMetrics,
+		Log:          log.Root(),
+	}
+	r.faults = fault.New(conf.Faults, func(daType int, kind string) {
+		r.metrics.RecordFault(_common.DATypeName(daType), kind)
+	})
+	r.prices.Store(pricing.NewPrices(conf.Pricing))
+	r.superviseBackends(ctx)
+	return r, nil
 }
 
 // for cli
@@ -147,76 +305,111 @@ func NewRollupModuleForCLI(cliCtx *cli.Context, logger log.Logger) (*RollupModul
 		return nil, _errors.NilPointerErr
 	}
 
-	celestiaDA, err := celestia.NewCelestiaRollup(cliCtx, logger)
+	conf, err := _config.LoadRollupConfig(cliCtx.String(_config.ConfigFlagName), cliCtx.Bool(_config.DevnetFlagName))
 	if err != nil {
-		log.Error("NewCelestiaRollup failed", "err", err)
-	} else {
-		log.Debug("finish new celestiaDA")
+		log.Error("load config failed", "err", err)
+		return nil, err
 	}
 
-	eip4844, err := eip4844.NewEip4844Rollup(cliCtx, logger)
+	r, err := NewRollupModuleWithConfig(cliCtx.Context, conf)
 	if err != nil {
-		log.Error("NewEip4844Rollup failed", "err", err)
-	} else {
-		log.Debug("finish new eip4844 rollup")
+		return nil, err
 	}
+	r.ConfigFile = cliCtx.String(_config.ConfigFlagName)
+	r.Devnet = cliCtx.Bool(_config.DevnetFlagName)
+	r.Log = logger
+	return r, nil
+}
 
-	conf := _config.NewRollupConfig() // config for anytrust & anytrust-das-committee & eigenda & nearda
-	anytrustDA, err := anytrust.NewAnytrustDA(conf.AnytrustDAConfig)
-	if err != nil {
-		log.Error("NewAnytrustRollup failed", "err", err)
-	} else {
-		log.Debug("finish new anytrustDA")
+func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, error) {
+	return r.RollupWithTypeContext(r.ctx, data, daType)
+}
+
+// RollupWithTypeContext is RollupWithType bound to the lifetime and trace of the caller's ctx.
+func (r *RollupModule) RollupWithTypeContext(ctx context.Context, data []byte, daType int) ([]interface{}, error) {
+	ctx, span := tracer.Start(ctx, "core.RollupWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+		attribute.Int("data.size", len(data)),
+	))
+	start := time.Now()
+	res, err := r.rollupPayload(ctx, span, data, daType)
+	if err == nil {
+		r.indexContent(data, daType, res)
 	}
+	r.recordDARequest(metrics.OpRollup, daType, len(data), start, err)
+	r.checkBackendOnError(daType, err)
+	tracing.EndSpan(span, err)
+	return res, err
+}
 
-	anytrustDACommittee, err := anytrust.NewAnytrustDAWithCommittee(cliCtx.Context, conf.AnytrustCommitteeConfig, nil)
+// rollupPayload compresses and encrypts data, then rolls it up on its own, in chunks when it is
+// above the DA's ceiling, packed with other submissions by the DA's aggregator when it is small
+// enough, or erasure coded over several DAs.
+func (r *RollupModule) rollupPayload(ctx context.Context, span trace.Span, data []byte, daType int) ([]interface{}, error) {
+	if len(data) == 0 {
+		return nil, errors.New("rollup data cannot be empty")
+	}
+	encoded, codecID, err := r.encode(ctx, daType, data)
 	if err != nil {
-		log.Error("NewAnytrustDAWithCommittee failed", "err", err)
-	} else {
-		log.Debug("finish new anytrustDA committee")
+		return nil, err
 	}
-
-	eigenDA, err := eigenda.NewEigenDAClient(conf.EigenDAConfig)
+	sealed, keyID, err := r.seal(ctx, encoded)
 	if err != nil {
-		log.Error("NewEigenRollup failed", "err", err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("codec", codec.Name(codecID)), attribute.Int("data.encoded_size", len(encoded)),
+		attribute.String("encryption.key_id", keyID))
+
+	var res []interface{}
+	if daType == _common.ErasureType {
+		res, err = r.rollupErasure(ctx, sealed)
+	} else if size := r.config().Chunking.ChunkSize(daType); size > 0 && len(sealed) > size {
+		span.SetAttributes(attribute.Int("chunks", (len(sealed)+size-1)/size))
+		res, err = r.rollupChunked(ctx, sealed, daType, size)
+	} else if agg := r.aggregatorFor(daType); agg != nil && len(sealed) <= agg.conf.MaxPayloadSize {
+		span.SetAttributes(attribute.Bool("aggregated", true))
+		res, err = agg.submit(ctx, sealed)
 	} else {
-		log.Debug("finish new eigenDA")
+		res, err = r.rollupWithType(ctx, sealed, daType)
 	}
-
-	nearDA, err := nearda.NewNearDAClient(conf.NearDAConfig)
 	if err != nil {
-		log.Error("NewNearRollup failed", "err", err)
-	} else {
-		log.Debug("finish new nearDA")
+		return nil, err
 	}
+	r.metrics.RecordCodec(metrics.OpRollup, _common.DATypeName(daType), codec.Name(codecID), len(data), len(encoded))
+	return res, nil
+}
 
-	return &RollupModule{
-		ctx:          cliCtx.Context,
-		RollupConfig: conf,
-
-		anytrustDA:        anytrustDA,
-		anytrustCommittee: anytrustDACommittee,
-		celestiaDA:        celestiaDA,
-		eigenDA:           eigenDA,
-		eip4844:           eip4844,
-		nearDA:            nearDA,
-		Log:               logger,
-	}, nil
-
+func (r *RollupModule) rollupWithType(ctx context.Context, data []byte, daType int) ([]interface{}, error) {
+	if !r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+	return r.dispatchRollup(ctx, data, daType)
 }
 
-func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, error) {
-	if data == nil || len(data) == 0 {
-		return nil, errors.New("rollup data cannot be empty")
+// dispatchRollup stores data on the DA, the caller accounts for the request in r.inflight.
+func (r *RollupModule) dispatchRollup(ctx context.Context, data []byte, daType int) ([]interface{}, error) {
+	if err := r.faults.Before(ctx, daType, fault.OpRollup); err != nil {
+		return nil, err
+	}
+	if da, release, ok := r.mockFor(daType); ok {
+		defer release()
+		return da.Store(ctx, data)
 	}
 	res := make([]interface{}, 0)
 	switch daType {
 	case _common.AnytrustType:
-		if r.anytrustDA == nil {
+		anytrustDA, release, ok := acquire(&r.anytrustDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "anytrustDA")
 			return nil, _errors.DANotPreparedErr
 		}
-		daCert, err := r.anytrustDA.WriteDA(r.ctx, data, r.RollupConfig.AnytrustDAConfig.DataRetentionTime)
+		defer release()
+		retentionTime := uint64(9223372036854775807)
+		if conf := r.config().AnytrustDAConfig; conf != nil {
+			retentionTime = conf.DataRetentionTime
+		}
+		daCert, err := anytrustDA.WriteDA(ctx, data, retentionTime)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "anytrustDA", "err", err)
 			return nil, err
@@ -230,27 +423,32 @@ func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, e
 		return res, nil
 
 	case CelestiaType:
-		if r.celestiaDA == nil {
+		celestiaDA, release, ok := acquire(&r.celestiaDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "celestiaDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 
-		height, err := r.celestiaDA.SubmitBlob(r.ctx, data)
+		receipt, err := celestiaDA.SubmitBlob(ctx, data)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "celestiaDA", "err", err)
 			return nil, err
 		}
-		log.Debug("celestiaDA stored data", "height", height)
+		log.Debug("celestiaDA stored data", "height", receipt.Height, "namespace", receipt.Namespace)
 
-		res = append(res, height)
+		res = append(res, receipt.Height)
+		res = append(res, receipt.String())
 		return res, nil
 	case _common.EigenDAType:
-		if r.eigenDA == nil {
+		eigenDA, release, ok := acquire(&r.eigenDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eigenDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 
-		reqID, err := r.eigenDA.DisperseBlob(r.ctx, data)
+		reqID, err := eigenDA.DisperseBlob(ctx, data)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "eigenDA", "err", err)
 			return nil, err
@@ -260,12 +458,14 @@ func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, e
 		res = append(res, reqIDBase64)
 		return res, nil
 	case _common.Eip4844Type:
-		if r.eip4844 == nil {
+		eip4844, release, ok := acquire(&r.eip4844)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eip4844")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 
-		txHash, err := r.eip4844.SendTransaction(r.ctx, data)
+		txHash, err := eip4844.SendTransaction(ctx, data)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "eip4844", "err", err)
 			return nil, err
@@ -275,12 +475,14 @@ func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, e
 		res = append(res, txHashStr)
 		return res, nil
 	case _common.NearDAType:
-		if r.nearDA == nil {
+		nearDA, release, ok := acquire(&r.nearDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "nearDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 
-		frameRefBytes, err := r.nearDA.Store(data)
+		frameRefBytes, err := nearDA.Store(data)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "nearDA", "err", err)
 			return nil, err
@@ -291,11 +493,13 @@ func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, e
 		res = append(res, base64.StdEncoding.EncodeToString(frameRefBytes))
 		return res, nil
 	case _common.AnytrustCommitteeType:
-		if r.anytrustCommittee == nil {
+		anytrustCommittee, release, ok := acquire(&r.anytrustCommittee)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "anytrust-das-committee")
 			return nil, _errors.DANotPreparedErr
 		}
-		daCert, err := r.anytrustCommittee.WriteDA(r.ctx, data, 9223372036854775807)
+		defer release()
+		daCert, err := anytrustCommittee.WriteDA(ctx, data, 9223372036854775807)
 		if err != nil {
 			log.Error(_errors.RollupFailedMsg, "da-type", "anytrust-das-committee", "err", err)
 			return nil, err
@@ -315,12 +519,103 @@ func (r *RollupModule) RollupWithType(data []byte, daType int) ([]interface{}, e
 }
 
 func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error) {
+	return r.RetrieveFromDAWithTypeContext(r.ctx, daType, args)
+}
+
+// RetrieveFromDAWithTypeContext is RetrieveFromDAWithType bound to the lifetime and trace of the caller's ctx.
+func (r *RollupModule) RetrieveFromDAWithTypeContext(ctx context.Context, daType int, args interface{}) ([]byte, error) {
+	ctx, span := tracer.Start(ctx, "core.RetrieveFromDAWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+	))
+	start := time.Now()
+	var (
+		res []byte
+		err error
+	)
+	if daType == _common.ErasureType {
+		res, err = r.retrieveErasure(ctx, args)
+	} else if aggregate.IsReceipt(args) {
+		span.SetAttributes(attribute.Bool("aggregated", true))
+		res, err = r.retrieveAggregated(ctx, daType, args.(string))
+	} else if chunk.IsReceipt(args) {
+		span.SetAttributes(attribute.Bool("chunked", true))
+		res, err = r.retrieveChunked(ctx, daType, args.(string))
+	} else {
+		res, err = r.retrieveFromDAWithType(ctx, daType, args)
+	}
+	if err == nil {
+		res, err = r.open(ctx, res)
+	}
+	if err == nil {
+		res, err = r.decode(daType, res)
+	}
+	r.recordDARequest(metrics.OpRetrieve, daType, len(res), start, err)
+	r.checkBackendOnError(daType, err)
+	span.SetAttributes(attribute.Int("data.size", len(res)))
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+// retrieveFromDAWithType reads the data stored on daType through the cache, as the DA returned it.
+// The injected faults apply to cached data as well, the cache itself only holds what the DA returned.
+func (r *RollupModule) retrieveFromDAWithType(ctx context.Context, daType int, args interface{}) ([]byte, error) {
+	if err := r.faults.Before(ctx, daType, fault.OpRetrieve); err != nil {
+		return nil, err
+	}
+	res, err := r.cachedFetch(ctx, daType, args)
+	if err != nil {
+		return nil, err
+	}
+	return r.faults.Data(daType, res), nil
+}
+
+func (r *RollupModule) cachedFetch(ctx context.Context, daType int, args interface{}) ([]byte, error) {
+	key, ok := cache.Key(daType, args)
+	if !ok || r.cache == nil {
+		res, _, err := r.fetchFromDA(ctx, daType, args)
+		return res, err
+	}
+	if res, result := r.cache.Get(key); result != cache.Miss {
+		r.metrics.RecordCache(_common.DATypeName(daType), string(result))
+		return res, nil
+	}
+	r.metrics.RecordCache(_common.DATypeName(daType), string(cache.Miss))
+	res, stored, err := r.fetchFromDA(ctx, daType, args)
+	if err == nil {
+		r.cache.Put(daType, key, res, stored)
+	}
+	return res, err
+}
+
+// fetchFromDA reads the data stored on daType, along with the time the DA stored it when the DA tells
+// it and the zero time otherwise.
+func (r *RollupModule) fetchFromDA(ctx context.Context, daType int, args interface{}) ([]byte, time.Time, error) {
+	if !r.inflight.Begin() {
+		return nil, time.Time{}, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+
+	if da, release, ok := r.mockFor(daType); ok {
+		defer release()
+		res, err := da.Retrieve(ctx, args)
+		return res, time.Time{}, err
+	}
+	if daType == _common.Eip4844Type {
+		return r.fetchFromEip4844(ctx, args)
+	}
+	res, err := r.fetchFromBackend(ctx, daType, args)
+	return res, time.Time{}, err
+}
+
+func (r *RollupModule) fetchFromBackend(ctx context.Context, daType int, args interface{}) ([]byte, error) {
 	switch daType {
 	case _common.AnytrustType:
-		if r.anytrustDA == nil {
+		anytrustDA, release, ok := acquire(&r.anytrustDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "anytrustDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 		hashHex, ok := args.(string)
 		if !ok {
 			log.Error("args is not string type")
@@ -328,7 +623,7 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 		}
 		log.Debug("receive rollup request with anytrustDA", "hashHex", hashHex)
 
-		res, err := r.anytrustDA.ReadDA(r.ctx, hashHex)
+		res, err := anytrustDA.ReadDA(ctx, hashHex)
 		if err != nil {
 			log.Error(_errors.GetFromDAErrMsg, "err", err, "hashHex", hashHex, "da-type", "anytrustDA")
 			return nil, err
@@ -337,30 +632,44 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 		log.Debug("get from anytrustDA successfully", "hashHex", hashHex)
 		return res, nil
 	case _common.CelestiaType:
-		if r.celestiaDA == nil {
+		celestiaDA, release, ok := acquire(&r.celestiaDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "celestiaDA")
 			return nil, _errors.DANotPreparedErr
 		}
-		reqHeight, ok := args.(uint64)
-		if !ok {
-			log.Error("args is not string type")
+		defer release()
+		// a bare height is stored under the configured namespace
+		var receipt *celestia.Receipt
+		switch arg := args.(type) {
+		case uint64:
+			receipt = &celestia.Receipt{Height: arg, Namespace: celestiaDA.Namespace}
+		case string:
+			var err error
+			if receipt, err = celestia.ParseReceipt(arg); err != nil {
+				log.Error("parse celestia receipt failed", "err", err, "receipt", arg)
+				return nil, err
+			}
+		default:
+			log.Error("args is neither uint64 nor string type")
 			return nil, _errors.WrongArgTypeErr
 		}
-		log.Debug("request get from celestiaDA", "height", reqHeight)
-		res, err := r.celestiaDA.RetrievedBlobs(r.ctx, reqHeight)
+		log.Debug("request get from celestiaDA", "height", receipt.Height, "namespace", receipt.Namespace)
+		res, err := celestiaDA.RetrieveBlob(ctx, receipt)
 		if err != nil {
-			log.Error(_errors.GetFromDAErrMsg, "err", err, "reqHeight", reqHeight, "da-type", "celestiaDA")
+			log.Error(_errors.GetFromDAErrMsg, "err", err, "receipt", receipt, "da-type", "celestiaDA")
 			return nil, err
 		}
 
-		log.Debug("get from celestiaDA successfully", "reqHeight", reqHeight)
+		log.Debug("get from celestiaDA successfully", "receipt", receipt)
 		return res, nil
 
 	case _common.EigenDAType:
-		if r.eigenDA == nil {
+		eigenDA, release, ok := acquire(&r.eigenDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eigenDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 		reqIDBase64, ok := args.(string)
 		if !ok {
 			log.Error("args is not string type")
@@ -374,7 +683,7 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 			return nil, err
 		}
 
-		status, info, err := r.eigenDA.GetBlobStatus(r.ctx, reqIDByte)
+		status, info, err := eigenDA.GetBlobStatus(ctx, reqIDByte)
 		if err != nil {
 			log.Error(_errors.GetFromDAErrMsg, "err", err, "reqIDBase64", reqIDBase64, "da-type", "eigenDA")
 			return nil, err
@@ -384,7 +693,7 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 			batchHeaderHash, blobIndex := info.BlobVerificationProof.GetBatchMetadata().GetBatchHeaderHash(), info.GetBlobVerificationProof().GetBlobIndex()
 			log.Debug("get from eigenDA", "status", status.String(), "reqIDBase64", reqIDBase64, "batchHeaderHash", hex.EncodeToString(batchHeaderHash), "blobIndex", blobIndex)
 
-			res, err := r.eigenDA.RetrieveBlob(r.ctx, batchHeaderHash, blobIndex)
+			res, err := eigenDA.RetrieveBlob(ctx, batchHeaderHash, blobIndex)
 			if err != nil {
 				log.Error(_errors.GetFromDAErrMsg, "da-type", "eigenDA", "err", err)
 				return nil, err
@@ -399,31 +708,13 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 
 		// Still waiting for confirmation from EigenDA
 		return nil, errors.New("Still waiting for confirmation from EigenDA, please try later")
-	case _common.Eip4844Type:
-		if r.eip4844 == nil {
-			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eip4844")
-			return nil, _errors.DANotPreparedErr
-		}
-		reqTxHashStr, ok := args.(string)
-		if !ok {
-			log.Error("args is not string type")
-			return nil, _errors.WrongArgTypeErr
-		}
-		log.Debug("request get from eip4844", "reqTxHashStr", reqTxHashStr)
-
-		res, err := r.eip4844.DataFromEVMTransactions(r.ctx, reqTxHashStr)
-		if err != nil {
-			log.Error(_errors.GetFromDAErrMsg, "err", err, "reqTxHashStr", reqTxHashStr, "da-type", "eip4844")
-			return nil, err
-		}
-
-		log.Debug("get from eip4844 successfully", "reqTxHashStr", reqTxHashStr)
-		return res, nil
 	case _common.NearDAType:
-		if r.nearDA == nil {
+		nearDA, release, ok := acquire(&r.nearDA)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "nearDA")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 		frameRefBase64, ok := args.(string)
 		if !ok {
 			log.Error("args is not string type")
@@ -440,7 +731,7 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 			return nil, errors.New(fmt.Sprintf("nearda arg length incorrect, expected: larger than 32, got: %d", len(frameRefBytes)))
 		}
 
-		result, err := r.nearDA.GetFromDA(frameRefBytes, binary.BigEndian.Uint32(frameRefBytes[:32]))
+		result, err := nearDA.GetFromDA(frameRefBytes, binary.BigEndian.Uint32(frameRefBytes[:32]))
 		if err != nil {
 			log.Error(_errors.GetFromDAErrMsg, "da-type", "nearDA", "err", err)
 			return nil, err
@@ -449,10 +740,12 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 		log.Debug("get from nearDA successfully")
 		return result, nil
 	case _common.AnytrustCommitteeType:
-		if r.anytrustCommittee == nil {
+		anytrustCommittee, release, ok := acquire(&r.anytrustCommittee)
+		if !ok {
 			log.Error(_errors.DANotPreparedErrMsg, "da-type", "anytrust-das-committee")
 			return nil, _errors.DANotPreparedErr
 		}
+		defer release()
 		hashHex, ok := args.(string)
 		if !ok {
 			log.Error("args is not string type")
@@ -460,7 +753,7 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 		}
 		log.Debug("receive rollup request with anytrust das committee", "hashHex", hashHex)
 
-		res, err := r.anytrustCommittee.ReadDA(r.ctx, hashHex)
+		res, err := anytrustCommittee.ReadDA(ctx, hashHex)
 		if err != nil {
 			log.Error(_errors.GetFromDAErrMsg, "err", err, "hashHex", hashHex, "da-type", "anytrust-das-committee")
 			return nil, err
@@ -473,3 +766,28 @@ func (r *RollupModule) RetrieveFromDAWithType(daType int, args interface{}) ([]b
 	}
 	return nil, _errors.UnknownDATypeErr
 }
+
+// fetchFromEip4844 reads a blob along with the time of the block including it.
+func (r *RollupModule) fetchFromEip4844(ctx context.Context, args interface{}) ([]byte, time.Time, error) {
+	eip4844, release, ok := acquire(&r.eip4844)
+	if !ok {
+		log.Error(_errors.DANotPreparedErrMsg, "da-type", "eip4844")
+		return nil, time.Time{}, _errors.DANotPreparedErr
+	}
+	defer release()
+	reqTxHashStr, ok := args.(string)
+	if !ok {
+		log.Error("args is not string type")
+		return nil, time.Time{}, _errors.WrongArgTypeErr
+	}
+	log.Debug("request get from eip4844", "reqTxHashStr", reqTxHashStr)
+
+	res, included, err := eip4844.DataAndTimeFromEVMTransaction(ctx, reqTxHashStr)
+	if err != nil {
+		log.Error(_errors.GetFromDAErrMsg, "err", err, "reqTxHashStr", reqTxHashStr, "da-type", "eip4844")
+		return nil, time.Time{}, err
+	}
+
+	log.Debug("get from eip4844 successfully", "reqTxHashStr", reqTxHashStr)
+	return res, included, nil
+}
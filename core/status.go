@@ -0,0 +1,158 @@
+package core
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/fault"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+func (r *RollupModule) StatusWithType(daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	return r.StatusWithTypeContext(r.ctx, daType, args)
+}
+
+// StatusWithTypeContext returns the progress of a submission given the receipt returned by RollupWithType.
+// Only eigenda and eip4844 confirm submissions asynchronously.
+func (r *RollupModule) StatusWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	ctx, span := tracer.Start(ctx, "core.StatusWithType", trace.WithAttributes(
+		tracing.DATypeAttr(_common.DATypeName(daType)),
+	))
+	res, err := r.statusWithType(ctx, daType, args)
+	r.checkBackendOnError(daType, err)
+	if res != nil {
+		span.SetAttributes(attribute.String("status", res.Status))
+	}
+	tracing.EndSpan(span, err)
+	return res, err
+}
+
+func (r *RollupModule) statusWithType(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	if !r.inflight.Begin() {
+		return nil, _errors.ShuttingDownErr
+	}
+	defer r.inflight.Done()
+
+	args, err := blobArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.faults.Before(ctx, daType, fault.OpStatus); err != nil {
+		return nil, err
+	}
+	if r.faults.Pending(daType) {
+		return &_common.SubmissionStatus{DAType: daType, Status: "pending"}, nil
+	}
+	if da, release, ok := r.mockFor(daType); ok {
+		defer release()
+		return da.Status(ctx, args)
+	}
+	switch daType {
+	case _common.EigenDAType:
+		eigenDA, release, ok := acquire(&r.eigenDA)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eigenDA")
+			return nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		reqIDBase64, ok := args.(string)
+		if !ok {
+			log.Error("args is not string type")
+			return nil, _errors.WrongArgTypeErr
+		}
+		reqID, err := base64.StdEncoding.DecodeString(reqIDBase64)
+		if err != nil {
+			log.Error("decode base64 reqID into string failed", "err", err, "reqIDBase64", reqIDBase64, "da-type", "eigenDA")
+			return nil, err
+		}
+
+		status, info, err := eigenDA.GetBlobStatus(ctx, reqID)
+		// a failed dispersal is reported as an error along with its status
+		if err != nil && status != disperser.BlobStatus_FAILED && status != disperser.BlobStatus_UNKNOWN {
+			log.Error("get eigenDA blob status failed", "err", err, "reqIDBase64", reqIDBase64)
+			return nil, err
+		}
+		return eigenDAStatus(status, info), nil
+
+	case _common.Eip4844Type:
+		eip4844, release, ok := acquire(&r.eip4844)
+		if !ok {
+			log.Error(_errors.DANotPreparedErrMsg, "da-type", "eip4844")
+			return nil, _errors.DANotPreparedErr
+		}
+		defer release()
+		txHashStr, ok := args.(string)
+		if !ok {
+			log.Error("args is not string type")
+			return nil, _errors.WrongArgTypeErr
+		}
+
+		receipt, err := eip4844.TxReceipt(ctx, txHashStr)
+		if err != nil {
+			log.Error("get eip4844 tx receipt failed", "err", err, "txHash", txHashStr)
+			return nil, err
+		}
+		return eip4844Status(receipt), nil
+
+	case _common.AnytrustType, _common.CelestiaType, _common.NearDAType, _common.AnytrustCommitteeType, _common.ErasureType:
+		return nil, _errors.StatusNotTrackedErr
+	default:
+		log.Error("StatusWithType got unknown da type", "daType", daType, "expected", "[0,5]")
+	}
+	return nil, _errors.UnknownDATypeErr
+}
+
+func eigenDAStatus(status disperser.BlobStatus, info *disperser.BlobInfo) *_common.SubmissionStatus {
+	res := &_common.SubmissionStatus{
+		DAType: _common.EigenDAType,
+		Status: strings.ToLower(status.String()),
+		Final: status == disperser.BlobStatus_FINALIZED ||
+			status == disperser.BlobStatus_FAILED ||
+			status == disperser.BlobStatus_INSUFFICIENT_SIGNATURES,
+	}
+	proof := info.GetBlobVerificationProof()
+	if proof == nil {
+		return res
+	}
+	batch := proof.GetBatchMetadata()
+	res.Detail = map[string]string{
+		"batch_header_hash":         "0x" + hex.EncodeToString(batch.GetBatchHeaderHash()),
+		"batch_root":                "0x" + hex.EncodeToString(batch.GetBatchHeader().GetBatchRoot()),
+		"blob_index":                strconv.FormatUint(uint64(proof.GetBlobIndex()), 10),
+		"reference_block_number":    strconv.FormatUint(uint64(batch.GetBatchHeader().GetReferenceBlockNumber()), 10),
+		"confirmation_block_number": strconv.FormatUint(uint64(batch.GetConfirmationBlockNumber()), 10),
+		"data_length":               strconv.FormatUint(uint64(info.GetBlobHeader().GetDataLength()), 10),
+	}
+	return res
+}
+
+func eip4844Status(receipt *types.Receipt) *_common.SubmissionStatus {
+	if receipt == nil {
+		return &_common.SubmissionStatus{DAType: _common.Eip4844Type, Status: "pending"}
+	}
+	status := "included"
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		status = "reverted"
+	}
+	return &_common.SubmissionStatus{
+		DAType: _common.Eip4844Type,
+		Status: status,
+		Final:  true,
+		Detail: map[string]string{
+			"block_hash":    receipt.BlockHash.Hex(),
+			"block_number":  receipt.BlockNumber.String(),
+			"blob_gas_used": strconv.FormatUint(receipt.BlobGasUsed, 10),
+		},
+	}
+}
@@ -66,3 +66,14 @@ type APIVersionResponse struct {
 type VersionInformation struct {
 	Version string `json:"version"`
 }
+
+type APISyncingResponse struct {
+	Data SyncingInformation `json:"data"`
+}
+
+type SyncingInformation struct {
+	HeadSlot     Uint64String `json:"head_slot"`
+	SyncDistance Uint64String `json:"sync_distance"`
+	IsSyncing    bool         `json:"is_syncing"`
+	IsOptimistic bool         `json:"is_optimistic"`
+}
@@ -13,17 +13,23 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/eniac-x-labs/rollup-node/client"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 const (
 	versionMethod        = "eth/v1/node/version"
+	syncingMethod        = "eth/v1/node/syncing"
 	specMethod           = "eth/v1/config/spec"
 	genesisMethod        = "eth/v1/beacon/genesis"
 	sidecarsMethodPrefix = "eth/v1/beacon/blob_sidecars/"
 )
 
+var tracer = tracing.Tracer("eth-serivce")
+
 type L1BeaconClientConfig struct {
 	FetchAllSidecars bool
 }
@@ -63,7 +69,10 @@ func NewBeaconHTTPClient(cl client.HTTP) *BeaconHTTPClient {
 	return &BeaconHTTPClient{cl}
 }
 
-func (cl *BeaconHTTPClient) apiReq(ctx context.Context, dest any, reqPath string, reqQuery url.Values) error {
+func (cl *BeaconHTTPClient) apiReq(ctx context.Context, dest any, reqPath string, reqQuery url.Values) (err error) {
+	ctx, span := tracer.Start(ctx, "beacon GET "+reqPath, trace.WithSpanKind(trace.SpanKindClient))
+	defer func() { tracing.EndSpan(span, err) }()
+
 	headers := http.Header{}
 	headers.Add("Accept", "application/json")
 	resp, err := cl.cl.Get(ctx, reqPath, reqQuery, headers)
@@ -93,6 +102,14 @@ func (cl *BeaconHTTPClient) NodeVersion(ctx context.Context) (string, error) {
 	return resp.Data.Version, nil
 }
 
+func (cl *BeaconHTTPClient) NodeSyncing(ctx context.Context) (SyncingInformation, error) {
+	var resp APISyncingResponse
+	if err := cl.apiReq(ctx, &resp, syncingMethod, nil); err != nil {
+		return SyncingInformation{}, err
+	}
+	return resp.Data, nil
+}
+
 func (cl *BeaconHTTPClient) ConfigSpec(ctx context.Context) (APIConfigResponse, error) {
 	var configResp APIConfigResponse
 	if err := cl.apiReq(ctx, &configResp, specMethod, nil); err != nil {
@@ -272,7 +289,10 @@ func (cl *L1BeaconClient) GetBlobs(ctx context.Context, ref L1BlockRef, hashes [
 	if err != nil {
 		return nil, fmt.Errorf("failed to get blob sidecars for L1BlockRef %s: %w", ref, err)
 	}
-	return blobsFromSidecars(blobSidecars, hashes)
+	_, span := tracer.Start(ctx, "beacon.VerifyBlobProofs", trace.WithAttributes(attribute.Int("eip4844.blobs", len(blobSidecars))))
+	blobs, err := blobsFromSidecars(blobSidecars, hashes)
+	tracing.EndSpan(span, err)
+	return blobs, err
 }
 
 func blobsFromSidecars(blobSidecars []*BlobSidecar, hashes []IndexedBlobHash) ([]*Blob, error) {
@@ -3,13 +3,14 @@ package flags
 import (
 	"fmt"
 
-	cli_config "github.com/eniac-x-labs/rollup-node/config/cli-config"
 	"github.com/urfave/cli/v2"
 
+	_config "github.com/eniac-x-labs/rollup-node/config"
+
+	"github.com/eniac-x-labs/rollup-node/core"
 	service "github.com/eniac-x-labs/rollup-node/eth-serivce"
 	"github.com/eniac-x-labs/rollup-node/metrics"
-	"github.com/eniac-x-labs/rollup-node/x/celestia"
-	"github.com/eniac-x-labs/rollup-node/x/eip4844"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 const EnvVarPrefix = "DAPP_ROLLUP"
@@ -33,6 +34,20 @@ var exposedAddress = []cli.Flag{
 		Usage:   "Listen address for web server",
 		EnvVars: PrefixEnvVar(EnvVarPrefix, "API_ADDRESS"),
 	},
+	&cli.StringFlag{
+		Name:    "adminToken",
+		Usage:   "Bearer token of the admin endpoints of the web server, they are disabled when empty",
+		EnvVars: PrefixEnvVar(EnvVarPrefix, "ADMIN_TOKEN"),
+	},
+}
+
+var lifecycleFlags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:    "shutdownTimeout",
+		Usage:   "Upper bound for draining in-flight requests and closing DA clients on shutdown",
+		Value:   core.DefaultShutdownTimeout,
+		EnvVars: PrefixEnvVar(EnvVarPrefix, "SHUTDOWN_TIMEOUT"),
+	},
 }
 
 func PrefixEnvVar(prefix, suffix string) []string {
@@ -40,11 +55,11 @@ func PrefixEnvVar(prefix, suffix string) []string {
 }
 
 func init() {
-	optionalFlags = append(optionalFlags, cli_config.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, _config.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, metrics.CLIFlags(EnvVarPrefix)...)
-	optionalFlags = append(optionalFlags, eip4844.CLIFlags(EnvVarPrefix)...)
-	optionalFlags = append(optionalFlags, celestia.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, tracing.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, exposedAddress...)
+	optionalFlags = append(optionalFlags, lifecycleFlags...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
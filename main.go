@@ -23,40 +23,62 @@ func main() {
 	var (
 		rpcAddress string
 		apiAddress string
+		configFile string
 	)
 	flag.StringVar(&rpcAddress, "rpcAddress", "", "listen address for rpc and sdk")
 	flag.StringVar(&apiAddress, "apiAddress", "", "listen address for web server")
+	flag.StringVar(&configFile, "config", _config.DefaultConfigFile, "path of the config file")
 	flag.Parse()
 
 	if len(rpcAddress) == 0 && len(apiAddress) == 0 {
 		flag.Usage()
 	}
 
-	rollupModule, err := _core.NewRollupModuleWithConfig(ctx, _config.NewRollupConfig())
+	rollupConfig, err := _config.LoadRollupConfig(configFile, false)
+	if err != nil {
+		log.Error("load config failed", "err", err)
+		return
+	}
+
+	rollupModule, err := _core.NewRollupModuleWithConfig(ctx, rollupConfig)
 	if err != nil {
 		log.Error("NewRollupModule failed", "err", err)
 		return
 	}
+	rollupModule.ConfigFile = configFile
 
 	// start rpc for sdk
-	//var wg sync.WaitGroup
 	if len(rpcAddress) != 0 {
-		//wg.Add(1)
-		go _rpc.NewAndStartRollupRpcServer(ctx, rpcAddress, rollupModule)
+		rpcServer, err := _rpc.NewRollupRpcServer(rpcAddress, rollupModule)
+		if err != nil {
+			log.Error("NewRollupRpcServer failed", "err", err)
+			return
+		}
+		rollupModule.AddServer("rpc", rpcServer)
 	}
 
-	err = api.NewApi(ctx, logger, apiAddress, rollupModule)
+	apiServer, err := api.NewApi(ctx, logger, apiAddress, rollupModule)
 	if err != nil {
 		log.Error("NewApi failed", "err", err)
 		return
 	}
+	rollupModule.AddServer("api", apiServer)
+	rollupModule.ReloadOnSIGHUP()
+
+	if err := rollupModule.Start(ctx); err != nil {
+		log.Error("start rollup module failed", "err", err)
+		return
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt)
 	<-quit
 	fmt.Println("Shutting down server...")
+
+	if err := rollupModule.Stop(context.Background()); err != nil {
+		log.Error("rollup module stopped with error", "err", err)
+	}
 	cancel()
 
-	//wg.Wait()
 	fmt.Println("Server gracefully stopped")
 }
@@ -0,0 +1,212 @@
+package metrics
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	Namespace = "rollup_node"
+
+	OpRollup   = "rollup"
+	OpRetrieve = "retrieve"
+)
+
+// RollupMetricer records the metrics of the rollup node and its DA backends.
+type RollupMetricer interface {
+	// RecordDARequest records a finished rollup or retrieve request against a DA backend,
+	// errCode is empty for successful requests.
+	RecordDARequest(op string, daType string, size int, duration time.Duration, errCode string)
+	// RecordCodec records the size of a payload before and after the codec of a successful rollup
+	// or retrieve request, codec is "none" for payloads stored raw.
+	RecordCodec(op string, daType string, codec string, rawSize int, encodedSize int)
+	// RecordCache records a lookup of the retrieved data cache, result is the tier that served it
+	// or "miss".
+	RecordCache(daType string, result string)
+	// RecordFault records a fault injected in a request to a DA, see the fault package.
+	RecordFault(daType string, fault string)
+	RecordEigenDAStatusTransition(from string, to string)
+	RecordBlobFee(blobBaseFee *big.Int, blobs int)
+	RecordBeaconFetch(duration time.Duration, err error)
+	Document() []DocumentedMetric
+}
+
+type RollupMetrics struct {
+	factory Factory
+
+	requests        *prometheus.CounterVec
+	errors          *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	payloadBytes    *prometheus.HistogramVec
+
+	codecRawBytes     *prometheus.CounterVec
+	codecEncodedBytes *prometheus.CounterVec
+	codecRatio        *prometheus.HistogramVec
+
+	cacheRequests *prometheus.CounterVec
+	faults        *prometheus.CounterVec
+
+	eigenDAStatusTransitions *prometheus.CounterVec
+
+	blobBaseFee        prometheus.Gauge
+	blobFeePaid        prometheus.Counter
+	beaconFetchLatency *prometheus.HistogramVec
+}
+
+var _ RollupMetricer = (*RollupMetrics)(nil)
+
+func NewRollupMetrics(registry *prometheus.Registry) *RollupMetrics {
+	factory := With(registry)
+	return &RollupMetrics{
+		factory: factory,
+		requests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "da_requests_total",
+			Help:      "Count of rollup and retrieve requests per DA type",
+		}, []string{"op", "da_type"}),
+		errors: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "da_errors_total",
+			Help:      "Count of failed rollup and retrieve requests per DA type and error code",
+		}, []string{"op", "da_type", "code"}),
+		requestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "da_request_duration_seconds",
+			Help:      "Latency of rollup and retrieve requests per DA type",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+		}, []string{"op", "da_type"}),
+		payloadBytes: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "da_payload_bytes",
+			Help:      "Size of rolled up and retrieved payloads per DA type",
+			Buckets:   prometheus.ExponentialBuckets(256, 4, 8),
+		}, []string{"op", "da_type"}),
+		codecRawBytes: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "codec",
+			Name:      "raw_bytes_total",
+			Help:      "Bytes of payloads before compression per DA type and codec",
+		}, []string{"op", "da_type", "codec"}),
+		codecEncodedBytes: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "codec",
+			Name:      "encoded_bytes_total",
+			Help:      "Bytes of payloads as stored on the DA per DA type and codec",
+		}, []string{"op", "da_type", "codec"}),
+		codecRatio: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "codec",
+			Name:      "compression_ratio",
+			Help:      "Stored size over raw size of payloads per DA type and codec",
+			Buckets:   []float64{.05, .1, .2, .3, .4, .5, .6, .7, .8, .9, 1},
+		}, []string{"op", "da_type", "codec"}),
+		cacheRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "cache",
+			Name:      "requests_total",
+			Help:      "Count of retrieved data cache lookups per DA type and result",
+		}, []string{"da_type", "result"}),
+		faults: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "faults_injected_total",
+			Help:      "Count of faults injected in the DA requests per DA type and fault",
+		}, []string{"da_type", "fault"}),
+		eigenDAStatusTransitions: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "eigenda",
+			Name:      "blob_status_transitions_total",
+			Help:      "Count of observed EigenDA blob status transitions",
+		}, []string{"from", "to"}),
+		blobBaseFee: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: "eip4844",
+			Name:      "blob_base_fee_wei",
+			Help:      "Blob base fee of the latest submitted blob transaction",
+		}),
+		blobFeePaid: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: "eip4844",
+			Name:      "blob_fee_paid_gwei_total",
+			Help:      "Total blob fee committed by submitted blob transactions",
+		}),
+		beaconFetchLatency: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: "eip4844",
+			Name:      "beacon_fetch_duration_seconds",
+			Help:      "Latency of fetching blob sidecars from the beacon node",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+		}, []string{"result"}),
+	}
+}
+
+func (m *RollupMetrics) RecordDARequest(op string, daType string, size int, duration time.Duration, errCode string) {
+	m.requests.WithLabelValues(op, daType).Inc()
+	m.requestDuration.WithLabelValues(op, daType).Observe(duration.Seconds())
+	if errCode != "" {
+		m.errors.WithLabelValues(op, daType, errCode).Inc()
+		return
+	}
+	m.payloadBytes.WithLabelValues(op, daType).Observe(float64(size))
+}
+
+func (m *RollupMetrics) RecordCodec(op string, daType string, codec string, rawSize int, encodedSize int) {
+	m.codecRawBytes.WithLabelValues(op, daType, codec).Add(float64(rawSize))
+	m.codecEncodedBytes.WithLabelValues(op, daType, codec).Add(float64(encodedSize))
+	if rawSize > 0 {
+		m.codecRatio.WithLabelValues(op, daType, codec).Observe(float64(encodedSize) / float64(rawSize))
+	}
+}
+
+func (m *RollupMetrics) RecordCache(daType string, result string) {
+	m.cacheRequests.WithLabelValues(daType, result).Inc()
+}
+
+func (m *RollupMetrics) RecordFault(daType string, fault string) {
+	m.faults.WithLabelValues(daType, fault).Inc()
+}
+
+func (m *RollupMetrics) RecordEigenDAStatusTransition(from string, to string) {
+	m.eigenDAStatusTransitions.WithLabelValues(from, to).Inc()
+}
+
+func (m *RollupMetrics) RecordBlobFee(blobBaseFee *big.Int, blobs int) {
+	if blobBaseFee == nil {
+		return
+	}
+	baseFee, _ := new(big.Float).SetInt(blobBaseFee).Float64()
+	m.blobBaseFee.Set(baseFee)
+
+	paid := new(big.Int).Mul(blobBaseFee, big.NewInt(int64(blobs*params.BlobTxBlobGasPerBlob)))
+	paidGwei, _ := new(big.Float).Quo(new(big.Float).SetInt(paid), big.NewFloat(params.GWei)).Float64()
+	m.blobFeePaid.Add(paidGwei)
+}
+
+func (m *RollupMetrics) RecordBeaconFetch(duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	m.beaconFetchLatency.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+func (m *RollupMetrics) Document() []DocumentedMetric {
+	return m.factory.Document()
+}
+
+type noopRollupMetrics struct{}
+
+var NoopRollupMetrics RollupMetricer = new(noopRollupMetrics)
+
+func (*noopRollupMetrics) RecordDARequest(op string, daType string, size int, duration time.Duration, errCode string) {
+}
+func (*noopRollupMetrics) RecordCodec(op string, daType string, codec string, rawSize int, encodedSize int) {
+}
+func (*noopRollupMetrics) RecordCache(daType string, result string)             {}
+func (*noopRollupMetrics) RecordFault(daType string, fault string)              {}
+func (*noopRollupMetrics) RecordEigenDAStatusTransition(from string, to string) {}
+func (*noopRollupMetrics) RecordBlobFee(blobBaseFee *big.Int, blobs int)        {}
+func (*noopRollupMetrics) RecordBeaconFetch(duration time.Duration, err error)  {}
+func (*noopRollupMetrics) Document() []DocumentedMetric                         { return nil }
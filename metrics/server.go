@@ -7,17 +7,18 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/eniac-x-labs/rollup-node/api/common/httputil"
 )
 
-func StartServer(r *prometheus.Registry, hostname string, port int) (string, error) {
+// StartServer serves the metrics of the given registry on /metrics in the background.
+func StartServer(r *prometheus.Registry, hostname string, port int) (*httputil.HTTPServer, error) {
 	addr := net.JoinHostPort(hostname, strconv.Itoa(port))
 	h := promhttp.InstrumentMetricHandler(
 		r, promhttp.HandlerFor(r, promhttp.HandlerOpts{}),
 	)
-	http.Handle("/metrics", promhttp.Handler())
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", h)
 
-	if err := http.ListenAndServe(addr, h); err != nil {
-		return "", err
-	}
-	return addr, nil
+	return httputil.StartHTTPServer(addr, mux)
 }
@@ -1,13 +1,34 @@
 package rpc
 
+import (
+	"context"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+)
+
 type RollupInter interface {
 	RollupWithType(data []byte, daType int) ([]interface{}, error)
 	RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error)
+	RollupWithTypeContext(ctx context.Context, data []byte, daType int) ([]interface{}, error)
+	RetrieveFromDAWithTypeContext(ctx context.Context, daType int, args interface{}) ([]byte, error)
+	StatusWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error)
+	ProofWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error)
+	AttestationWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error)
+	EstimateCostWithTypeContext(ctx context.Context, daType int, size int) (*_common.CostEstimate, error)
+	RetrieveByHashContext(ctx context.Context, hash string) (*_common.Content, error)
+	HealthCheck(ctx context.Context) (*health.Report, error)
 }
 
 type DRNGRpcInterface interface {
 	Rollup(req RollupRequest, reply *[]interface{}) error
 	Retrieve(req RetrieveRequest, reply *[]byte) error
+	Status(req StatusRequest, reply *_common.SubmissionStatus) error
+	Proof(req ProofRequest, reply *_common.InclusionProof) error
+	Attestation(req AttestationRequest, reply *_common.Attestation) error
+	EstimateCost(req EstimateCostRequest, reply *_common.CostEstimate) error
+	Content(req ContentRequest, reply *_common.Content) error
+	Health(req HealthRequest, reply *health.Report) error
 }
 
 //type DAInter interface {
@@ -2,62 +2,219 @@ package rpc
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
 	"net/rpc"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+
+	"go.opentelemetry.io/otel/trace"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 type RollupRequest struct {
 	DAType int
 	Data   []byte
+	// Namespace is the tenant or namespace to store the data under, see common.WithNamespace.
+	Namespace string
+	// Codec overrides the codec configured for the DA, see common.WithCodec.
+	Codec string
+	// KeyID is the keyring key to encrypt the data with, see common.WithKeyID.
+	KeyID string
+	// TraceCarrier holds the caller's span context, see tracing.Inject.
+	TraceCarrier map[string]string
 }
 
 type RetrieveRequest struct {
 	DAType int
 	Args   interface{}
+	// AuthToken lets the caller read the plaintext of encrypted payloads, see common.WithAuthToken.
+	AuthToken    string
+	TraceCarrier map[string]string
+}
+
+type StatusRequest struct {
+	DAType       int
+	Args         interface{}
+	TraceCarrier map[string]string
+}
+
+type ProofRequest struct {
+	DAType       int
+	Args         interface{}
+	TraceCarrier map[string]string
 }
 
+type AttestationRequest struct {
+	DAType       int
+	Args         interface{}
+	TraceCarrier map[string]string
+}
+
+type EstimateCostRequest struct {
+	DAType int
+	// Size is the number of bytes submitted to the DA
+	Size         int
+	TraceCarrier map[string]string
+}
+
+type ContentRequest struct {
+	// Hash is the sha256 or keccak256 of the payload
+	Hash string
+	// AuthToken lets the caller read the plaintext of encrypted payloads, see common.WithAuthToken.
+	AuthToken    string
+	TraceCarrier map[string]string
+}
+
+type HealthRequest struct{}
+
+var tracer = tracing.Tracer("rpc")
+
+// acceptRetryInterval throttles the accept loop after a transient listener error.
+const acceptRetryInterval = 100 * time.Millisecond
+
 type RollupRpcServer struct {
 	RollupInter
 }
 
-func NewAndStartRollupRpcServer(ctx context.Context, address string, rollup RollupInter) {
-	if err := rpc.Register(&RollupRpcServer{
-		rollup,
-	}); err != nil {
+// RpcServer serves RollupRpcServer over net/rpc, it implements cliapp.Lifecycle.
+type RpcServer struct {
+	address  string
+	server   *rpc.Server
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	wg    sync.WaitGroup
+
+	stopped atomic.Bool
+}
+
+func NewRollupRpcServer(address string, rollup RollupInter) (*RpcServer, error) {
+	server := rpc.NewServer()
+	if err := server.Register(&RollupRpcServer{rollup}); err != nil {
 		log.Error("RpcServer Register failed", "err", err)
-		return
+		return nil, err
 	}
 	log.Debug("RpcServer Register finished")
 
-	listener, err := net.Listen("tcp", address)
+	return &RpcServer{
+		address: address,
+		server:  server,
+		conns:   make(map[net.Conn]struct{}),
+	}, nil
+}
+
+func (s *RpcServer) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.address)
 	if err != nil {
-		log.Error("RpcServer Listen failed", "err", err, "address", address)
-		return
+		log.Error("RpcServer Listen failed", "err", err, "address", s.address)
+		return err
 	}
-	log.Debug("RpcServer listen address finished", "address", address)
+	s.listener = listener
+	log.Info("RpcServer started", "address", listener.Addr().String())
 
+	s.wg.Add(1)
+	go s.acceptLoop()
+	return nil
+}
+
+func (s *RpcServer) acceptLoop() {
+	defer s.wg.Done()
 	for {
-		select {
-		case <-ctx.Done():
-			listener.Close()
-			log.Info("rollup rpc listener closed successfully")
-			return
-		default:
-			conn, err := listener.Accept()
-			if err != nil {
-				log.Error("RpcServer listener.Accept failed", "err", err)
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
 			}
+			log.Error("RpcServer listener.Accept failed", "err", err)
+			time.Sleep(acceptRetryInterval)
+			continue
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.server.ServeConn(conn)
+
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Stop closes the listener and the read side of every client connection, so no new
+// calls are read while the pending ones still get their reply. Connections left once
+// ctx is done are force-closed.
+func (s *RpcServer) Stop(ctx context.Context) error {
+	if s.stopped.Swap(true) {
+		return nil
+	}
+	if s.listener == nil {
+		return nil
+	}
 
-			go rpc.ServeConn(conn)
+	result := s.listener.Close()
+
+	s.mu.Lock()
+	for conn := range s.conns {
+		if c, ok := conn.(interface{ CloseRead() error }); ok {
+			_ = c.CloseRead()
+		} else {
+			_ = conn.Close()
+		}
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			_ = conn.Close()
 		}
+		s.mu.Unlock()
+		result = errors.Join(result, fmt.Errorf("rpc calls did not finish: %w", ctx.Err()))
 	}
+
+	log.Info("rollup rpc server stopped")
+	return result
+}
+
+func (s *RpcServer) Stopped() bool {
+	return s.stopped.Load()
+}
+
+// Addr returns the listening address, it is only set once the server started.
+func (s *RpcServer) Addr() net.Addr {
+	return s.listener.Addr()
 }
 
 func (s *RollupRpcServer) Rollup(req RollupRequest, reply *[]interface{}) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Rollup",
+		trace.WithSpanKind(trace.SpanKindServer))
 	var err error
-	*reply, err = s.RollupWithType(req.Data, req.DAType)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	ctx = _common.WithKeyID(_common.WithCodec(_common.WithNamespace(ctx, req.Namespace), req.Codec), req.KeyID)
+	*reply, err = s.RollupWithTypeContext(ctx, req.Data, req.DAType)
 	if err != nil {
 		return err
 	}
@@ -66,10 +223,93 @@ func (s *RollupRpcServer) Rollup(req RollupRequest, reply *[]interface{}) error
 }
 
 func (s *RollupRpcServer) Retrieve(req RetrieveRequest, reply *[]byte) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Retrieve",
+		trace.WithSpanKind(trace.SpanKindServer))
+	var err error
+	defer func() { tracing.EndSpan(span, err) }()
+
+	*reply, err = s.RetrieveFromDAWithTypeContext(_common.WithAuthToken(ctx, req.AuthToken), req.DAType, req.Args)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *RollupRpcServer) Status(req StatusRequest, reply *_common.SubmissionStatus) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Status",
+		trace.WithSpanKind(trace.SpanKindServer))
+	var err error
+	defer func() { tracing.EndSpan(span, err) }()
+
+	status, err := s.StatusWithTypeContext(ctx, req.DAType, req.Args)
+	if err != nil {
+		return err
+	}
+	*reply = *status
+	return nil
+}
+
+func (s *RollupRpcServer) Proof(req ProofRequest, reply *_common.InclusionProof) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Proof",
+		trace.WithSpanKind(trace.SpanKindServer))
+	var err error
+	defer func() { tracing.EndSpan(span, err) }()
+
+	proof, err := s.ProofWithTypeContext(ctx, req.DAType, req.Args)
+	if err != nil {
+		return err
+	}
+	*reply = *proof
+	return nil
+}
+
+func (s *RollupRpcServer) Attestation(req AttestationRequest, reply *_common.Attestation) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Attestation",
+		trace.WithSpanKind(trace.SpanKindServer))
 	var err error
-	*reply, err = s.RetrieveFromDAWithType(req.DAType, req.Args)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	attestation, err := s.AttestationWithTypeContext(ctx, req.DAType, req.Args)
+	if err != nil {
+		return err
+	}
+	*reply = *attestation
+	return nil
+}
+
+func (s *RollupRpcServer) EstimateCost(req EstimateCostRequest, reply *_common.CostEstimate) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.EstimateCost",
+		trace.WithSpanKind(trace.SpanKindServer))
+	var err error
+	defer func() { tracing.EndSpan(span, err) }()
+
+	estimate, err := s.EstimateCostWithTypeContext(ctx, req.DAType, req.Size)
+	if err != nil {
+		return err
+	}
+	*reply = *estimate
+	return nil
+}
+
+func (s *RollupRpcServer) Content(req ContentRequest, reply *_common.Content) error {
+	ctx, span := tracer.Start(tracing.Extract(context.Background(), req.TraceCarrier), "rpc.Content",
+		trace.WithSpanKind(trace.SpanKindServer))
+	var err error
+	defer func() { tracing.EndSpan(span, err) }()
+
+	res, err := s.RetrieveByHashContext(_common.WithAuthToken(ctx, req.AuthToken), req.Hash)
+	if err != nil {
+		return err
+	}
+	*reply = *res
+	return nil
+}
+
+func (s *RollupRpcServer) Health(req HealthRequest, reply *health.Report) error {
+	report, err := s.HealthCheck(context.Background())
 	if err != nil {
 		return err
 	}
+	*reply = *report
 	return nil
 }
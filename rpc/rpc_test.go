@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+	"net/rpc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+)
+
+type slowRollup struct {
+	delay time.Duration
+}
+
+func (s *slowRollup) RollupWithType(data []byte, daType int) ([]interface{}, error) {
+	return s.RollupWithTypeContext(context.Background(), data, daType)
+}
+
+func (s *slowRollup) RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error) {
+	return s.RetrieveFromDAWithTypeContext(context.Background(), daType, args)
+}
+
+func (s *slowRollup) RollupWithTypeContext(ctx context.Context, data []byte, daType int) ([]interface{}, error) {
+	time.Sleep(s.delay)
+	return []interface{}{"receipt"}, nil
+}
+
+func (s *slowRollup) RetrieveFromDAWithTypeContext(ctx context.Context, daType int, args interface{}) ([]byte, error) {
+	time.Sleep(s.delay)
+	return []byte("data"), nil
+}
+
+func (s *slowRollup) StatusWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	return &_common.SubmissionStatus{DAType: daType, Status: "confirmed"}, nil
+}
+
+func (s *slowRollup) ProofWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error) {
+	return &_common.InclusionProof{DAType: daType, Included: true}, nil
+}
+
+func (s *slowRollup) AttestationWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error) {
+	return &_common.Attestation{DAType: daType}, nil
+}
+
+func (s *slowRollup) EstimateCostWithTypeContext(ctx context.Context, daType int, size int) (*_common.CostEstimate, error) {
+	return &_common.CostEstimate{DAType: daType, Size: size, Amount: "0", Token: "ETH", Decimals: 18}, nil
+}
+
+func (s *slowRollup) RetrieveByHashContext(ctx context.Context, hash string) (*_common.Content, error) {
+	return &_common.Content{DAType: _common.CelestiaType, Receipt: "receipt", Data: []byte("data")}, nil
+}
+
+func (s *slowRollup) HealthCheck(ctx context.Context) (*health.Report, error) {
+	return &health.Report{Ready: true}, nil
+}
+
+func TestRpcServerStopWaitsForPendingCalls(t *testing.T) {
+	srv, err := NewRollupRpcServer("127.0.0.1:0", &slowRollup{delay: 200 * time.Millisecond})
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	client, err := rpc.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	var reply []interface{}
+	call := client.Go("RollupRpcServer.Rollup", RollupRequest{DAType: 1, Data: []byte("data")}, &reply, nil)
+	time.Sleep(50 * time.Millisecond)
+
+	require.NoError(t, srv.Stop(context.Background()))
+	require.True(t, srv.Stopped())
+
+	<-call.Done
+	require.NoError(t, call.Error)
+	require.Equal(t, []interface{}{"receipt"}, reply)
+
+	_, err = rpc.Dial("tcp", srv.Addr().String())
+	require.Error(t, err)
+}
+
+func TestRpcServerStopIsBounded(t *testing.T) {
+	srv, err := NewRollupRpcServer("127.0.0.1:0", &slowRollup{delay: 5 * time.Second})
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+
+	client, err := rpc.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	var reply []byte
+	client.Go("RollupRpcServer.Retrieve", RetrieveRequest{DAType: 1, Args: "receipt"}, &reply, nil)
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.ErrorIs(t, srv.Stop(ctx), context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+}
+
+func TestRpcServerContent(t *testing.T) {
+	srv, err := NewRollupRpcServer("127.0.0.1:0", &slowRollup{})
+	require.NoError(t, err)
+	require.NoError(t, srv.Start(context.Background()))
+	defer srv.Stop(context.Background())
+
+	client, err := rpc.Dial("tcp", srv.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	var reply _common.Content
+	require.NoError(t, client.Call("RollupRpcServer.Content", ContentRequest{Hash: "0x0a0b"}, &reply))
+	require.Equal(t, _common.Content{DAType: _common.CelestiaType, Receipt: "receipt", Data: []byte("data")}, reply)
+}
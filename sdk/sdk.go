@@ -1,8 +1,13 @@
 package sdk
 
 import (
+	"context"
 	"net/rpc"
 
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/health"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+
 	_rpc "github.com/eniac-x-labs/rollup-node/rpc"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -21,19 +26,150 @@ func NewRollupSdk(addr string) (_rpc.RollupInter, error) {
 }
 
 func (s *RollupSDK) RollupWithType(data []byte, daType int) ([]interface{}, error) {
+	return s.RollupWithTypeContext(context.Background(), data, daType)
+}
+
+func (s *RollupSDK) RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error) {
+	return s.RetrieveFromDAWithTypeContext(context.Background(), daType, args)
+}
+
+// RollupWithTypeContext propagates the span, the namespace, the codec and the key id in ctx to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) RollupWithTypeContext(ctx context.Context, data []byte, daType int) ([]interface{}, error) {
 	var res []interface{}
-	err := s.Call("RollupRpcServer.Rollup", _rpc.RollupRequest{
-		DAType: daType,
-		Data:   data,
+	err := s.call(ctx, "RollupRpcServer.Rollup", _rpc.RollupRequest{
+		DAType:       daType,
+		Data:         data,
+		Namespace:    _common.NamespaceFromContext(ctx),
+		Codec:        _common.CodecFromContext(ctx),
+		KeyID:        _common.KeyIDFromContext(ctx),
+		TraceCarrier: tracing.Inject(ctx),
 	}, &res)
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func (s *RollupSDK) RetrieveFromDAWithType(daType int, args interface{}) ([]byte, error) {
+// RetrieveFromDAWithTypeContext propagates the span and the auth token in ctx to the node and gives up waiting
+// once ctx is done.
+func (s *RollupSDK) RetrieveFromDAWithTypeContext(ctx context.Context, daType int, args interface{}) ([]byte, error) {
 	var res []byte
-	err := s.Call("RollupRpcServer.Retrieve", _rpc.RetrieveRequest{
-		DAType: daType,
-		Args:   args,
+	err := s.call(ctx, "RollupRpcServer.Retrieve", _rpc.RetrieveRequest{
+		DAType:       daType,
+		Args:         args,
+		AuthToken:    _common.AuthTokenFromContext(ctx),
+		TraceCarrier: tracing.Inject(ctx),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *RollupSDK) StatusWithType(daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	return s.StatusWithTypeContext(context.Background(), daType, args)
+}
+
+// StatusWithTypeContext propagates the span in ctx to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) StatusWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.SubmissionStatus, error) {
+	var res _common.SubmissionStatus
+	err := s.call(ctx, "RollupRpcServer.Status", _rpc.StatusRequest{
+		DAType:       daType,
+		Args:         args,
+		TraceCarrier: tracing.Inject(ctx),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (s *RollupSDK) ProofWithType(daType int, args interface{}) (*_common.InclusionProof, error) {
+	return s.ProofWithTypeContext(context.Background(), daType, args)
+}
+
+// ProofWithTypeContext propagates the span in ctx to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) ProofWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.InclusionProof, error) {
+	var res _common.InclusionProof
+	err := s.call(ctx, "RollupRpcServer.Proof", _rpc.ProofRequest{
+		DAType:       daType,
+		Args:         args,
+		TraceCarrier: tracing.Inject(ctx),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (s *RollupSDK) AttestationWithType(daType int, args interface{}) (*_common.Attestation, error) {
+	return s.AttestationWithTypeContext(context.Background(), daType, args)
+}
+
+// AttestationWithTypeContext propagates the span in ctx to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) AttestationWithTypeContext(ctx context.Context, daType int, args interface{}) (*_common.Attestation, error) {
+	var res _common.Attestation
+	err := s.call(ctx, "RollupRpcServer.Attestation", _rpc.AttestationRequest{
+		DAType:       daType,
+		Args:         args,
+		TraceCarrier: tracing.Inject(ctx),
 	}, &res)
-	return res, err
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (s *RollupSDK) EstimateCostWithType(daType int, size int) (*_common.CostEstimate, error) {
+	return s.EstimateCostWithTypeContext(context.Background(), daType, size)
+}
+
+// EstimateCostWithTypeContext propagates the span in ctx to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) EstimateCostWithTypeContext(ctx context.Context, daType int, size int) (*_common.CostEstimate, error) {
+	var res _common.CostEstimate
+	err := s.call(ctx, "RollupRpcServer.EstimateCost", _rpc.EstimateCostRequest{
+		DAType:       daType,
+		Size:         size,
+		TraceCarrier: tracing.Inject(ctx),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// RetrieveByHashContext retrieves the payload with the sha256 or keccak256 hash from any DA the node
+// stored it on, the node checks the hash on the data. It propagates the span and the auth token in ctx
+// to the node and gives up waiting once ctx is done.
+func (s *RollupSDK) RetrieveByHashContext(ctx context.Context, hash string) (*_common.Content, error) {
+	var res _common.Content
+	err := s.call(ctx, "RollupRpcServer.Content", _rpc.ContentRequest{
+		Hash:         hash,
+		AuthToken:    _common.AuthTokenFromContext(ctx),
+		TraceCarrier: tracing.Inject(ctx),
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// call abandons the reply once ctx is done, the result must not be read after an error.
+func (s *RollupSDK) call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	call := s.Go(serviceMethod, args, reply, nil)
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HealthCheck returns the health report of the node, it gives up waiting once ctx is done.
+func (s *RollupSDK) HealthCheck(ctx context.Context) (*health.Report, error) {
+	var res health.Report
+	if err := s.call(ctx, "RollupRpcServer.Health", _rpc.HealthRequest{}, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
 }
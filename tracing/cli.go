@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	EnabledFlagName     = "tracing.enabled"
+	ExporterFlagName    = "tracing.exporter"
+	EndpointFlagName    = "tracing.endpoint"
+	InsecureFlagName    = "tracing.insecure"
+	FileFlagName        = "tracing.file"
+	SampleRatioFlagName = "tracing.sample-ratio"
+
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	defaultEndpoint    = "localhost:4317"
+	defaultSampleRatio = 1.0
+)
+
+func DefaultCLIConfig() CLIConfig {
+	return CLIConfig{
+		Enabled:     false,
+		Exporter:    ExporterOTLP,
+		Endpoint:    defaultEndpoint,
+		SampleRatio: defaultSampleRatio,
+	}
+}
+
+func PrefixEnvVar(prefix, suffix string) []string {
+	return []string{prefix + "_" + suffix}
+}
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		&cli.BoolFlag{
+			Name:    EnabledFlagName,
+			Usage:   "Enable OpenTelemetry tracing",
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_ENABLED"),
+		},
+		&cli.StringFlag{
+			Name:    ExporterFlagName,
+			Usage:   fmt.Sprintf("Span exporter, one of %s, %s, %s", ExporterOTLP, ExporterStdout, ExporterFile),
+			Value:   ExporterOTLP,
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_EXPORTER"),
+		},
+		&cli.StringFlag{
+			Name:    EndpointFlagName,
+			Usage:   "OTLP gRPC collector endpoint",
+			Value:   defaultEndpoint,
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_ENDPOINT"),
+		},
+		&cli.BoolFlag{
+			Name:    InsecureFlagName,
+			Usage:   "Connect to the OTLP collector without TLS",
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_INSECURE"),
+		},
+		&cli.StringFlag{
+			Name:    FileFlagName,
+			Usage:   "File spans are appended to when the file exporter is used",
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_FILE"),
+		},
+		&cli.Float64Flag{
+			Name:    SampleRatioFlagName,
+			Usage:   "Fraction of new traces to sample, between 0 and 1",
+			Value:   defaultSampleRatio,
+			EnvVars: PrefixEnvVar(envPrefix, "TRACING_SAMPLE_RATIO"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	Enabled     bool    `toml:"enable"`
+	Exporter    string  `toml:"exporter"`
+	Endpoint    string  `toml:"endpoint"`
+	Insecure    bool    `toml:"insecure"`
+	File        string  `toml:"file"`
+	SampleRatio float64 `toml:"sampleRatio"`
+}
+
+func (c CLIConfig) Check() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	switch c.Exporter {
+	case ExporterOTLP:
+		if c.Endpoint == "" {
+			return errors.New("tracing endpoint is required for the otlp exporter")
+		}
+	case ExporterFile:
+		if c.File == "" {
+			return errors.New("tracing file is required for the file exporter")
+		}
+	case ExporterStdout:
+	default:
+		return fmt.Errorf("unknown tracing exporter %q", c.Exporter)
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return errors.New("tracing sample ratio must be between 0 and 1")
+	}
+
+	return nil
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		Enabled:     ctx.Bool(EnabledFlagName),
+		Exporter:    ctx.String(ExporterFlagName),
+		Endpoint:    ctx.String(EndpointFlagName),
+		Insecure:    ctx.Bool(InsecureFlagName),
+		File:        ctx.String(FileFlagName),
+		SampleRatio: ctx.Float64(SampleRatioFlagName),
+	}
+}
@@ -11,22 +11,3 @@ type AnytrustConfig struct {
 	//SigningWallet         string        `toml:"signingWallet"`
 	//SigningWalletPassword string        `toml:"signingWalletPassword"`
 }
-
-const (
-	RpcUrlFlag            = "rpc_url"
-	RestfulUrlFlag        = "restful_url"
-	DataRetentionTimeFlag = "data_retention_time"
-	RandomMessageSizeFlag = "random_message_size"
-	//DasRetentionPeriodFlag = "dasRetentionPeriod"
-	SigningKeyFlag = "signing_key"
-)
-
-// AnytrustDAEnvFlags The env flag is like prefix_flag, with all letters in uppercase.
-var AnytrustDAEnvFlags = []string{
-	RpcUrlFlag,
-	RestfulUrlFlag,
-	DataRetentionTimeFlag,
-	RandomMessageSizeFlag,
-	//DasRetentionPeriodFlag,
-	SigningKeyFlag,
-}
@@ -3,27 +3,40 @@ package anytrust
 import (
 	"context"
 	"crypto/ecdsa"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/eniac-x-labs/anytrustDA/arbstate"
 	"github.com/eniac-x-labs/anytrustDA/das"
 	"github.com/eniac-x-labs/anytrustDA/util/signature"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
+var tracer = tracing.Tracer("x/anytrust")
+
 type IAnytrustDA interface {
 	WriteDA(ctx context.Context, data []byte, retentionTime uint64) (*arbstate.DataAvailabilityCertificate, error)
 	ReadDA(ctx context.Context, hashHex string) ([]byte, error)
+	HealthCheck(ctx context.Context) error
+	Close() error
 }
 
+// committeeStopTimeout bounds how long Close waits for the committee's background services.
+const committeeStopTimeout = 10 * time.Second
+
 type AnytrustDACommittee struct {
 	das.DataAvailabilityServiceWriter
 	das.DataAvailabilityServiceReader
 	*das.LifecycleManager
 }
 
-func NewAnytrustDAWithCommittee(ctx context.Context, daConfig *das.DataAvailabilityConfig, dataSigner signature.DataSignerFunc) (*AnytrustDACommittee, error) {
+func NewAnytrustDAWithCommittee(ctx context.Context, daConfig *das.DataAvailabilityConfig, dataSigner signature.DataSignerFunc) (IAnytrustDA, error) {
 	//daWriter, daReader, dasLifecycleManager, err := _das.CreateBatchPosterDAS(ctx, daConfig, dataSigner, l1client, deployInfo.SequencerInbox)
 
 	daWriter, daReader, lifeManager, err := das.CreateAggregatorComponents(ctx, daConfig, dataSigner)
@@ -37,20 +50,48 @@ func NewAnytrustDAWithCommittee(ctx context.Context, daConfig *das.DataAvailabil
 	}, nil
 }
 
-func (a *AnytrustDACommittee) WriteDA(ctx context.Context, data []byte, retentionTime uint64) (*arbstate.DataAvailabilityCertificate, error) {
+func (a *AnytrustDACommittee) WriteDA(ctx context.Context, data []byte, retentionTime uint64) (cert *arbstate.DataAvailabilityCertificate, err error) {
+	ctx, span := tracer.Start(ctx, "anytrust.committee.Store", trace.WithAttributes(attribute.Int("data.size", len(data))))
+	defer func() { tracing.EndSpan(span, err) }()
 	return a.Store(ctx, data, retentionTime, nil)
 }
 
-func (a *AnytrustDACommittee) ReadDA(ctx context.Context, hashHex string) ([]byte, error) {
+func (a *AnytrustDACommittee) ReadDA(ctx context.Context, hashHex string) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "anytrust.committee.GetByHash")
+	defer func() { tracing.EndSpan(span, err) }()
 	if strings.HasPrefix(hashHex, "0x") {
 		hashHex = hashHex[2:]
 	}
 	return a.GetByHash(ctx, common.HexToHash(hashHex))
 }
 
+// HealthCheck probes the aggregated writer and reader, if they support it.
+func (a *AnytrustDACommittee) HealthCheck(ctx context.Context) error {
+	if checker, ok := a.DataAvailabilityServiceWriter.(das.DataAvailabilityServiceHealthChecker); ok {
+		if err := checker.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("anytrust das committee writer unhealthy: %w", err)
+		}
+	}
+	if checker, ok := a.DataAvailabilityServiceReader.(das.DataAvailabilityServiceHealthChecker); ok {
+		if err := checker.HealthCheck(ctx); err != nil {
+			return fmt.Errorf("anytrust das committee reader unhealthy: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close stops the background services of the aggregator, like its keyset refreshers.
+func (a *AnytrustDACommittee) Close() error {
+	if a.LifecycleManager != nil {
+		a.StopAndWaitUntil(committeeStopTimeout)
+	}
+	return nil
+}
+
 type AnytrustDA struct {
-	writer das.DataAvailabilityServiceWriter //*das.DASRPCClient
-	reader *das.RestfulDasClient
+	writer    das.DataAvailabilityServiceWriter //*das.DASRPCClient
+	rpcClient *das.DASRPCClient
+	reader    *das.RestfulDasClient
 }
 
 func NewAnytrustDA(config *AnytrustConfig) (IAnytrustDA, error) {
@@ -103,18 +144,39 @@ func NewAnytrustDA(config *AnytrustConfig) (IAnytrustDA, error) {
 		return nil, err
 	}
 	return &AnytrustDA{
-		writer: dasClient,
-		reader: reader,
+		writer:    dasClient,
+		rpcClient: rpcClient,
+		reader:    reader,
 	}, nil
 }
 
-func (a *AnytrustDA) WriteDA(ctx context.Context, data []byte, retentionTime uint64) (*arbstate.DataAvailabilityCertificate, error) {
+func (a *AnytrustDA) WriteDA(ctx context.Context, data []byte, retentionTime uint64) (cert *arbstate.DataAvailabilityCertificate, err error) {
+	ctx, span := tracer.Start(ctx, "anytrust.Store", trace.WithAttributes(attribute.Int("data.size", len(data))))
+	defer func() { tracing.EndSpan(span, err) }()
 	return a.writer.Store(ctx, data, retentionTime, nil)
 }
 
-func (a *AnytrustDA) ReadDA(ctx context.Context, hashHex string) ([]byte, error) {
+func (a *AnytrustDA) ReadDA(ctx context.Context, hashHex string) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "anytrust.GetByHash")
+	defer func() { tracing.EndSpan(span, err) }()
 	if strings.HasPrefix(hashHex, "0x") {
 		hashHex = hashHex[2:]
 	}
 	return a.reader.GetByHash(ctx, common.HexToHash(hashHex))
 }
+
+func (a *AnytrustDA) HealthCheck(ctx context.Context) error {
+	if err := a.rpcClient.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("anytrust das rpc unhealthy: %w", err)
+	}
+	if err := a.reader.HealthCheck(ctx); err != nil {
+		return fmt.Errorf("anytrust das restful unhealthy: %w", err)
+	}
+	return nil
+}
+
+// Close is a no-op, neither the das rpc client nor the restful client hold resources
+// that can be released.
+func (a *AnytrustDA) Close() error {
+	return nil
+}
@@ -0,0 +1,499 @@
+package celestia
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+
+	openrpc "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/go-square/merkle"
+	"github.com/celestiaorg/go-square/shares"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/filecoin-project/go-jsonrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/eniac-x-labs/rollup-node/client"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+const defaultBlobstreamLogRange = 5000
+
+var (
+	ErrBlobstreamDisabled = errors.New("blobstream verification is not configured, set [celestia.blobstream] l1_rpc and contract")
+	// ErrNotAttested is returned until the Blobstream contract relays a data commitment covering the height.
+	ErrNotAttested = errors.New("celestia height not attested by blobstream yet")
+)
+
+// blobstreamABI is the part of the Blobstream contract the verifier reads, see
+// https://github.com/succinctlabs/sp1-blobstream
+const blobstreamABI = `[
+	{"type":"function","name":"latestBlock","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint64"}]},
+	{"type":"function","name":"state_dataCommitments","stateMutability":"view","inputs":[{"name":"","type":"uint256"}],"outputs":[{"name":"","type":"bytes32"}]},
+	{"type":"event","name":"DataCommitmentStored","anonymous":false,"inputs":[
+		{"name":"proofNonce","type":"uint256","indexed":false},
+		{"name":"startBlock","type":"uint64","indexed":true},
+		{"name":"endBlock","type":"uint64","indexed":true},
+		{"name":"dataCommitment","type":"bytes32","indexed":true}
+	]}
+]`
+
+// daVerifierABI is the entry point of our on-chain verifier, it takes the SharesProof of the
+// blobstream-contracts DAVerifier library.
+const daVerifierABI = `[
+	{"type":"function","name":"verifySharesToDataRootTupleRoot","stateMutability":"view","inputs":[
+		{"name":"_bridge","type":"address"},
+		{"name":"_sharesProof","type":"tuple","components":[
+			{"name":"data","type":"bytes[]"},
+			{"name":"shareProofs","type":"tuple[]","components":[
+				{"name":"beginKey","type":"uint256"},
+				{"name":"endKey","type":"uint256"},
+				{"name":"sideNodes","type":"tuple[]","components":[
+					{"name":"min","type":"tuple","components":[{"name":"version","type":"bytes1"},{"name":"id","type":"bytes28"}]},
+					{"name":"max","type":"tuple","components":[{"name":"version","type":"bytes1"},{"name":"id","type":"bytes28"}]},
+					{"name":"digest","type":"bytes32"}
+				]}
+			]},
+			{"name":"namespace","type":"tuple","components":[{"name":"version","type":"bytes1"},{"name":"id","type":"bytes28"}]},
+			{"name":"rowRoots","type":"tuple[]","components":[
+				{"name":"min","type":"tuple","components":[{"name":"version","type":"bytes1"},{"name":"id","type":"bytes28"}]},
+				{"name":"max","type":"tuple","components":[{"name":"version","type":"bytes1"},{"name":"id","type":"bytes28"}]},
+				{"name":"digest","type":"bytes32"}
+			]},
+			{"name":"rowProofs","type":"tuple[]","components":[
+				{"name":"sideNodes","type":"bytes32[]"},
+				{"name":"key","type":"uint256"},
+				{"name":"numLeaves","type":"uint256"}
+			]},
+			{"name":"attestationProof","type":"tuple","components":[
+				{"name":"tupleRootNonce","type":"uint256"},
+				{"name":"tuple","type":"tuple","components":[{"name":"height","type":"uint256"},{"name":"dataRoot","type":"bytes32"}]},
+				{"name":"proof","type":"tuple","components":[
+					{"name":"sideNodes","type":"bytes32[]"},
+					{"name":"key","type":"uint256"},
+					{"name":"numLeaves","type":"uint256"}
+				]}
+			]}
+		]}
+	],"outputs":[{"name":"","type":"bool"},{"name":"","type":"uint8"}]}
+]`
+
+var (
+	blobstreamContract = mustParseABI(blobstreamABI)
+	daVerifierContract = mustParseABI(daVerifierABI)
+)
+
+func mustParseABI(s string) abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// BlobstreamConfig locates the Blobstream contract relaying celestia data commitments to L1.
+type BlobstreamConfig struct {
+	// L1Rpc of the chain the contract is deployed on, empty disables verification
+	L1Rpc    string `mapstructure:"l1_rpc"`
+	Contract string `mapstructure:"contract"`
+	// StartBlock is the L1 block the contract was deployed at, data commitments are not searched before it
+	StartBlock uint64 `mapstructure:"start_block"`
+	// LogRange is the number of L1 blocks searched per log query
+	LogRange uint64 `mapstructure:"log_range"`
+}
+
+func (c BlobstreamConfig) Enabled() bool {
+	return len(c.L1Rpc) != 0
+}
+
+func (c BlobstreamConfig) Check() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if !common.IsHexAddress(c.Contract) {
+		return fmt.Errorf("invalid blobstream contract address %q", c.Contract)
+	}
+	return nil
+}
+
+// Namespace, NamespaceNode, NamespaceMerkleMultiproof, BinaryMerkleProof, DataRootTuple,
+// AttestationProof and SharesProof mirror the structs of blobstream-contracts.
+type Namespace struct {
+	Version [1]byte
+	Id      [28]byte
+}
+
+type NamespaceNode struct {
+	Min    Namespace
+	Max    Namespace
+	Digest [32]byte
+}
+
+type NamespaceMerkleMultiproof struct {
+	BeginKey  *big.Int
+	EndKey    *big.Int
+	SideNodes []NamespaceNode
+}
+
+type BinaryMerkleProof struct {
+	SideNodes [][32]byte
+	Key       *big.Int
+	NumLeaves *big.Int
+}
+
+type DataRootTuple struct {
+	Height   *big.Int
+	DataRoot [32]byte
+}
+
+type AttestationProof struct {
+	TupleRootNonce *big.Int
+	Tuple          DataRootTuple
+	Proof          BinaryMerkleProof
+}
+
+type SharesProof struct {
+	Data             [][]byte
+	ShareProofs      []NamespaceMerkleMultiproof
+	Namespace        Namespace
+	RowRoots         []NamespaceNode
+	RowProofs        []BinaryMerkleProof
+	AttestationProof AttestationProof
+}
+
+// BlobstreamProof proves the blob of a receipt is part of a data commitment relayed to L1.
+type BlobstreamProof struct {
+	Receipt *Receipt
+	// Nonce of the data commitment covering the receipt height, in [StartBlock, EndBlock)
+	Nonce          uint64
+	StartBlock     uint64
+	EndBlock       uint64
+	DataRoot       common.Hash
+	DataCommitment common.Hash
+	SharesProof    SharesProof
+	// Calldata of verifySharesToDataRootTupleRoot(address,SharesProof) with the contract as bridge
+	Calldata []byte
+}
+
+// blobstreamNode is the part of the celestia node the verifier fetches proofs from.
+type blobstreamNode interface {
+	GetBlob(ctx context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Blob, error)
+	GetBlobProof(ctx context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Proof, error)
+	GetHeader(ctx context.Context, height uint64) (*header.ExtendedHeader, error)
+	// GetDataRootTupleInclusionProof proves the data root of height is part of the data commitment of [start, end)
+	GetDataRootTupleInclusionProof(ctx context.Context, height, start, end uint64) (*merkle.Proof, error)
+}
+
+// blobstreamAPI is the blobstream module of celestia-node, which this version of the openrpc client lacks.
+type blobstreamAPI struct {
+	GetDataRootTupleInclusionProof func(ctx context.Context, height, start, end uint64) (*merkle.Proof, error) `perm:"read"`
+}
+
+type nodeProofs struct {
+	client     *openrpc.Client
+	blobstream blobstreamAPI
+	closer     jsonrpc.ClientCloser
+}
+
+func dialNodeProofs(ctx context.Context, c *openrpc.Client, addr, token string) (*nodeProofs, error) {
+	var authHeader http.Header
+	if token != "" {
+		authHeader = http.Header{openrpc.AuthKey: []string{fmt.Sprintf("Bearer %s", token)}}
+	}
+	n := &nodeProofs{client: c}
+	closer, err := jsonrpc.NewClient(ctx, addr, "blobstream", &n.blobstream, authHeader)
+	if err != nil {
+		return nil, err
+	}
+	n.closer = closer
+	return n, nil
+}
+
+func (n *nodeProofs) GetBlob(ctx context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Blob, error) {
+	return n.client.Blob.Get(ctx, height, namespace, commitment)
+}
+
+func (n *nodeProofs) GetBlobProof(ctx context.Context, height uint64, namespace share.Namespace, commitment blob.Commitment) (*blob.Proof, error) {
+	return n.client.Blob.GetProof(ctx, height, namespace, commitment)
+}
+
+func (n *nodeProofs) GetHeader(ctx context.Context, height uint64) (*header.ExtendedHeader, error) {
+	return n.client.Header.GetByHeight(ctx, height)
+}
+
+func (n *nodeProofs) GetDataRootTupleInclusionProof(ctx context.Context, height, start, end uint64) (*merkle.Proof, error) {
+	return n.blobstream.GetDataRootTupleInclusionProof(ctx, height, start, end)
+}
+
+// BlobstreamVerifier checks celestia blobs against the data commitments of a Blobstream contract.
+type BlobstreamVerifier struct {
+	l1         client.EthClient
+	node       blobstreamNode
+	contract   common.Address
+	startBlock uint64
+	logRange   uint64
+}
+
+func newBlobstreamVerifier(l1 client.EthClient, node blobstreamNode, conf BlobstreamConfig) *BlobstreamVerifier {
+	logRange := conf.LogRange
+	if logRange == 0 {
+		logRange = defaultBlobstreamLogRange
+	}
+	return &BlobstreamVerifier{
+		l1:         l1,
+		node:       node,
+		contract:   common.HexToAddress(conf.Contract),
+		startBlock: conf.StartBlock,
+		logRange:   logRange,
+	}
+}
+
+// Verify fetches the share proofs of the blob of receipt and the inclusion proof of its data root
+// in the data commitment relayed to L1, checks both and encodes them for the on-chain verifier.
+func (v *BlobstreamVerifier) Verify(ctx context.Context, receipt *Receipt) (_ *BlobstreamProof, err error) {
+	ctx, span := tracer.Start(ctx, "celestia.BlobstreamVerify", trace.WithAttributes(
+		attribute.Int64("celestia.height", int64(receipt.Height)),
+		attribute.String("celestia.namespace", receipt.Namespace.String()),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if len(receipt.Commitment) == 0 {
+		return nil, ErrNoCommitment
+	}
+	nonce, start, end, commitment, err := v.dataCommitment(ctx, receipt.Height)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int64("blobstream.nonce", int64(nonce)))
+
+	header, err := v.node.GetHeader(ctx, receipt.Height)
+	if err != nil {
+		return nil, fmt.Errorf("get celestia header: %w", err)
+	}
+	if header.DAH == nil || !bytes.Equal(header.DAH.Hash(), header.DataHash) {
+		return nil, errors.New("celestia header data root doesn't match its data availability header")
+	}
+	dataRoot := common.BytesToHash(header.DataHash)
+
+	tupleProof, err := v.node.GetDataRootTupleInclusionProof(ctx, receipt.Height, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("get data root tuple inclusion proof: %w", err)
+	}
+	if tupleProof.Index != int64(receipt.Height-start) || tupleProof.Total != int64(end-start) {
+		return nil, fmt.Errorf("data root tuple proof is for leaf %d of %d, want %d of %d", tupleProof.Index, tupleProof.Total, receipt.Height-start, end-start)
+	}
+	if err := tupleProof.Verify(commitment[:], encodeDataRootTuple(receipt.Height, dataRoot)); err != nil {
+		return nil, fmt.Errorf("data root tuple not in the data commitment of nonce %d: %w", nonce, err)
+	}
+
+	sharesProof, err := v.sharesProof(ctx, receipt, header.DAH)
+	if err != nil {
+		return nil, err
+	}
+	sharesProof.AttestationProof = AttestationProof{
+		TupleRootNonce: new(big.Int).SetUint64(nonce),
+		Tuple:          DataRootTuple{Height: new(big.Int).SetUint64(receipt.Height), DataRoot: dataRoot},
+		Proof:          toBinaryMerkleProof(tupleProof),
+	}
+	calldata, err := daVerifierContract.Pack("verifySharesToDataRootTupleRoot", v.contract, sharesProof)
+	if err != nil {
+		return nil, fmt.Errorf("encode verifier calldata: %w", err)
+	}
+
+	return &BlobstreamProof{
+		Receipt:        receipt,
+		Nonce:          nonce,
+		StartBlock:     start,
+		EndBlock:       end,
+		DataRoot:       dataRoot,
+		DataCommitment: commitment,
+		SharesProof:    *sharesProof,
+		Calldata:       calldata,
+	}, nil
+}
+
+// sharesProof proves the shares of the blob against the row roots, and the row roots against the
+// data root of dah.
+func (v *BlobstreamVerifier) sharesProof(ctx context.Context, receipt *Receipt, dah *header.DataAvailabilityHeader) (*SharesProof, error) {
+	b, err := v.node.GetBlob(ctx, receipt.Height, receipt.Namespace, receipt.Commitment)
+	if err != nil {
+		return nil, fmt.Errorf("get celestia blob: %w", err)
+	}
+	index, err := blobIndex(b)
+	if err != nil {
+		return nil, err
+	}
+	blobProof, err := v.node.GetBlobProof(ctx, receipt.Height, receipt.Namespace, receipt.Commitment)
+	if err != nil {
+		return nil, fmt.Errorf("get celestia blob proof: %w", err)
+	}
+	blobShares, err := shares.SplitBlobs(&b.Blob)
+	if err != nil {
+		return nil, err
+	}
+	data := shares.ToBytes(blobShares)
+
+	width := len(dah.RowRoots)
+	firstRow := index / width
+	if width == 0 || firstRow+len(*blobProof) > width {
+		return nil, fmt.Errorf("blob index %d and %d row proofs out of a square of width %d", index, len(*blobProof), width)
+	}
+	_, rowProofs := merkle.ProofsFromByteSlices(append(append([][]byte{}, dah.RowRoots...), dah.ColumnRoots...))
+
+	res := &SharesProof{Data: data, Namespace: toNamespace(receipt.Namespace)}
+	cursor := 0
+	for i, proof := range *blobProof {
+		row := firstRow + i
+		n := proof.End() - proof.Start()
+		if cursor+n > len(data) {
+			return nil, fmt.Errorf("blob proofs cover more than the %d shares of the blob", len(data))
+		}
+		if !proof.VerifyInclusion(sha256.New(), receipt.Namespace.ToNMT(), data[cursor:cursor+n], dah.RowRoots[row]) {
+			return nil, fmt.Errorf("shares %d to %d not included in row %d: %w", cursor, cursor+n, row, blob.ErrInvalidProof)
+		}
+		cursor += n
+
+		sideNodes := make([]NamespaceNode, len(proof.Nodes()))
+		for j, node := range proof.Nodes() {
+			sideNodes[j] = toNamespaceNode(node)
+		}
+		res.ShareProofs = append(res.ShareProofs, NamespaceMerkleMultiproof{
+			BeginKey:  big.NewInt(int64(proof.Start())),
+			EndKey:    big.NewInt(int64(proof.End())),
+			SideNodes: sideNodes,
+		})
+		res.RowRoots = append(res.RowRoots, toNamespaceNode(dah.RowRoots[row]))
+		res.RowProofs = append(res.RowProofs, toBinaryMerkleProof(rowProofs[row]))
+	}
+	if cursor != len(data) {
+		return nil, fmt.Errorf("blob proofs cover %d of the %d shares of the blob", cursor, len(data))
+	}
+	return res, nil
+}
+
+// dataCommitment finds the data commitment covering height, checked against the contract state.
+func (v *BlobstreamVerifier) dataCommitment(ctx context.Context, height uint64) (nonce, start, end uint64, commitment common.Hash, err error) {
+	var latest uint64
+	if err = v.call(ctx, &latest, "latestBlock"); err != nil {
+		return
+	}
+	if height >= latest {
+		err = fmt.Errorf("%w, height: %d, latest relayed: %d", ErrNotAttested, height, latest)
+		return
+	}
+	if nonce, start, end, err = v.findDataCommitment(ctx, height); err != nil {
+		return
+	}
+
+	// the log may be from a reorged block, the contract state is authoritative
+	if err = v.call(ctx, &commitment, "state_dataCommitments", new(big.Int).SetUint64(nonce)); err != nil {
+		return
+	}
+	if commitment == (common.Hash{}) {
+		err = fmt.Errorf("%w, data commitment %d not stored", ErrNotAttested, nonce)
+	}
+	return
+}
+
+// findDataCommitment searches the DataCommitmentStored logs backwards from the L1 head, the
+// commitment covering a recent height is among the last ones.
+func (v *BlobstreamVerifier) findDataCommitment(ctx context.Context, height uint64) (nonce, start, end uint64, err error) {
+	head, err := v.l1.BlockNumber(ctx)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	event := blobstreamContract.Events["DataCommitmentStored"]
+	for to := head; to >= v.startBlock; {
+		from := v.startBlock
+		if to-v.startBlock >= v.logRange {
+			from = to - v.logRange + 1
+		}
+		logs, err := v.l1.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(from),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{v.contract},
+			Topics:    [][]common.Hash{{event.ID}},
+		})
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("get blobstream logs: %w", err)
+		}
+		for i := len(logs) - 1; i >= 0; i-- {
+			l := logs[i]
+			if len(l.Topics) != 4 || len(l.Data) != 32 {
+				continue
+			}
+			start, end = l.Topics[1].Big().Uint64(), l.Topics[2].Big().Uint64()
+			if start <= height && height < end {
+				return new(big.Int).SetBytes(l.Data).Uint64(), start, end, nil
+			}
+		}
+		if from == v.startBlock {
+			break
+		}
+		to = from - 1
+	}
+	return 0, 0, 0, fmt.Errorf("%w, no data commitment covers height %d", ErrNotAttested, height)
+}
+
+func (v *BlobstreamVerifier) call(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	input, err := blobstreamContract.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	output, err := v.l1.CallContract(ctx, ethereum.CallMsg{To: &v.contract, Data: input}, nil)
+	if err != nil {
+		return fmt.Errorf("call blobstream %s: %w", method, err)
+	}
+	values, err := blobstreamContract.Unpack(method, output)
+	if err != nil {
+		return fmt.Errorf("decode blobstream %s: %w", method, err)
+	}
+	switch res := result.(type) {
+	case *uint64:
+		*res = values[0].(uint64)
+	case *common.Hash:
+		*res = values[0].([32]byte)
+	}
+	return nil
+}
+
+// encodeDataRootTuple is abi.encode(DataRootTuple), the leaf of a data commitment.
+func encodeDataRootTuple(height uint64, dataRoot common.Hash) []byte {
+	return append(common.LeftPadBytes(new(big.Int).SetUint64(height).Bytes(), 32), dataRoot.Bytes()...)
+}
+
+func toBinaryMerkleProof(proof *merkle.Proof) BinaryMerkleProof {
+	sideNodes := make([][32]byte, len(proof.Aunts))
+	for i, aunt := range proof.Aunts {
+		sideNodes[i] = common.BytesToHash(aunt)
+	}
+	return BinaryMerkleProof{SideNodes: sideNodes, Key: big.NewInt(proof.Index), NumLeaves: big.NewInt(proof.Total)}
+}
+
+func toNamespace(ns []byte) Namespace {
+	var res Namespace
+	res.Version[0] = ns[0]
+	copy(res.Id[:], ns[1:appconsts.NamespaceSize])
+	return res
+}
+
+// toNamespaceNode splits an nmt node, min namespace || max namespace || digest.
+func toNamespaceNode(node []byte) NamespaceNode {
+	res := NamespaceNode{
+		Min: toNamespace(node[:appconsts.NamespaceSize]),
+		Max: toNamespace(node[appconsts.NamespaceSize : 2*appconsts.NamespaceSize]),
+	}
+	copy(res.Digest[:], node[2*appconsts.NamespaceSize:])
+	return res
+}
@@ -0,0 +1,264 @@
+package celestia
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/header"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/go-square/merkle"
+	"github.com/celestiaorg/go-square/shares"
+	"github.com/celestiaorg/nmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/eniac-x-labs/rollup-node/client"
+)
+
+// fakeBlobstreamL1 is an L1 with a Blobstream contract at contract, relaying every data commitment
+// in its own block. It serves the calls and logs the verifier reads.
+type fakeBlobstreamL1 struct {
+	client.EthClient
+	contract    common.Address
+	head        uint64
+	latestBlock uint64
+	commitments map[uint64]common.Hash
+	logs        []types.Log
+}
+
+func newFakeBlobstreamL1() *fakeBlobstreamL1 {
+	return &fakeBlobstreamL1{
+		contract:    common.HexToAddress("0xb10b"),
+		head:        1,
+		commitments: make(map[uint64]common.Hash),
+	}
+}
+
+// submit relays the data commitment of [start, end) in its own L1 block.
+func (l *fakeBlobstreamL1) submit(nonce, start, end uint64, commitment common.Hash) {
+	l.head++
+	l.latestBlock = end
+	l.commitments[nonce] = commitment
+	word := func(v uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(v)) }
+	l.logs = append(l.logs, types.Log{
+		Address:     l.contract,
+		Topics:      []common.Hash{blobstreamContract.Events["DataCommitmentStored"].ID, word(start), word(end), commitment},
+		Data:        word(nonce).Bytes(),
+		BlockNumber: l.head,
+	})
+}
+
+func (l *fakeBlobstreamL1) BlockNumber(context.Context) (uint64, error) {
+	return l.head, nil
+}
+
+func (l *fakeBlobstreamL1) FilterLogs(_ context.Context, q ethereum.FilterQuery) ([]types.Log, error) {
+	var logs []types.Log
+	for _, log := range l.logs {
+		if log.BlockNumber < q.FromBlock.Uint64() || log.BlockNumber > q.ToBlock.Uint64() {
+			continue
+		}
+		if len(q.Addresses) != 0 && !slices.Contains(q.Addresses, log.Address) {
+			continue
+		}
+		if len(q.Topics) != 0 && len(q.Topics[0]) != 0 && !slices.Contains(q.Topics[0], log.Topics[0]) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+func (l *fakeBlobstreamL1) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if msg.To == nil || *msg.To != l.contract {
+		return nil, nil
+	}
+	method, err := blobstreamContract.MethodById(msg.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch method.Name {
+	case "latestBlock":
+		return method.Outputs.Pack(l.latestBlock)
+	case "state_dataCommitments":
+		args, err := method.Inputs.Unpack(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		return method.Outputs.Pack([32]byte(l.commitments[args[0].(*big.Int).Uint64()]))
+	}
+	return nil, fmt.Errorf("unexpected call of %s", method.Name)
+}
+
+type fakeBlobstreamNode struct {
+	blob       *blob.Blob
+	proof      *blob.Proof
+	header     *header.ExtendedHeader
+	tupleProof *merkle.Proof
+}
+
+func (n *fakeBlobstreamNode) GetBlob(context.Context, uint64, share.Namespace, blob.Commitment) (*blob.Blob, error) {
+	return n.blob, nil
+}
+
+func (n *fakeBlobstreamNode) GetBlobProof(context.Context, uint64, share.Namespace, blob.Commitment) (*blob.Proof, error) {
+	return n.proof, nil
+}
+
+func (n *fakeBlobstreamNode) GetHeader(context.Context, uint64) (*header.ExtendedHeader, error) {
+	return n.header, nil
+}
+
+func (n *fakeBlobstreamNode) GetDataRootTupleInclusionProof(context.Context, uint64, uint64, uint64) (*merkle.Proof, error) {
+	return n.tupleProof, nil
+}
+
+// fillerShare is a share of another namespace, or parity, in the square.
+func fillerShare(ns []byte, fill byte) []byte {
+	return append(append([]byte{}, ns...), bytes.Repeat([]byte{fill}, appconsts.ShareSize-len(ns))...)
+}
+
+// withIndex sets the index of a retrieved blob, which is only settable through json.
+func withIndex(t *testing.T, b *blob.Blob, index int) *blob.Blob {
+	encoded, err := json.Marshal(b)
+	require.NoError(t, err)
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	fields["index"] = index
+	encoded, _ = json.Marshal(fields)
+	retrieved := new(blob.Blob)
+	require.NoError(t, json.Unmarshal(encoded, retrieved))
+	return retrieved
+}
+
+// newFakeBlobstreamNode lays the blob out from the 3rd share of the 2nd row of a 4x4 square, so
+// it spans two rows, and returns the node serving it at height together with the data root.
+func newFakeBlobstreamNode(t *testing.T, height uint64, b *blob.Blob) (*fakeBlobstreamNode, []byte) {
+	blobShares, err := shares.SplitBlobs(&b.Blob)
+	require.NoError(t, err)
+	data := shares.ToBytes(blobShares)
+	require.Len(t, data, 4)
+
+	const odsWidth, edsWidth = 4, 8
+	before, _ := share.NewBlobNamespaceV0([]byte{0x01})
+	after, _ := share.NewBlobNamespaceV0(bytes.Repeat([]byte{0xee}, 10))
+	parity := bytes.Repeat([]byte{0xff}, appconsts.NamespaceSize)
+
+	var rowRoots, colRoots [][]byte
+	var proof blob.Proof
+	next := 0
+	for row := 0; row < edsWidth; row++ {
+		tree := nmt.New(sha256.New(), nmt.NamespaceIDSize(appconsts.NamespaceSize), nmt.IgnoreMaxNamespace(true))
+		start, end := -1, -1
+		for col := 0; col < edsWidth; col++ {
+			index := row*edsWidth + col
+			var leaf []byte
+			switch {
+			case row >= odsWidth || col >= odsWidth:
+				leaf = fillerShare(parity, byte(col))
+			case index >= 10 && next < len(data):
+				leaf = data[next]
+				next++
+				if start < 0 {
+					start = col
+				}
+				end = col + 1
+			case index < 10:
+				leaf = fillerShare(before, byte(col))
+			default:
+				leaf = fillerShare(after, byte(col))
+			}
+			require.NoError(t, tree.Push(append(append([]byte{}, leaf[:appconsts.NamespaceSize]...), leaf...)))
+		}
+		root, err := tree.Root()
+		require.NoError(t, err)
+		rowRoots = append(rowRoots, root)
+		// column roots only matter for the data root
+		colRoots = append(colRoots, bytes.Join([][]byte{parity, parity, bytes.Repeat([]byte{byte(row)}, 32)}, nil))
+		if start >= 0 {
+			rowProof, err := tree.ProveRange(start, end)
+			require.NoError(t, err)
+			proof = append(proof, &rowProof)
+		}
+	}
+	dah := &header.DataAvailabilityHeader{RowRoots: rowRoots, ColumnRoots: colRoots}
+
+	return &fakeBlobstreamNode{
+		blob:   withIndex(t, b, 10),
+		proof:  &proof,
+		header: &header.ExtendedHeader{RawHeader: header.RawHeader{Height: int64(height), DataHash: dah.Hash()}, DAH: dah},
+	}, dah.Hash()
+}
+
+func TestBlobstreamVerify(t *testing.T) {
+	namespace, _ := share.NewBlobNamespaceV0([]byte("DappLink"))
+	b, err := blob.NewBlobV0(namespace, bytes.Repeat([]byte("DappLink"), 200))
+	require.NoError(t, err)
+	receipt := &Receipt{Height: 105, Namespace: namespace, Commitment: b.Commitment}
+
+	node, dataRoot := newFakeBlobstreamNode(t, receipt.Height, b)
+	// the data commitment of [100, 116)
+	var tuples [][]byte
+	for height := uint64(100); height < 116; height++ {
+		root := common.BytesToHash(crypto.Keccak256(big.NewInt(int64(height)).Bytes()))
+		if height == receipt.Height {
+			root = common.BytesToHash(dataRoot)
+		}
+		tuples = append(tuples, encodeDataRootTuple(height, root))
+	}
+	commitment, tupleProofs := merkle.ProofsFromByteSlices(tuples)
+	node.tupleProof = tupleProofs[receipt.Height-100]
+
+	l1 := newFakeBlobstreamL1()
+	l1.submit(2, 84, 100, common.HexToHash("0x02"))
+	l1.submit(3, 100, 116, common.BytesToHash(commitment))
+	l1.submit(4, 116, 120, common.HexToHash("0x04"))
+
+	// a log range of one block searches the commitments one by one
+	verifier := newBlobstreamVerifier(l1, node, BlobstreamConfig{Contract: l1.contract.Hex(), LogRange: 1})
+	proof, err := verifier.Verify(context.Background(), receipt)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), proof.Nonce)
+	require.Equal(t, uint64(100), proof.StartBlock)
+	require.Equal(t, uint64(116), proof.EndBlock)
+	require.Equal(t, common.BytesToHash(commitment), proof.DataCommitment)
+	require.Len(t, proof.SharesProof.Data, 4)
+	require.Len(t, proof.SharesProof.RowProofs, 2)
+	require.Equal(t, big.NewInt(5), proof.SharesProof.AttestationProof.Proof.Key)
+
+	// the calldata decodes back to the proof
+	method := daVerifierContract.Methods["verifySharesToDataRootTupleRoot"]
+	require.Equal(t, method.ID, proof.Calldata[:4])
+	args, err := method.Inputs.Unpack(proof.Calldata[4:])
+	require.NoError(t, err)
+	require.Equal(t, l1.contract, args[0])
+
+	// heights past the latest relayed block aren't attested yet
+	_, err = verifier.Verify(context.Background(), &Receipt{Height: 120, Namespace: namespace, Commitment: b.Commitment})
+	require.True(t, errors.Is(err, ErrNotAttested))
+
+	// a data root that isn't in the relayed commitment is rejected
+	node.tupleProof = tupleProofs[0]
+	_, err = verifier.Verify(context.Background(), receipt)
+	require.Error(t, err)
+	node.tupleProof = tupleProofs[receipt.Height-100]
+
+	// so are shares that aren't in the square
+	tampered, err := blob.NewBlobV0(namespace, bytes.Repeat([]byte("tampered"), 200))
+	require.NoError(t, err)
+	node.blob = withIndex(t, tampered, 10)
+	_, err = verifier.Verify(context.Background(), receipt)
+	require.ErrorIs(t, err, blob.ErrInvalidProof)
+}
@@ -84,6 +84,10 @@ type CLIConfig struct {
 	DaRpc     string
 	AuthToken string
 	Namespace string
+	// Tenants maps tenant names to their namespace
+	Tenants    map[string]string
+	Fee        FeeConfig
+	Blobstream BlobstreamConfig
 }
 
 func (c CLIConfig) Check() error {
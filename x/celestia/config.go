@@ -1,17 +1,22 @@
 package celestia
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/log"
 )
 
-// corresponding celestia.toml
+// celestia client settings, filled from the [celestia] section of the config file
 type ParseCelestiaConfig struct {
-	L1ChainID           string `toml:"l1ChainID"`
-	PrivateKey          string `toml:"privateKey"`
-	DaRpc               string `toml:"daRpc"`
-	AuthToken           string `toml:"authToken"`
-	Namespace           string `toml:"namespace"`
-	EthFallbackDisabled bool   `toml:"ethFallbackDisabled"`
+	L1ChainID           string            `toml:"l1ChainID"`
+	PrivateKey          string            `toml:"privateKey"`
+	DaRpc               string            `toml:"daRpc"`
+	AuthToken           string            `toml:"authToken"`
+	Namespace           string            `toml:"namespace"`
+	Tenants             map[string]string `toml:"tenants"`
+	Fee                 FeeConfig         `toml:"fee"`
+	Blobstream          BlobstreamConfig  `toml:"blobstream"`
+	EthFallbackDisabled bool              `toml:"ethFallbackDisabled"`
 
 	// data source config
 	BatchInboxAddress string `toml:"batchInboxAddress"`
@@ -30,12 +35,24 @@ type CelestiaConfig struct {
 }
 
 func ProcessCelestiaConfig(parseConf *ParseCelestiaConfig, logger log.Logger) (*CelestiaConfig, error) {
+	if _, err := NewNamespaces(parseConf.Namespace, parseConf.Tenants); err != nil {
+		return nil, err
+	}
+	if err := parseConf.Fee.orDefault().Check(); err != nil {
+		return nil, fmt.Errorf("invalid fee config: %w", err)
+	}
+	if err := parseConf.Blobstream.Check(); err != nil {
+		return nil, err
+	}
 
 	return &CelestiaConfig{
 		celestiaConfig: CLIConfig{
-			DaRpc:     parseConf.DaRpc,
-			AuthToken: parseConf.AuthToken,
-			Namespace: parseConf.Namespace,
+			DaRpc:      parseConf.DaRpc,
+			AuthToken:  parseConf.AuthToken,
+			Namespace:  parseConf.Namespace,
+			Tenants:    parseConf.Tenants,
+			Fee:        parseConf.Fee,
+			Blobstream: parseConf.Blobstream,
 		},
 		logger: logger,
 	}, nil
@@ -0,0 +1,340 @@
+package celestia
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkmath "cosmossdk.io/math"
+	openrpc "github.com/celestiaorg/celestia-openrpc"
+	"github.com/celestiaorg/celestia-openrpc/types/appconsts"
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/filecoin-project/go-jsonrpc"
+)
+
+const (
+	// FeeStrategyFixed always pays GasPrice, bumped on timeouts up to MaxGasPrice.
+	FeeStrategyFixed = "fixed"
+	// FeeStrategyEstimate pays the gas price estimated by the celestia node, between GasPrice and
+	// MaxGasPrice, or the price of the last submission when the node can't estimate it.
+	FeeStrategyEstimate = "estimate"
+
+	// pay for blob gas, see celestia-app x/blob/types.EstimateGas
+	pfbGasFixedCost    = 75000
+	bytesPerBlobInfo   = 70
+	txSizeCostPerByte  = 10
+	estimateGasPerByte = appconsts.DefaultGasPerBlobByte
+
+	// txPriorityMedium is the priority gas prices are estimated for, see celestia-node state.TxPriority
+	txPriorityMedium = 2
+)
+
+// FeeConfig sets how much is paid for a blob and how stalled submissions are retried.
+type FeeConfig struct {
+	Strategy string `mapstructure:"strategy"`
+	// GasPrice is in utia per gas
+	GasPrice    float64 `mapstructure:"gas_price"`
+	MaxGasPrice float64 `mapstructure:"max_gas_price"`
+	// GasLimit of a submission, 0 estimates it from the blob size
+	GasLimit uint64 `mapstructure:"gas_limit"`
+	// SubmitTimeout bounds the wait for inclusion, the submission is then sent again with a higher price
+	SubmitTimeout time.Duration `mapstructure:"submit_timeout"`
+	MaxRetries    int           `mapstructure:"max_retries"`
+	// GasPriceBump multiplies the gas price of a timed out submission
+	GasPriceBump float64 `mapstructure:"gas_price_bump"`
+	// KeyName of the node keyring paying for blobs, the node's default key when empty
+	KeyName string `mapstructure:"key_name"`
+	// FeeGranterAddress pays the fees through a fee grant to the submitting key when set
+	FeeGranterAddress string `mapstructure:"fee_granter_address"`
+}
+
+// DefaultFeeConfig starts from the minimum gas price of celestia validators.
+func DefaultFeeConfig() FeeConfig {
+	return FeeConfig{
+		Strategy:      FeeStrategyEstimate,
+		GasPrice:      0.002,
+		MaxGasPrice:   0.2,
+		SubmitTimeout: time.Minute,
+		MaxRetries:    3,
+		GasPriceBump:  1.25,
+	}
+}
+
+// orDefault returns the default config in place of an unset one.
+func (c FeeConfig) orDefault() FeeConfig {
+	if c == (FeeConfig{}) {
+		return DefaultFeeConfig()
+	}
+	return c
+}
+
+func (c FeeConfig) Check() error {
+	switch c.Strategy {
+	case FeeStrategyFixed, FeeStrategyEstimate:
+	default:
+		return fmt.Errorf("unknown fee strategy %q, want %s or %s", c.Strategy, FeeStrategyFixed, FeeStrategyEstimate)
+	}
+	if c.GasPrice <= 0 {
+		return errors.New("gas price must be positive")
+	}
+	if c.MaxGasPrice < c.GasPrice {
+		return errors.New("max gas price must not be lower than gas price")
+	}
+	if c.SubmitTimeout <= 0 {
+		return errors.New("submit timeout must be positive")
+	}
+	if c.MaxRetries < 0 {
+		return errors.New("max retries must not be negative")
+	}
+	if c.GasPriceBump < 1 {
+		return errors.New("gas price bump must be at least 1")
+	}
+	if len(c.FeeGranterAddress) != 0 && !strings.HasPrefix(c.FeeGranterAddress, "celestia1") {
+		return fmt.Errorf("fee granter address %q is not a celestia address", c.FeeGranterAddress)
+	}
+	return nil
+}
+
+// txOptions reports whether submissions need the tx config of the state module, see nodeState.
+func (c FeeConfig) txOptions() bool {
+	return len(c.KeyName) != 0 || len(c.FeeGranterAddress) != 0
+}
+
+// Fee is what a submission paid.
+type Fee struct {
+	// Amount is in utia
+	Amount   uint64
+	GasPrice float64
+	GasLimit uint64
+	GasUsed  int64
+	TxHash   string
+	Attempts int
+}
+
+// EstimateGas returns the gas of a pay for blob transaction of blobs of the given sizes.
+func EstimateGas(blobSizes ...int) uint64 {
+	var shares uint64
+	for _, size := range blobSizes {
+		shares += sparseSharesNeeded(size)
+	}
+	return shares*appconsts.ShareSize*estimateGasPerByte + txSizeCostPerByte*bytesPerBlobInfo*uint64(len(blobSizes)) + pfbGasFixedCost
+}
+
+func sparseSharesNeeded(size int) uint64 {
+	if size == 0 {
+		return 0
+	}
+	if size <= appconsts.FirstSparseShareContentSize {
+		return 1
+	}
+	rest := size - appconsts.FirstSparseShareContentSize
+	return 1 + uint64((rest+appconsts.ContinuationSparseShareContentSize-1)/appconsts.ContinuationSparseShareContentSize)
+}
+
+// payForBlobFunc pays for blobs at price utia per gas.
+type payForBlobFunc func(ctx context.Context, price float64, gasLimit uint64, blobs []*blob.Blob) (*state.TxResponse, error)
+
+// gasPriceFunc returns the gas price the network currently asks for.
+type gasPriceFunc func(ctx context.Context) (float64, error)
+
+// TxConfig is the submit options of the state module of celestia-node.
+type TxConfig struct {
+	GasPrice          float64 `json:"gas_price,omitempty"`
+	IsGasPriceSet     bool    `json:"is_gas_price_set,omitempty"`
+	Gas               uint64  `json:"gas,omitempty"`
+	KeyName           string  `json:"key_name,omitempty"`
+	FeeGranterAddress string  `json:"fee_granter_address,omitempty"`
+}
+
+// stateAPI is the part of the state module of celestia-node taking submit options and estimating
+// gas prices, which this version of the openrpc client lacks.
+type stateAPI struct {
+	SubmitPayForBlob func(ctx context.Context, blobs []*blob.Blob, config *TxConfig) (*state.TxResponse, error) `perm:"write"`
+	EstimateGasPrice func(ctx context.Context, priority int) (float64, error)                                   `perm:"read"`
+}
+
+type nodeState struct {
+	state  stateAPI
+	closer jsonrpc.ClientCloser
+}
+
+func dialNodeState(ctx context.Context, addr, token string) (*nodeState, error) {
+	var authHeader http.Header
+	if token != "" {
+		authHeader = http.Header{openrpc.AuthKey: []string{fmt.Sprintf("Bearer %s", token)}}
+	}
+	n := &nodeState{}
+	closer, err := jsonrpc.NewClient(ctx, addr, "state", &n.state, authHeader)
+	if err != nil {
+		return nil, err
+	}
+	n.closer = closer
+	return n, nil
+}
+
+// payForBlob pays for blobs from the key and through the fee granter of conf.
+func (n *nodeState) payForBlob(conf FeeConfig) payForBlobFunc {
+	return func(ctx context.Context, price float64, gasLimit uint64, blobs []*blob.Blob) (*state.TxResponse, error) {
+		return n.state.SubmitPayForBlob(ctx, blobs, &TxConfig{
+			GasPrice:          price,
+			IsGasPriceSet:     true,
+			Gas:               gasLimit,
+			KeyName:           conf.KeyName,
+			FeeGranterAddress: conf.FeeGranterAddress,
+		})
+	}
+}
+
+func (n *nodeState) gasPrice(ctx context.Context) (float64, error) {
+	return n.state.EstimateGasPrice(ctx, txPriorityMedium)
+}
+
+// legacyPayForBlob pays for blobs through the state module of the openrpc client, from the
+// node's default key.
+func legacyPayForBlob(s *state.API) payForBlobFunc {
+	return func(ctx context.Context, price float64, gasLimit uint64, blobs []*blob.Blob) (*state.TxResponse, error) {
+		return s.SubmitPayForBlob(ctx, sdkmath.NewIntFromUint64(feeAmount(price, gasLimit)), gasLimit, blobs)
+	}
+}
+
+// feeAmount returns the fee in utia of gasLimit gas at price.
+func feeAmount(price float64, gasLimit uint64) uint64 {
+	return uint64(math.Ceil(price * float64(gasLimit)))
+}
+
+// feeStrategy picks the gas price of submissions, it is shared by concurrent submissions.
+type feeStrategy struct {
+	conf FeeConfig
+	// networkPrice is nil for the fixed strategy
+	networkPrice gasPriceFunc
+	logger       log.Logger
+	warned       atomic.Bool
+
+	mu sync.Mutex
+	// price is the price of the last submission, the estimate when the node can't give one
+	price float64
+}
+
+func newFeeStrategy(conf FeeConfig, networkPrice gasPriceFunc, logger log.Logger) *feeStrategy {
+	if conf.Strategy != FeeStrategyEstimate {
+		networkPrice = nil
+	}
+	return &feeStrategy{conf: conf, networkPrice: networkPrice, logger: logger, price: conf.GasPrice}
+}
+
+// gasPrice returns the gas price the next submission starts from.
+func (f *feeStrategy) gasPrice(ctx context.Context) float64 {
+	if f.conf.Strategy == FeeStrategyFixed {
+		return f.conf.GasPrice
+	}
+	if f.networkPrice != nil {
+		price, err := f.networkPrice(ctx)
+		if err == nil {
+			return math.Min(math.Max(price, f.conf.GasPrice), f.conf.MaxGasPrice)
+		}
+		// nodes without the estimator fail every call, warn once
+		if f.warned.CompareAndSwap(false, true) {
+			f.logger.Warn("celestia node can't estimate the gas price, using the last one", "err", err)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.price
+}
+
+// gasLimit returns the gas limit of a submission of blobs of the given sizes.
+func (f *feeStrategy) gasLimit(blobSizes ...int) uint64 {
+	if f.conf.GasLimit != 0 {
+		return f.conf.GasLimit
+	}
+	return EstimateGas(blobSizes...)
+}
+
+// estimate returns the fee in utia the next submission of a blob of size bytes starts from.
+func (f *feeStrategy) estimate(ctx context.Context, size int) (amount uint64, gasLimit uint64, price float64) {
+	gasLimit = f.gasLimit(size)
+	price = f.gasPrice(ctx)
+	return feeAmount(price, gasLimit), gasLimit, price
+}
+
+func (f *feeStrategy) bump(price float64) float64 {
+	next := math.Min(price*f.conf.GasPriceBump, f.conf.MaxGasPrice)
+	f.paid(next)
+	return next
+}
+
+// paid records the price of the last submission.
+func (f *feeStrategy) paid(price float64) {
+	if f.conf.Strategy != FeeStrategyEstimate {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.price = price
+}
+
+// submit pays for the blobs and waits for their inclusion. A submission not included within the
+// submit timeout is sent again with a bumped gas price, the timed out one may still be included
+// which only costs a duplicate blob.
+func (f *feeStrategy) submit(ctx context.Context, payForBlob payForBlobFunc, blobs []*blob.Blob) (uint64, *Fee, error) {
+	sizes := make([]int, len(blobs))
+	for i, b := range blobs {
+		sizes[i] = len(b.Data)
+	}
+	gasLimit := f.gasLimit(sizes...)
+
+	price := f.gasPrice(ctx)
+	for attempt := 1; ; attempt++ {
+		amount := feeAmount(price, gasLimit)
+		attemptCtx, cancel := context.WithTimeout(ctx, f.conf.SubmitTimeout)
+		resp, err := payForBlob(attemptCtx, price, gasLimit, blobs)
+		cancel()
+
+		if err == nil && resp.Code != 0 {
+			err = fmt.Errorf("pay for blob failed, code: %d, log: %s", resp.Code, resp.RawLog)
+		}
+		if err == nil {
+			f.paid(price)
+			return uint64(resp.Height), &Fee{
+				Amount:   amount,
+				GasPrice: price,
+				GasLimit: gasLimit,
+				GasUsed:  resp.GasUsed,
+				TxHash:   resp.TxHash,
+				Attempts: attempt,
+			}, nil
+		}
+
+		// the rpc client doesn't always wrap the context error
+		timedOut := errors.Is(attemptCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		if !timedOut || attempt > f.conf.MaxRetries {
+			return 0, nil, err
+		}
+		if price >= f.conf.MaxGasPrice {
+			return 0, nil, fmt.Errorf("submission not included at the max gas price %v: %w", f.conf.MaxGasPrice, err)
+		}
+		next := f.bump(price)
+		f.logger.Warn("celestia submission timed out, retrying with a higher gas price", "attempt", attempt, "gasPrice", price, "next", next)
+		price = next
+	}
+}
+
+// EstimateCost returns the fee in utia of submitting a blob of size bytes at the gas price of the
+// fee strategy, a submission timing out pays more.
+func (c *CelestiaRollup) EstimateCost(ctx context.Context, size int) (*big.Int, map[string]string, error) {
+	amount, gasLimit, price := c.fee.estimate(ctx, size)
+	return new(big.Int).SetUint64(amount), map[string]string{
+		"gas_limit": strconv.FormatUint(gasLimit, 10),
+		"gas_price": strconv.FormatFloat(price, 'f', -1, 64),
+	}, nil
+}
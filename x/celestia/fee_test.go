@@ -0,0 +1,172 @@
+package celestia
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/celestiaorg/celestia-openrpc/types/state"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+)
+
+// stallingPayForBlob times out the first stalls submissions and records the fee of every one.
+func stallingPayForBlob(stalls int, fees *[]uint64) payForBlobFunc {
+	return func(ctx context.Context, price float64, gasLim uint64, blobs []*blob.Blob) (*state.TxResponse, error) {
+		*fees = append(*fees, feeAmount(price, gasLim))
+		if len(*fees) <= stalls {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return &state.TxResponse{Height: 100, TxHash: "ABCD", GasUsed: int64(gasLim) / 2}, nil
+	}
+}
+
+func testBlobs(t *testing.T) []*blob.Blob {
+	namespace, _ := share.NewBlobNamespaceV0([]byte("DappLink"))
+	b, err := blob.NewBlobV0(namespace, []byte("hello DappLink"))
+	require.NoError(t, err)
+	return []*blob.Blob{b}
+}
+
+func TestFeeStrategyBumpsOnTimeout(t *testing.T) {
+	conf := FeeConfig{
+		Strategy:      FeeStrategyFixed,
+		GasPrice:      0.002,
+		MaxGasPrice:   0.1,
+		GasLimit:      100000,
+		SubmitTimeout: 10 * time.Millisecond,
+		MaxRetries:    3,
+		GasPriceBump:  2,
+	}
+	var fees []uint64
+	height, fee, err := newFeeStrategy(conf, nil, log.Root()).submit(context.Background(), stallingPayForBlob(2, &fees), testBlobs(t))
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), height)
+	require.Equal(t, []uint64{200, 400, 800}, fees)
+	require.Equal(t, &Fee{Amount: 800, GasPrice: 0.008, GasLimit: 100000, GasUsed: 50000, TxHash: "ABCD", Attempts: 3}, fee)
+
+	// gives up after max retries
+	fees = nil
+	_, _, err = newFeeStrategy(conf, nil, log.Root()).submit(context.Background(), stallingPayForBlob(10, &fees), testBlobs(t))
+	require.Error(t, err)
+	require.Len(t, fees, 4)
+
+	// and at the max gas price
+	conf.MaxGasPrice = 0.004
+	fees = nil
+	_, _, err = newFeeStrategy(conf, nil, log.Root()).submit(context.Background(), stallingPayForBlob(10, &fees), testBlobs(t))
+	require.Error(t, err)
+	require.Equal(t, []uint64{200, 400}, fees)
+}
+
+func TestFeeStrategyDoesNotRetryErrors(t *testing.T) {
+	calls := 0
+	payForBlob := func(ctx context.Context, price float64, gasLim uint64, blobs []*blob.Blob) (*state.TxResponse, error) {
+		calls++
+		return &state.TxResponse{Code: 11, RawLog: "out of gas"}, nil
+	}
+	_, _, err := newFeeStrategy(DefaultFeeConfig(), nil, log.Root()).submit(context.Background(), payForBlob, testBlobs(t))
+	require.ErrorContains(t, err, "out of gas")
+	require.Equal(t, 1, calls)
+
+	calls = 0
+	failing := func(ctx context.Context, price float64, gasLim uint64, blobs []*blob.Blob) (*state.TxResponse, error) {
+		calls++
+		return nil, errors.New("insufficient funds")
+	}
+	_, _, err = newFeeStrategy(DefaultFeeConfig(), nil, log.Root()).submit(context.Background(), failing, testBlobs(t))
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+}
+
+func TestFeeStrategyEstimate(t *testing.T) {
+	ctx := context.Background()
+	conf := DefaultFeeConfig()
+	conf.GasPrice = 0.01
+	conf.MaxGasPrice = 0.1
+	conf.SubmitTimeout = 10 * time.Millisecond
+	conf.GasPriceBump = 2
+
+	// the node's estimate, within the floor and the cap
+	networkPrice := 0.03
+	var networkErr error
+	strategy := newFeeStrategy(conf, func(context.Context) (float64, error) { return networkPrice, networkErr }, log.Root())
+	require.Equal(t, 0.03, strategy.gasPrice(ctx))
+	networkPrice = 0.001
+	require.Equal(t, conf.GasPrice, strategy.gasPrice(ctx))
+	networkPrice = 1
+	require.Equal(t, conf.MaxGasPrice, strategy.gasPrice(ctx))
+
+	networkPrice = 0.02
+	var fees []uint64
+	_, fee, err := strategy.submit(ctx, stallingPayForBlob(1, &fees), testBlobs(t))
+	require.NoError(t, err)
+	require.Equal(t, 0.04, fee.GasPrice)
+
+	// the last price when the node can't estimate
+	networkErr = errors.New("method 'state.EstimateGasPrice' not found")
+	require.Equal(t, 0.04, strategy.gasPrice(ctx))
+	require.Equal(t, conf.GasPrice, newFeeStrategy(conf, nil, log.Root()).gasPrice(ctx))
+
+	// the fixed strategy ignores the node
+	conf.Strategy = FeeStrategyFixed
+	strategy = newFeeStrategy(conf, func(context.Context) (float64, error) { return 0.05, nil }, log.Root())
+	require.Equal(t, conf.GasPrice, strategy.gasPrice(ctx))
+}
+
+func TestTxConfig(t *testing.T) {
+	conf := DefaultFeeConfig()
+	require.False(t, conf.txOptions())
+	conf.KeyName = "appchain"
+	conf.FeeGranterAddress = "celestia1granter"
+	require.NoError(t, conf.Check())
+	require.True(t, conf.txOptions())
+
+	var got *TxConfig
+	n := &nodeState{state: stateAPI{
+		SubmitPayForBlob: func(ctx context.Context, blobs []*blob.Blob, config *TxConfig) (*state.TxResponse, error) {
+			got = config
+			return &state.TxResponse{Height: 100}, nil
+		},
+	}}
+	_, _, err := newFeeStrategy(conf, nil, log.Root()).submit(context.Background(), n.payForBlob(conf), testBlobs(t))
+	require.NoError(t, err)
+	require.Equal(t, &TxConfig{
+		GasPrice:          conf.GasPrice,
+		IsGasPriceSet:     true,
+		Gas:               EstimateGas(len("hello DappLink")),
+		KeyName:           "appchain",
+		FeeGranterAddress: "celestia1granter",
+	}, got)
+
+	conf.FeeGranterAddress = "0xgranter"
+	require.Error(t, conf.Check())
+}
+
+func TestFeeStrategyEstimateCost(t *testing.T) {
+	conf := DefaultFeeConfig()
+	conf.Strategy = FeeStrategyFixed
+	conf.GasPrice = 0.01
+	amount, gasLimit, price := newFeeStrategy(conf, nil, log.Root()).estimate(context.Background(), 14)
+	require.Equal(t, EstimateGas(14), gasLimit)
+	require.Equal(t, 0.01, price)
+	require.Equal(t, uint64(math.Ceil(0.01*float64(gasLimit))), amount)
+
+	conf.GasLimit = 100000
+	amount, gasLimit, _ = newFeeStrategy(conf, nil, log.Root()).estimate(context.Background(), 14)
+	require.Equal(t, uint64(100000), gasLimit)
+	require.Equal(t, uint64(1000), amount)
+}
+
+func TestEstimateGas(t *testing.T) {
+	// one share
+	require.Equal(t, uint64(512*8+700+75000), EstimateGas(14))
+	// a blob spilling over a second share
+	require.Equal(t, uint64(2*512*8+700+75000), EstimateGas(500))
+	require.Equal(t, EstimateGas(14)+EstimateGas(500)-75000, EstimateGas(14, 500))
+}
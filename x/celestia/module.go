@@ -3,6 +3,7 @@ package celestia
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync/atomic"
 
 	client "github.com/celestiaorg/celestia-openrpc"
@@ -11,19 +12,38 @@ import (
 	"github.com/urfave/cli/v2"
 
 	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
+	ethclient "github.com/eniac-x-labs/rollup-node/client"
+	_common "github.com/eniac-x-labs/rollup-node/common"
 	cli_config "github.com/eniac-x-labs/rollup-node/config/cli-config"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 var ErrAlreadyStopped = errors.New("already stopped")
 
+var tracer = tracing.Tracer("x/celestia")
+
 type CelestiaRollup struct {
 	CelestiaConfig CLIConfig
 	Config         *cli_config.CLIConfig
 	Log            log.Logger
 	DAClient       *client.Client
 	Namespace      share.Namespace
-	stopped        atomic.Bool
+	Namespaces     *Namespaces
+	fee            *feeStrategy
+	state          *nodeState
+	payForBlob     payForBlobFunc
+	// Blobstream is nil unless [celestia.blobstream] is configured
+	Blobstream *BlobstreamVerifier
+	stopped    atomic.Bool
+	// heightOnlyWarned is set once a receipt without commitment was retrieved
+	heightOnlyWarned atomic.Bool
+}
+
+func (c *CelestiaRollup) Start(ctx context.Context) error {
+	return nil
 }
 
 func (c *CelestiaRollup) Stop(ctx context.Context) error {
@@ -33,6 +53,19 @@ func (c *CelestiaRollup) Stop(ctx context.Context) error {
 
 	c.Log.Info("Stopping Celestia rollup service")
 
+	if c.Blobstream != nil {
+		c.Blobstream.l1.Close()
+		if node, ok := c.Blobstream.node.(*nodeProofs); ok {
+			node.closer()
+		}
+	}
+	if c.state != nil {
+		c.state.closer()
+	}
+	if c.DAClient != nil {
+		c.DAClient.Close()
+	}
+
 	c.stopped.Store(true)
 	c.Log.Info("Celestia rollup service stopped")
 
@@ -63,7 +96,7 @@ func CelestiaServiceFromCLIConfig(ctx context.Context, cfg *cli_config.CLIConfig
 func NewCelestiaRollupWithConfig(ctx context.Context, config *CelestiaConfig) (*CelestiaRollup, error) {
 	if config == nil {
 		log.Error("celestia config is nil pointer")
-		return nil, nil
+		return nil, errors.New("celestia config is nil pointer")
 	}
 
 	var c CelestiaRollup
@@ -86,44 +119,151 @@ func (c *CelestiaRollup) initFromCLIConfig(ctx context.Context, celestiaConfig C
 }
 
 func (c *CelestiaRollup) initDA(ctx context.Context, celestiaConfig CLIConfig) error {
-	namespace, err := share.NewBlobNamespaceV0([]byte{0xDE, 0xAD, 0xBE, 0xEF})
+	namespaces, err := NewNamespaces(celestiaConfig.Namespace, celestiaConfig.Tenants)
 	if err != nil {
 		return err
 	}
+	feeConfig := celestiaConfig.Fee.orDefault()
+	if err := feeConfig.Check(); err != nil {
+		return fmt.Errorf("invalid fee config: %w", err)
+	}
+	if err := celestiaConfig.Blobstream.Check(); err != nil {
+		return err
+	}
 
 	client, err := client.NewClient(ctx, celestiaConfig.DaRpc, celestiaConfig.AuthToken)
 	if err != nil {
 		return err
 	}
-
-	c.Namespace = namespace
 	c.DAClient = client
+	c.state, err = dialNodeState(ctx, celestiaConfig.DaRpc, celestiaConfig.AuthToken)
+	if err != nil {
+		return err
+	}
+
+	if celestiaConfig.Blobstream.Enabled() {
+		node, err := dialNodeProofs(ctx, client, celestiaConfig.DaRpc, celestiaConfig.AuthToken)
+		if err != nil {
+			return err
+		}
+		l1, err := ethclient.DialEthClient(ctx, celestiaConfig.Blobstream.L1Rpc)
+		if err != nil {
+			node.closer()
+			return fmt.Errorf("dial blobstream l1: %w", err)
+		}
+		c.Blobstream = newBlobstreamVerifier(l1, node, celestiaConfig.Blobstream)
+	}
+
+	c.Namespace = namespaces.Default
+	c.Namespaces = namespaces
+	c.fee = newFeeStrategy(feeConfig, c.state.gasPrice, c.Log)
+	// the openrpc client only pays from the default key, older nodes lack the tx config
+	c.payForBlob = legacyPayForBlob(&client.State)
+	if feeConfig.txOptions() {
+		c.payForBlob = c.state.payForBlob(feeConfig)
+	}
 
 	return nil
 }
 
-func (c *CelestiaRollup) SubmitBlob(ctx context.Context, data []byte) (uint64, error) {
+// SubmitBlob stores data under the namespace of ctx, see common.WithNamespace, or the configured one.
+// The gas price follows the fee strategy, the receipt records the fee paid.
+func (c *CelestiaRollup) SubmitBlob(ctx context.Context, data []byte) (_ *Receipt, err error) {
+	ctx, span := tracer.Start(ctx, "celestia.SubmitBlob", trace.WithAttributes(attribute.Int("data.size", len(data))))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	namespace, err := c.Namespaces.Resolve(_common.NamespaceFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("celestia.namespace", namespace.String()))
 
-	blobData, err := blob.NewBlobV0(c.Namespace, data)
+	blobData, err := blob.NewBlobV0(namespace, data)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
 	// submit the blob to the network
-	height, err := c.DAClient.Blob.Submit(ctx, []*blob.Blob{blobData}, blob.DefaultGasPrice())
+	height, fee, err := c.fee.submit(ctx, c.payForBlob, []*blob.Blob{blobData})
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
+	span.SetAttributes(
+		attribute.Int64("celestia.height", int64(height)),
+		attribute.Int64("celestia.fee_utia", int64(fee.Amount)),
+		attribute.Float64("celestia.gas_price", fee.GasPrice),
+		attribute.Int("celestia.attempts", fee.Attempts),
+	)
+
+	return &Receipt{Height: height, Namespace: namespace, Commitment: blobData.Commitment, Fee: fee}, nil
+}
 
-	return height, nil
+// RetrievedBlobs returns the blob stored at height under the configured namespace.
+func (c *CelestiaRollup) RetrievedBlobs(ctx context.Context, height uint64) (_ []byte, err error) {
+	return c.RetrieveBlob(ctx, &Receipt{Height: height, Namespace: c.Namespace})
 }
 
-func (c *CelestiaRollup) RetrievedBlobs(ctx context.Context, height uint64) ([]byte, error) {
+// RetrieveBlob returns the blob of the receipt. Receipts without commitment, such as the bare height
+// rollups return first, are deprecated: they return the first blob of the namespace at their height,
+// whichever blob that is.
+func (c *CelestiaRollup) RetrieveBlob(ctx context.Context, receipt *Receipt) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "celestia.RetrieveBlob", trace.WithAttributes(
+		attribute.Int64("celestia.height", int64(receipt.Height)),
+		attribute.String("celestia.namespace", receipt.Namespace.String()),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if len(receipt.Commitment) != 0 {
+		b, err := c.DAClient.Blob.Get(ctx, receipt.Height, receipt.Namespace, receipt.Commitment)
+		if err != nil {
+			return nil, err
+		}
+		return b.Data, nil
+	}
+
+	if !c.heightOnlyWarned.Swap(true) {
+		c.Log.Warn("retrieving a celestia blob by height is deprecated, it returns the first blob of the namespace, retrieve with the full receipt instead",
+			"height", receipt.Height, "namespace", receipt.Namespace.String())
+	}
 	// fetch the blob back from the network
-	retrievedBlobs, err := c.DAClient.Blob.GetAll(ctx, height, []share.Namespace{c.Namespace})
+	retrievedBlobs, err := c.DAClient.Blob.GetAll(ctx, receipt.Height, []share.Namespace{receipt.Namespace})
 	if err != nil {
 		return nil, err
 	}
+	if len(retrievedBlobs) == 0 {
+		return nil, blob.ErrBlobNotFound
+	}
 
 	return retrievedBlobs[0].Data, nil
 }
+
+// VerifyBlobstream proves the blob of receipt against the data commitments relayed to L1, see
+// BlobstreamVerifier.Verify.
+func (c *CelestiaRollup) VerifyBlobstream(ctx context.Context, receipt *Receipt) (*BlobstreamProof, error) {
+	if c.Blobstream == nil {
+		return nil, ErrBlobstreamDisabled
+	}
+	return c.Blobstream.Verify(ctx, receipt)
+}
+
+// HealthCheck reports whether the celestia node is reachable, accepts our auth token,
+// has finished syncing and holds a balance to pay for blobs.
+func (c *CelestiaRollup) HealthCheck(ctx context.Context) error {
+	state, err := c.DAClient.Header.SyncState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get celestia sync state: %w", err)
+	}
+	if !state.Finished() {
+		return fmt.Errorf("celestia node is syncing, height: %d, target: %d", state.Height, state.ToHeight)
+	}
+
+	balance, err := c.DAClient.State.Balance(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get celestia account balance: %w", err)
+	}
+	if !balance.Amount.IsPositive() {
+		return errors.New("celestia account balance is zero")
+	}
+
+	return nil
+}
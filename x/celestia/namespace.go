@@ -0,0 +1,124 @@
+package celestia
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+)
+
+// DefaultNamespaceID is used when no namespace is configured, it is the one blobs were stored under
+// before the namespace was configurable.
+var DefaultNamespaceID = []byte{0xDE, 0xAD, 0xBE, 0xEF}
+
+// ParseNamespace parses a hex namespace, either a version 0 namespace id of at most 10 bytes or a
+// full 29 bytes namespace.
+func ParseNamespace(s string) (share.Namespace, error) {
+	id, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("namespace must be hex: %w", err)
+	}
+	if len(id) == len(share.PayForBlobNamespace) {
+		namespace, err := share.NamespaceFromBytes(id)
+		if err != nil {
+			return nil, err
+		}
+		return namespace, namespace.ValidateForBlob()
+	}
+	return share.NewBlobNamespaceV0(id)
+}
+
+// Namespaces resolves the namespace of a request: the configured one, a tenant or a raw namespace.
+type Namespaces struct {
+	Default share.Namespace
+	Tenants map[string]share.Namespace
+}
+
+// NewNamespaces parses the configured namespace and tenants, see ParseNamespace.
+func NewNamespaces(namespace string, tenants map[string]string) (*Namespaces, error) {
+	res := &Namespaces{Tenants: make(map[string]share.Namespace, len(tenants))}
+	var err error
+	if len(namespace) == 0 {
+		res.Default, err = share.NewBlobNamespaceV0(DefaultNamespaceID)
+	} else {
+		res.Default, err = ParseNamespace(namespace)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace %q: %w", namespace, err)
+	}
+	for tenant, namespace := range tenants {
+		if res.Tenants[tenant], err = ParseNamespace(namespace); err != nil {
+			return nil, fmt.Errorf("invalid namespace %q of tenant %s: %w", namespace, tenant, err)
+		}
+	}
+	return res, nil
+}
+
+// Resolve returns the default namespace for an empty name, the namespace of a tenant, or parses
+// name as a namespace.
+func (n *Namespaces) Resolve(name string) (share.Namespace, error) {
+	if len(name) == 0 {
+		return n.Default, nil
+	}
+	if namespace, ok := n.Tenants[name]; ok {
+		return namespace, nil
+	}
+	namespace, err := ParseNamespace(name)
+	if err != nil {
+		return nil, fmt.Errorf("%q is neither a tenant nor a namespace: %w", name, err)
+	}
+	return namespace, nil
+}
+
+// Receipt locates a blob, it is encoded as "<height>:<namespace hex>:<commitment hex>:<fee>utia".
+// Receipts without fee or commitment, "<height>:<namespace hex>", are still accepted.
+type Receipt struct {
+	Height     uint64
+	Namespace  share.Namespace
+	Commitment blob.Commitment
+	// Fee is only known to the submitter, ParseReceipt only restores its amount
+	Fee *Fee
+}
+
+func (r *Receipt) String() string {
+	if len(r.Commitment) == 0 {
+		return fmt.Sprintf("%d:%s", r.Height, r.Namespace)
+	}
+	if r.Fee == nil {
+		return fmt.Sprintf("%d:%s:%x", r.Height, r.Namespace, []byte(r.Commitment))
+	}
+	return fmt.Sprintf("%d:%s:%x:%dutia", r.Height, r.Namespace, []byte(r.Commitment), r.Fee.Amount)
+}
+
+// ParseReceipt parses a receipt encoded by Receipt.String.
+func ParseReceipt(s string) (*Receipt, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 4 {
+		return nil, fmt.Errorf("invalid celestia receipt %q, want <height>:<namespace>:<commitment>", s)
+	}
+	height, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid celestia receipt height: %w", err)
+	}
+	namespace, err := ParseNamespace(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid celestia receipt namespace: %w", err)
+	}
+	receipt := &Receipt{Height: height, Namespace: namespace}
+	if len(parts) > 2 {
+		if receipt.Commitment, err = hex.DecodeString(strings.TrimPrefix(parts[2], "0x")); err != nil {
+			return nil, fmt.Errorf("invalid celestia receipt commitment: %w", err)
+		}
+	}
+	if len(parts) > 3 {
+		amount, err := strconv.ParseUint(strings.TrimSuffix(parts[3], "utia"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid celestia receipt fee: %w", err)
+		}
+		receipt.Fee = &Fee{Amount: amount}
+	}
+	return receipt, nil
+}
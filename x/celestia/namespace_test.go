@@ -0,0 +1,65 @@
+package celestia
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacesResolve(t *testing.T) {
+	namespaces, err := NewNamespaces("", map[string]string{"appchain_a": "0a0a"})
+	require.NoError(t, err)
+
+	deadbeef, _ := share.NewBlobNamespaceV0(DefaultNamespaceID)
+	tenant, _ := share.NewBlobNamespaceV0([]byte{0x0a, 0x0a})
+	raw, _ := share.NewBlobNamespaceV0([]byte("DappLink"))
+
+	namespace, err := namespaces.Resolve("")
+	require.NoError(t, err)
+	require.Equal(t, deadbeef, namespace)
+
+	namespace, err = namespaces.Resolve("appchain_a")
+	require.NoError(t, err)
+	require.Equal(t, tenant, namespace)
+
+	namespace, err = namespaces.Resolve(raw.String())
+	require.NoError(t, err)
+	require.Equal(t, raw, namespace)
+
+	_, err = namespaces.Resolve("appchain_b")
+	require.Error(t, err)
+
+	_, err = NewNamespaces("", map[string]string{"appchain_a": "0a0a0a0a0a0a0a0a0a0a0a"})
+	require.Error(t, err)
+	// reserved namespaces can't hold blobs
+	_, err = ParseNamespace(share.PayForBlobNamespace.String())
+	require.Error(t, err)
+}
+
+func TestReceiptRoundTrip(t *testing.T) {
+	namespace, _ := share.NewBlobNamespaceV0([]byte("DappLink"))
+	b, err := blob.NewBlobV0(namespace, []byte("hello DappLink"))
+	require.NoError(t, err)
+	receipt := &Receipt{Height: 2075034, Namespace: namespace, Commitment: b.Commitment}
+
+	parsed, err := ParseReceipt(receipt.String())
+	require.NoError(t, err)
+	require.Equal(t, receipt, parsed)
+
+	// only the amount of the fee is encoded
+	receipt.Fee = &Fee{Amount: 1920, GasPrice: 0.002, Attempts: 2}
+	parsed, err = ParseReceipt(receipt.String())
+	require.NoError(t, err)
+	require.Equal(t, &Fee{Amount: 1920}, parsed.Fee)
+
+	// receipts without commitment are still accepted
+	parsed, err = ParseReceipt(fmt.Sprintf("2075034:%s", namespace))
+	require.NoError(t, err)
+	require.Equal(t, &Receipt{Height: 2075034, Namespace: namespace}, parsed)
+
+	_, err = ParseReceipt("2075034")
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package celestia
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/celestiaorg/celestia-openrpc/types/blob"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+var ErrNoCommitment = errors.New("celestia receipt has no commitment, submit the data again to get a provable receipt")
+
+// InclusionProof proves that the blob of a receipt is included in the block at its height.
+type InclusionProof struct {
+	Receipt *Receipt
+	// Index is the index of the first share of the blob in the extended data square.
+	Index    int
+	Proof    *blob.Proof
+	Included bool
+}
+
+// GetProof fetches the inclusion proof of the blob of receipt and checks it against the node.
+func (c *CelestiaRollup) GetProof(ctx context.Context, receipt *Receipt) (_ *InclusionProof, err error) {
+	ctx, span := tracer.Start(ctx, "celestia.GetProof", trace.WithAttributes(
+		attribute.Int64("celestia.height", int64(receipt.Height)),
+		attribute.String("celestia.namespace", receipt.Namespace.String()),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	if len(receipt.Commitment) == 0 {
+		return nil, ErrNoCommitment
+	}
+	b, err := c.DAClient.Blob.Get(ctx, receipt.Height, receipt.Namespace, receipt.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	index, err := blobIndex(b)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := c.DAClient.Blob.GetProof(ctx, receipt.Height, receipt.Namespace, receipt.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	included, err := c.DAClient.Blob.Included(ctx, receipt.Height, receipt.Namespace, proof, receipt.Commitment)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(attribute.Bool("celestia.included", included))
+
+	return &InclusionProof{Receipt: receipt, Index: index, Proof: proof, Included: included}, nil
+}
+
+// blobIndex reads the index the node set on a retrieved blob, it is only exposed by its json encoding.
+func blobIndex(b *blob.Blob) (int, error) {
+	encoded, err := json.Marshal(b)
+	if err != nil {
+		return 0, err
+	}
+	var decoded struct {
+		Index int `json:"index"`
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return 0, err
+	}
+	return decoded.Index, nil
+}
@@ -0,0 +1,189 @@
+// Package conformance is the test suite every DA backend passes against its local stand-in. A
+// backend is adapted to Backend and the suite is run from its tests:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, func(t *testing.T) conformance.Backend { return newBackend(t) }, conformance.Options{})
+//	}
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Backend is the part of a DA backend the suite exercises.
+type Backend interface {
+	// Store returns the receipt data is retrieved with
+	Store(ctx context.Context, data []byte) (receipt interface{}, err error)
+	Retrieve(ctx context.Context, receipt interface{}) ([]byte, error)
+	// Close releases the backend, the requests following it must fail
+	Close() error
+}
+
+// Options describe the limits of a backend.
+type Options struct {
+	// MaxSize is the largest payload the backend accepts, a larger one must be rejected. The size checks
+	// are skipped when 0.
+	MaxSize int
+	// UnknownReceipt is a well formed receipt of data never stored, the check is skipped when nil
+	UnknownReceipt interface{}
+	// Concurrency is the number of submissions made at once, 8 when 0
+	Concurrency int
+	// Timeout bounds every request of the suite, 30s when 0
+	Timeout time.Duration
+}
+
+// Run runs the suite, every test against a backend of its own built by newBackend.
+func Run(t *testing.T, newBackend func(t *testing.T) Backend, opts Options) {
+	if opts.Concurrency == 0 {
+		opts.Concurrency = 8
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	for _, tc := range []struct {
+		name string
+		test func(t *testing.T, ctx context.Context, b Backend, opts Options)
+	}{
+		{"RoundTrip", testRoundTrip},
+		{"EmptyPayload", testEmptyPayload},
+		{"MaxSizePayload", testMaxSizePayload},
+		{"ConcurrentSubmissions", testConcurrentSubmissions},
+		{"UnknownReceipt", testUnknownReceipt},
+		{"ContextCancellation", testContextCancellation},
+		{"Close", testClose},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := newBackend(t)
+			ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+			defer cancel()
+			tc.test(t, ctx, b, opts)
+		})
+	}
+}
+
+func roundTrip(t *testing.T, ctx context.Context, b Backend, data []byte) interface{} {
+	receipt, err := b.Store(ctx, data)
+	require.NoError(t, err, "store %d bytes", len(data))
+	got, err := b.Retrieve(ctx, receipt)
+	require.NoError(t, err, "retrieve %v", receipt)
+	require.True(t, bytes.Equal(data, got), "retrieved %d bytes, stored %d", len(got), len(data))
+	return receipt
+}
+
+func random(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func testRoundTrip(t *testing.T, ctx context.Context, b Backend, _ Options) {
+	for _, data := range [][]byte{
+		[]byte("rollup batch"),
+		make([]byte, 1024),
+		random(t, 4096),
+	} {
+		roundTrip(t, ctx, b, data)
+	}
+	// the same payload stored twice is retrieved with either receipt
+	data := []byte("rollup batch")
+	roundTrip(t, ctx, b, data)
+}
+
+// testEmptyPayload lets a backend reject an empty payload, one it accepts is retrieved empty.
+func testEmptyPayload(t *testing.T, ctx context.Context, b Backend, _ Options) {
+	receipt, err := b.Store(ctx, []byte{})
+	if err != nil {
+		t.Logf("empty payload rejected: %v", err)
+		return
+	}
+	got, err := b.Retrieve(ctx, receipt)
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func testMaxSizePayload(t *testing.T, ctx context.Context, b Backend, opts Options) {
+	if opts.MaxSize == 0 {
+		t.Skip("no max size")
+	}
+	roundTrip(t, ctx, b, random(t, opts.MaxSize))
+	_, err := b.Store(ctx, random(t, opts.MaxSize+1))
+	require.Error(t, err, "payload above the max size accepted")
+}
+
+func testConcurrentSubmissions(t *testing.T, ctx context.Context, b Backend, opts Options) {
+	payloads := make([][]byte, opts.Concurrency)
+	receipts := make([]interface{}, opts.Concurrency)
+	errs := make([]error, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i := range payloads {
+		payloads[i] = []byte(fmt.Sprintf("rollup batch %d", i))
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			receipts[i], errs[i] = b.Store(ctx, payloads[i])
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]int)
+	for i, receipt := range receipts {
+		require.NoError(t, errs[i], "submission %d", i)
+		key := fmt.Sprint(receipt)
+		prev, ok := seen[key]
+		require.False(t, ok, "submissions %d and %d got the same receipt", prev, i)
+		seen[key] = i
+	}
+	for i, receipt := range receipts {
+		got, err := b.Retrieve(ctx, receipt)
+		require.NoError(t, err, "retrieve submission %d", i)
+		require.Equal(t, payloads[i], got, "submission %d", i)
+	}
+}
+
+func testUnknownReceipt(t *testing.T, ctx context.Context, b Backend, opts Options) {
+	if opts.UnknownReceipt == nil {
+		t.Skip("no unknown receipt")
+	}
+	data, err := b.Retrieve(ctx, opts.UnknownReceipt)
+	require.Error(t, err, "retrieved %d bytes for an unknown receipt", len(data))
+}
+
+func testContextCancellation(t *testing.T, ctx context.Context, b Backend, _ Options) {
+	receipt := roundTrip(t, ctx, b, []byte("rollup batch"))
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := b.Store(canceled, []byte("canceled batch"))
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = b.Retrieve(canceled, receipt)
+	require.ErrorIs(t, err, context.Canceled)
+
+	expired, cancel := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancel()
+	_, err = b.Store(expired, []byte("expired batch"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// the backend still serves the requests that follow
+	roundTrip(t, ctx, b, []byte("rollup batch"))
+}
+
+func testClose(t *testing.T, ctx context.Context, b Backend, _ Options) {
+	receipt := roundTrip(t, ctx, b, []byte("rollup batch"))
+	require.NoError(t, b.Close())
+
+	_, err := b.Store(ctx, []byte("rollup batch"))
+	require.Error(t, err, "store after close")
+	_, err = b.Retrieve(ctx, receipt)
+	require.Error(t, err, "retrieve after close")
+	// closing again may fail but must not panic
+	_ = b.Close()
+}
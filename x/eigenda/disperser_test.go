@@ -0,0 +1,122 @@
+package eigenda
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/eniac-x-labs/rollup-node/x/eigenda/mockdisperser"
+)
+
+func newMockClient(t *testing.T, conf mockdisperser.Config, timeout time.Duration) (*EigenDAClient, *mockdisperser.Server) {
+	srv := mockdisperser.New(conf)
+	t.Cleanup(srv.Close)
+	client, err := NewEigenDAClient(&EigenDAConfig{
+		RPC:                      "mockdisperser",
+		StatusQueryTimeout:       timeout,
+		StatusQueryRetryInterval: 10 * time.Millisecond,
+	}, srv.DialOptions()...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client.(*EigenDAClient), srv
+}
+
+func TestMockDisperseBlobAndGetBlobInfo(t *testing.T) {
+	ctx := context.Background()
+	client, _ := newMockClient(t, mockdisperser.Config{ConfirmAfter: 30 * time.Millisecond}, 5*time.Second)
+	data := []byte("rollup batch")
+	info, err := client.DisperseBlobAndGetBlobInfo(ctx, data)
+	require.NoError(t, err)
+	proof := info.GetBlobVerificationProof()
+	require.NotEmpty(t, proof.GetBatchMetadata().GetBatchHeaderHash())
+
+	got, err := client.RetrieveBlob(ctx, proof.GetBatchMetadata().GetBatchHeaderHash(), proof.GetBlobIndex())
+	require.NoError(t, err)
+	require.True(t, bytes.HasPrefix(got, data), "%q", got)
+
+	_, err = client.RetrieveBlob(ctx, []byte("unknown batch"), 0)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMockDisperseBlobAndGetBlobInfoFailures(t *testing.T) {
+	ctx := context.Background()
+
+	client, _ := newMockClient(t, mockdisperser.Config{ConfirmAfter: time.Hour}, 50*time.Millisecond)
+	_, err := client.DisperseBlobAndGetBlobInfo(ctx, []byte("rollup batch"))
+	require.ErrorIs(t, err, ErrStatusQueryTimeout)
+
+	// the caller's deadline ends the wait before the status query timeout
+	client, _ = newMockClient(t, mockdisperser.Config{ConfirmAfter: time.Hour}, time.Hour)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = client.DisperseBlobAndGetBlobInfo(timeoutCtx, []byte("rollup batch"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	client, _ = newMockClient(t, mockdisperser.Config{
+		ConfirmAfter: 20 * time.Millisecond,
+		Fail:         func([]byte) bool { return true },
+	}, 5*time.Second)
+	_, err = client.DisperseBlobAndGetBlobInfo(ctx, []byte("rollup batch"))
+	require.ErrorIs(t, err, ErrDispersalFailed)
+
+	_, err = client.DisperseBlob(ctx, nil)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestMockBlobStatus(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newMockClient(t, mockdisperser.Config{
+		ConfirmAfter:  100 * time.Millisecond,
+		FinalizeAfter: 100 * time.Millisecond,
+	}, time.Second)
+	reqID, err := client.DisperseBlob(ctx, []byte("rollup batch"))
+	require.NoError(t, err)
+
+	st, info, err := client.GetBlobStatus(ctx, reqID)
+	require.NoError(t, err)
+	require.Equal(t, disperser.BlobStatus_PROCESSING, st)
+	require.Nil(t, info)
+
+	require.Eventually(t, func() bool {
+		st, _, err = client.GetBlobStatus(ctx, reqID)
+		return err == nil && st == disperser.BlobStatus_CONFIRMED
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		st, info, err = client.GetBlobStatus(ctx, reqID)
+		return err == nil && st == disperser.BlobStatus_FINALIZED
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NotNil(t, info.GetBlobVerificationProof())
+
+	require.True(t, srv.SetStatus(reqID, disperser.BlobStatus_FAILED))
+	st, _, err = client.GetBlobStatus(ctx, reqID)
+	require.ErrorIs(t, err, ErrDispersalFailed)
+	require.Equal(t, disperser.BlobStatus_FAILED, st)
+
+	_, _, err = client.GetBlobStatus(ctx, []byte("unknown request"))
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestMockHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	client, srv := newMockClient(t, mockdisperser.Config{}, time.Second)
+	// the health check request id is unknown to the disperser, a not found answer is healthy
+	require.NoError(t, client.HealthCheck(ctx))
+
+	srv.SetError(status.Error(codes.Unavailable, "disperser down"))
+	require.Error(t, client.HealthCheck(ctx))
+	_, err := client.DisperseBlob(ctx, []byte("rollup batch"))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	srv.SetError(nil)
+	require.NoError(t, client.HealthCheck(ctx))
+	srv.Close()
+	require.Error(t, client.HealthCheck(ctx))
+}
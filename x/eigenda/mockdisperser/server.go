@@ -0,0 +1,244 @@
+// Package mockdisperser is an in-process EigenDA disperser for tests. It serves the disperser gRPC API over
+// an in-memory connection, so eigenda.EigenDAClient runs against it unchanged without any network:
+//
+//	srv := mockdisperser.New(mockdisperser.Config{ConfirmAfter: 100 * time.Millisecond})
+//	defer srv.Close()
+//	client, err := eigenda.NewEigenDAClient(&eigenda.EigenDAConfig{RPC: "mockdisperser"}, srv.DialOptions()...)
+//
+// Dispersed blobs are processing for Config.ConfirmAfter, then confirmed for Config.FinalizeAfter and
+// finalized, or failed when Config.Fail says so. SetStatus pins the status of a blob and SetError fails
+// every call, for the cases the schedule doesn't cover.
+package mockdisperser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// MaxBlobSize is the size limit of the dispersed data the disperser enforces, once padded.
+const MaxBlobSize = 2 * 1024 * 1024
+
+// symbolSize is the size of a bn254 field element, the unit of the blob length.
+const symbolSize = 32
+
+type Config struct {
+	// ConfirmAfter is how long a dispersed blob is processing before it is confirmed
+	ConfirmAfter time.Duration
+	// FinalizeAfter is how long a confirmed blob waits to be finalized
+	FinalizeAfter time.Duration
+	// Fail makes the blobs it returns true for fail at the end of processing instead of being confirmed
+	Fail func(data []byte) bool
+}
+
+type blob struct {
+	data      []byte
+	dispersed time.Time
+	failed    bool
+	// pinned overrides the schedule when set by SetStatus
+	pinned disperser.BlobStatus
+
+	batchID         uint32
+	batchHeader     *disperser.BatchHeader
+	batchHeaderHash []byte
+}
+
+// Server implements disperser.DisperserServer, requests are served once New returns until Close.
+type Server struct {
+	disperser.UnimplementedDisperserServer
+	conf Config
+	now  func() time.Time
+
+	mu      sync.Mutex
+	blobs   map[string]*blob // by request id
+	batches map[string]*blob // by batch header hash, every blob is the only one of its batch
+	seq     uint32
+	err     error
+
+	listener *bufconn.Listener
+	server   *grpc.Server
+}
+
+func New(conf Config) *Server {
+	s := &Server{
+		conf:     conf,
+		now:      time.Now,
+		blobs:    make(map[string]*blob),
+		batches:  make(map[string]*blob),
+		listener: bufconn.Listen(4 * MaxBlobSize),
+		server:   grpc.NewServer(grpc.MaxRecvMsgSize(2 * MaxBlobSize)),
+	}
+	disperser.RegisterDisperserServer(s.server, s)
+	go func() { _ = s.server.Serve(s.listener) }()
+	return s
+}
+
+// DialOptions connects a client to the server whatever its target, they must follow the options of the
+// client so the insecure credentials replace its TLS ones.
+func (s *Server) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return s.listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// Close stops the server, the calls in flight fail with codes.Unavailable.
+func (s *Server) Close() {
+	s.server.Stop()
+}
+
+// SetStatus pins the status of the blob of requestID, UNKNOWN hands it back to the schedule. It returns
+// false for an unknown request id.
+func (s *Server) SetStatus(requestID []byte, status disperser.BlobStatus) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.blobs[string(requestID)]
+	if ok {
+		b.pinned = status
+	}
+	return ok
+}
+
+// SetError makes every call fail with err until it is cleared with nil, err is best a status error such as
+// status.Error(codes.Unavailable, "down").
+func (s *Server) SetError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+// Len returns the number of dispersed blobs.
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.blobs)
+}
+
+func (s *Server) DisperseBlob(_ context.Context, req *disperser.DisperseBlobRequest) (*disperser.DisperseBlobReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	switch {
+	case len(req.Data) == 0:
+		return nil, status.Error(codes.InvalidArgument, "blob is empty")
+	case len(req.Data) > MaxBlobSize:
+		return nil, status.Errorf(codes.InvalidArgument, "blob size %d exceeds the limit of %d bytes", len(req.Data), MaxBlobSize)
+	}
+
+	s.seq++
+	now := s.now()
+	b := &blob{
+		data:      append([]byte(nil), req.Data...),
+		dispersed: now,
+		failed:    s.conf.Fail != nil && s.conf.Fail(req.Data),
+		batchID:   s.seq,
+	}
+	root := sha256.Sum256(req.Data)
+	b.batchHeader = &disperser.BatchHeader{
+		BatchRoot:               root[:],
+		QuorumNumbers:           []byte{0},
+		QuorumSignedPercentages: []byte{100},
+		ReferenceBlockNumber:    s.seq,
+	}
+	headerHash := sha256.Sum256(binary.BigEndian.AppendUint32(root[:], s.seq))
+	b.batchHeaderHash = headerHash[:]
+
+	// "<blob hash>-<metadata hash>" with the metadata "<requested at ns>/<quorum>/<adversary threshold>/"
+	// followed by its sha256, as the disperser does
+	metadata := []byte(fmt.Sprintf("%d/0/33/", now.UnixNano()+int64(s.seq)))
+	metadataHash := sha256.Sum256(metadata)
+	requestID := []byte(hex.EncodeToString(root[:]) + "-" + hex.EncodeToString(append(metadata, metadataHash[:]...)))
+
+	s.blobs[string(requestID)] = b
+	s.batches[string(b.batchHeaderHash)] = b
+	return &disperser.DisperseBlobReply{Result: disperser.BlobStatus_PROCESSING, RequestId: requestID}, nil
+}
+
+func (s *Server) GetBlobStatus(_ context.Context, req *disperser.BlobStatusRequest) (*disperser.BlobStatusReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	b, ok := s.blobs[string(req.RequestId)]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no blob for request id %q", req.RequestId)
+	}
+	reply := &disperser.BlobStatusReply{Status: s.status(b)}
+	if reply.Status == disperser.BlobStatus_CONFIRMED || reply.Status == disperser.BlobStatus_FINALIZED {
+		reply.Info = b.info()
+	}
+	return reply, nil
+}
+
+func (s *Server) RetrieveBlob(_ context.Context, req *disperser.RetrieveBlobRequest) (*disperser.RetrieveBlobReply, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	b, ok := s.batches[string(req.BatchHeaderHash)]
+	if !ok || req.BlobIndex != 0 {
+		return nil, status.Errorf(codes.NotFound, "no blob %d in batch %x", req.BlobIndex, req.BatchHeaderHash)
+	}
+	if st := s.status(b); st != disperser.BlobStatus_CONFIRMED && st != disperser.BlobStatus_FINALIZED {
+		return nil, status.Errorf(codes.NotFound, "blob %d of batch %x is %s", req.BlobIndex, req.BatchHeaderHash, st)
+	}
+	return &disperser.RetrieveBlobReply{Data: b.data}, nil
+}
+
+// status returns the status of b at the current time of its schedule.
+func (s *Server) status(b *blob) disperser.BlobStatus {
+	if b.pinned != disperser.BlobStatus_UNKNOWN {
+		return b.pinned
+	}
+	elapsed := s.now().Sub(b.dispersed)
+	switch {
+	case elapsed < s.conf.ConfirmAfter:
+		return disperser.BlobStatus_PROCESSING
+	case b.failed:
+		return disperser.BlobStatus_FAILED
+	case elapsed < s.conf.ConfirmAfter+s.conf.FinalizeAfter:
+		return disperser.BlobStatus_CONFIRMED
+	}
+	return disperser.BlobStatus_FINALIZED
+}
+
+func (b *blob) info() *disperser.BlobInfo {
+	return &disperser.BlobInfo{
+		BlobHeader: &disperser.BlobHeader{
+			DataLength: uint32((len(b.data) + symbolSize - 1) / symbolSize),
+			BlobQuorumParams: []*disperser.BlobQuorumParam{{
+				QuorumNumber:                    0,
+				AdversaryThresholdPercentage:    33,
+				ConfirmationThresholdPercentage: 55,
+			}},
+		},
+		BlobVerificationProof: &disperser.BlobVerificationProof{
+			BatchId:   b.batchID,
+			BlobIndex: 0,
+			BatchMetadata: &disperser.BatchMetadata{
+				BatchHeader:             b.batchHeader,
+				BatchHeaderHash:         b.batchHeaderHash,
+				ConfirmationBlockNumber: b.batchHeader.ReferenceBlockNumber + 1,
+			},
+			QuorumIndexes: []byte{0},
+		},
+	}
+}
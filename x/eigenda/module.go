@@ -14,8 +14,25 @@ import (
 	"github.com/Layr-Labs/eigenda/api/grpc/disperser"
 	"github.com/Layr-Labs/eigenda/encoding/utils/codec"
 	"github.com/ethereum/go-ethereum/log"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/eniac-x-labs/rollup-node/metrics"
+	"github.com/eniac-x-labs/rollup-node/tracing"
+)
+
+var tracer = tracing.Tracer("x/eigenda")
+
+var (
+	// ErrDispersalFailed is returned for a blob the disperser failed to process
+	ErrDispersalFailed = errors.New("eigenDA blob dispersal failed in processing")
+	// ErrStatusQueryTimeout is returned for a blob not confirmed within the status query timeout
+	ErrStatusQueryTimeout = errors.New("timed out getting EigenDA status for dispersed blob")
 )
 
 type IEigenDA interface {
@@ -23,37 +40,82 @@ type IEigenDA interface {
 	DisperseBlob(ctx context.Context, txData []byte) ([]byte, error)
 	GetBlobStatus(ctx context.Context, reqID []byte) (disperser.BlobStatus, *disperser.BlobInfo, error)
 	DisperseBlobAndGetBlobInfo(ctx context.Context, txData []byte) (*disperser.BlobInfo, error)
+	HealthCheck(ctx context.Context) error
+	Close() error
 }
 
+// healthCheckRequestID is queried by HealthCheck, the disperser answers it with a not found
+// or invalid argument error as long as it is reachable and accepts our connection.
+var healthCheckRequestID = []byte("rollup-node-health-check")
+
+// blobStatusCacheSize bounds the number of dispersed blobs whose last status is tracked for metrics.
+const blobStatusCacheSize = 4096
+
 type EigenDAClient struct {
 	DisperserCli disperser.DisperserClient
+	conn         *grpc.ClientConn
 	EigenDAConfig
-	logger log.Logger
+	Metrics metrics.RollupMetricer
+	logger  log.Logger
+
+	// lastStatus remembers the last seen status of each request id to record status transitions
+	lastStatus *lru.Cache[string, disperser.BlobStatus]
 }
 
-func NewEigenDAClient(cfg *EigenDAConfig) (IEigenDA, error) {
+// NewEigenDAClient connects to the disperser at cfg.RPC over TLS, opts are applied after the TLS
+// credentials, so tests may replace them, see mockdisperser.
+func NewEigenDAClient(cfg *EigenDAConfig, opts ...grpc.DialOption) (IEigenDA, error) {
 	config := &tls.Config{}
 	credential := credentials.NewTLS(config)
 	dialOptions := []grpc.DialOption{grpc.WithTransportCredentials(credential)}
-	conn, err := grpc.Dial(cfg.RPC, dialOptions...)
+	conn, err := grpc.Dial(cfg.RPC, append(dialOptions, opts...)...)
 	if err != nil {
 		return nil, err
 	}
 	daClient := disperser.NewDisperserClient(conn)
 
+	lastStatus, err := lru.New[string, disperser.BlobStatus](blobStatusCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
 	logger := log.Root().With(slog.String("module", "eigenda"))
 	return &EigenDAClient{
 		DisperserCli: daClient,
+		conn:         conn,
 		EigenDAConfig: EigenDAConfig{
 			RPC:                      cfg.RPC,
 			StatusQueryTimeout:       cfg.StatusQueryTimeout,
 			StatusQueryRetryInterval: cfg.StatusQueryRetryInterval,
 		},
-		logger: logger,
+		Metrics:    metrics.NoopRollupMetrics,
+		logger:     logger,
+		lastStatus: lastStatus,
 	}, nil
 }
 
-func (m *EigenDAClient) RetrieveBlob(ctx context.Context, BatchHeaderHash []byte, BlobIndex uint32) ([]byte, error) {
+// recordStatus records a status transition of the given request id if its status changed since it was last seen.
+func (m *EigenDAClient) recordStatus(reqID []byte, status disperser.BlobStatus) {
+	key := string(reqID)
+	from := "NONE"
+	if last, ok := m.lastStatus.Get(key); ok {
+		if last == status {
+			return
+		}
+		from = last.String()
+	}
+	m.Metrics.RecordEigenDAStatusTransition(from, status.String())
+
+	if status == disperser.BlobStatus_FINALIZED || status == disperser.BlobStatus_FAILED {
+		m.lastStatus.Remove(key)
+		return
+	}
+	m.lastStatus.Add(key, status)
+}
+
+func (m *EigenDAClient) RetrieveBlob(ctx context.Context, BatchHeaderHash []byte, BlobIndex uint32) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "eigenda.RetrieveBlob", trace.WithAttributes(attribute.Int64("eigenda.blob_index", int64(BlobIndex))))
+	defer func() { tracing.EndSpan(span, err) }()
 	if m.DisperserCli == nil {
 		return nil, errors.New("eigendDA disperserCli is nil")
 	}
@@ -72,7 +134,9 @@ func (m *EigenDAClient) RetrieveBlob(ctx context.Context, BatchHeaderHash []byte
 	return decodedData, nil
 }
 
-func (m *EigenDAClient) DisperseBlobAndGetBlobInfo(ctx context.Context, txData []byte) (*disperser.BlobInfo, error) {
+func (m *EigenDAClient) DisperseBlobAndGetBlobInfo(ctx context.Context, txData []byte) (_ *disperser.BlobInfo, err error) {
+	ctx, span := tracer.Start(ctx, "eigenda.DisperseBlobAndGetBlobInfo", trace.WithAttributes(attribute.Int("data.size", len(txData))))
+	defer func() { tracing.EndSpan(span, err) }()
 	m.logger.Info("Attempting to disperse blob to EigenDA")
 	if m.DisperserCli == nil {
 		return nil, errors.New("eigendDA disperserCli is nil")
@@ -92,6 +156,7 @@ func (m *EigenDAClient) DisperseBlobAndGetBlobInfo(ctx context.Context, txData [
 	}
 	m.logger.Debug("daClient.DisperseBlob", "disperseRes", disperseRes)
 	m.logger.Debug("daClient.DisperseBlob", "disperseRes.Result", disperseRes.Result)
+	m.recordStatus(disperseRes.RequestId, disperseRes.Result)
 	if disperseRes.Result == disperser.BlobStatus_UNKNOWN ||
 		disperseRes.Result == disperser.BlobStatus_FAILED {
 		m.logger.Error("Unable to disperse blob to EigenDA, aborting", "err", err)
@@ -105,14 +170,26 @@ func (m *EigenDAClient) DisperseBlobAndGetBlobInfo(ctx context.Context, txData [
 	var statusRes *disperser.BlobStatusReply
 	timeoutTime := time.Now().Add(m.StatusQueryTimeout)
 	// Wait before first status check
-	time.Sleep(m.StatusQueryRetryInterval)
+	if err := sleep(ctx, m.StatusQueryRetryInterval); err != nil {
+		return nil, err
+	}
 	for time.Now().Before(timeoutTime) {
 		statusRes, err = m.DisperserCli.GetBlobStatus(ctx, &disperser.BlobStatusRequest{
 			RequestId: disperseRes.RequestId,
 		})
 		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
 			m.logger.Warn("Unable to retrieve blob dispersal status, will retry", "requestID", base64RequestID, "err", err)
-		} else if statusRes.Status == disperser.BlobStatus_CONFIRMED || statusRes.Status == disperser.BlobStatus_FINALIZED {
+			if err := sleep(ctx, m.StatusQueryRetryInterval); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		m.recordStatus(disperseRes.RequestId, statusRes.Status)
+		span.AddEvent("blob status", trace.WithAttributes(attribute.String("eigenda.status", statusRes.Status.String())))
+		if statusRes.Status == disperser.BlobStatus_CONFIRMED || statusRes.Status == disperser.BlobStatus_FINALIZED {
 			// TODO(eigenlayer): As long as fault proofs are disabled, we can move on once a blob is confirmed
 			// but not yet finalized, without further logic. Once fault proofs are enabled, we will need to update
 			// the proposer to wait until the blob associated with an L2 block has been finalized, i.e. the EigenDA
@@ -123,19 +200,35 @@ func (m *EigenDAClient) DisperseBlobAndGetBlobInfo(ctx context.Context, txData [
 		} else if statusRes.Status == disperser.BlobStatus_UNKNOWN ||
 			statusRes.Status == disperser.BlobStatus_FAILED {
 			m.logger.Error("EigenDA blob dispersal failed in processing", "requestID", base64RequestID, "err", err)
-			return nil, fmt.Errorf("eigenDA blob dispersal failed in processing with reply status %d", statusRes.Status)
+			return nil, fmt.Errorf("%w with reply status %d", ErrDispersalFailed, statusRes.Status)
 		} else {
 			m.logger.Warn("Still waiting for confirmation from EigenDA", "requestID", base64RequestID)
 		}
 
-		// Wait before first status check
-		time.Sleep(m.StatusQueryRetryInterval)
+		// Wait before next status check
+		if err := sleep(ctx, m.StatusQueryRetryInterval); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil, fmt.Errorf("timed out getting EigenDA status for dispersed blob key: %s", base64RequestID)
+	return nil, fmt.Errorf("%w key: %s", ErrStatusQueryTimeout, base64RequestID)
 }
 
-func (m *EigenDAClient) DisperseBlob(ctx context.Context, txData []byte) ([]byte, error) {
+// sleep waits for d unless ctx ends first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *EigenDAClient) DisperseBlob(ctx context.Context, txData []byte) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "eigenda.DisperseBlob", trace.WithAttributes(attribute.Int("data.size", len(txData))))
+	defer func() { tracing.EndSpan(span, err) }()
 	m.logger.Info("Attempting to disperse blob to EigenDA", "txDataHex", hex.EncodeToString(txData))
 
 	if m.DisperserCli == nil {
@@ -158,6 +251,7 @@ func (m *EigenDAClient) DisperseBlob(ctx context.Context, txData []byte) ([]byte
 
 	m.logger.Debug("daClient.DisperseBlob", "disperseRes", disperseRes)
 	m.logger.Debug("daClient.DisperseBlob", "disperseRes.Result", disperseRes.Result)
+	m.recordStatus(disperseRes.RequestId, disperseRes.Result)
 	if disperseRes.Result == disperser.BlobStatus_UNKNOWN ||
 		disperseRes.Result == disperser.BlobStatus_FAILED {
 		m.logger.Error("Unable to disperse blob to EigenDA, aborting", "err", err)
@@ -170,7 +264,9 @@ func (m *EigenDAClient) DisperseBlob(ctx context.Context, txData []byte) ([]byte
 	return disperseRes.RequestId, nil
 }
 
-func (m *EigenDAClient) GetBlobStatus(ctx context.Context, reqID []byte) (disperser.BlobStatus, *disperser.BlobInfo, error) {
+func (m *EigenDAClient) GetBlobStatus(ctx context.Context, reqID []byte) (_ disperser.BlobStatus, _ *disperser.BlobInfo, err error) {
+	ctx, span := tracer.Start(ctx, "eigenda.GetBlobStatus")
+	defer func() { tracing.EndSpan(span, err) }()
 	if m.DisperserCli == nil {
 		return -1, nil, errors.New("eigendDA disperserCli is nil")
 	}
@@ -184,7 +280,10 @@ func (m *EigenDAClient) GetBlobStatus(ctx context.Context, reqID []byte) (disper
 	if err != nil {
 		m.logger.Warn("Unable to retrieve blob dispersal status, should retry", "requestID", base64RequestID, "err", err)
 		return -1, nil, err
-	} else if statusRes.Status == disperser.BlobStatus_CONFIRMED || statusRes.Status == disperser.BlobStatus_FINALIZED {
+	}
+	m.recordStatus(reqID, statusRes.Status)
+	span.SetAttributes(attribute.String("eigenda.status", statusRes.Status.String()))
+	if statusRes.Status == disperser.BlobStatus_CONFIRMED || statusRes.Status == disperser.BlobStatus_FINALIZED {
 		// TODO(eigenlayer): As long as fault proofs are disabled, we can move on once a blob is confirmed
 		// but not yet finalized, without further logic. Once fault proofs are enabled, we will need to update
 		// the proposer to wait until the blob associated with an L2 block has been finalized, i.e. the EigenDA
@@ -195,8 +294,31 @@ func (m *EigenDAClient) GetBlobStatus(ctx context.Context, reqID []byte) (disper
 	} else if statusRes.Status == disperser.BlobStatus_UNKNOWN ||
 		statusRes.Status == disperser.BlobStatus_FAILED {
 		m.logger.Error("EigenDA blob dispersal failed in processing", "requestID", base64RequestID, "err", err)
-		return statusRes.Status, statusRes.Info, fmt.Errorf("eigenDA blob dispersal failed in processing with reply status %d", statusRes.Status)
+		return statusRes.Status, statusRes.Info, fmt.Errorf("%w with reply status %d", ErrDispersalFailed, statusRes.Status)
 	}
 	m.logger.Warn("Still waiting for confirmation from EigenDA", "requestID", base64RequestID)
 	return statusRes.Status, statusRes.Info, nil
 }
+
+func (m *EigenDAClient) HealthCheck(ctx context.Context) error {
+	if m.DisperserCli == nil {
+		return errors.New("eigendDA disperserCli is nil")
+	}
+
+	_, err := m.DisperserCli.GetBlobStatus(ctx, &disperser.BlobStatusRequest{
+		RequestId: healthCheckRequestID,
+	})
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled, codes.Unauthenticated, codes.PermissionDenied:
+		return fmt.Errorf("eigenDA disperser unreachable: %w", err)
+	}
+	return nil
+}
+
+// Close releases the grpc connection to the disperser.
+func (m *EigenDAClient) Close() error {
+	if m.conn == nil {
+		return nil
+	}
+	return m.conn.Close()
+}
@@ -8,34 +8,50 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/holiman/uint256"
 	"math/big"
+	"strconv"
 	"sync/atomic"
+	"time"
 
 	cli_config "github.com/eniac-x-labs/rollup-node/config/cli-config"
 	"github.com/urfave/cli/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/eniac-x-labs/rollup-node/client"
 	eth "github.com/eniac-x-labs/rollup-node/eth-serivce"
+	"github.com/eniac-x-labs/rollup-node/metrics"
 	"github.com/eniac-x-labs/rollup-node/signer"
+	"github.com/eniac-x-labs/rollup-node/tracing"
 )
 
 var ErrAlreadyStopped = errors.New("already stopped")
 
+var tracer = tracing.Tracer("x/eip4844")
+
 type Eip4844Rollup struct {
-	Eip4844Config  CLIConfig
-	Config         *cli_config.CLIConfig
-	l1BeaconClient *eth.L1BeaconClient
-	Log            log.Logger
-	ethClients     client.EthClient
-	Signer         signer.SignerFn
-	From           common.Address
-	stopped        atomic.Bool
-	driverCtx      context.Context
+	Eip4844Config   CLIConfig
+	Config          *cli_config.CLIConfig
+	l1BeaconClient  *eth.L1BeaconClient
+	beaconClient    *eth.BeaconHTTPClient
+	Log             log.Logger
+	Metrics         metrics.RollupMetricer
+	ethClients      client.EthClient
+	Signer          signer.SignerFn
+	From            common.Address
+	stopped         atomic.Bool
+	driverCtx       context.Context
+	cancelDriverCtx context.CancelFunc
+}
+
+func (e *Eip4844Rollup) Start(ctx context.Context) error {
+	return nil
 }
 
 func (e *Eip4844Rollup) Stop(ctx context.Context) error {
@@ -45,6 +61,13 @@ func (e *Eip4844Rollup) Stop(ctx context.Context) error {
 
 	e.Log.Info("Stopping eip4844 rollup service")
 
+	if e.cancelDriverCtx != nil {
+		e.cancelDriverCtx()
+	}
+	if e.ethClients != nil {
+		e.ethClients.Close()
+	}
+
 	e.stopped.Store(true)
 	e.Log.Info("eip4844 rollup service stopped")
 
@@ -76,8 +99,8 @@ func Eip4844ServiceFromCLIConfig(ctx context.Context, cfg *cli_config.CLIConfig,
 
 func NewEip4844WithConfig(ctx context.Context, cfg *cli_config.CLIConfig, config *Eip4844Config) (*Eip4844Rollup, error) {
 	if cfg == nil || config == nil {
-		log.Error("celestia config is nil pointer")
-		return nil, nil
+		log.Error("eip4844 config is nil pointer")
+		return nil, errors.New("eip4844 config is nil pointer")
 	}
 
 	var e Eip4844Rollup
@@ -92,6 +115,7 @@ func (e *Eip4844Rollup) initFromCLIConfig(ctx context.Context, cfg *cli_config.C
 	e.Config = cfg
 	e.Eip4844Config = eip4844Config
 	e.Log = logger
+	e.Metrics = metrics.NoopRollupMetrics
 
 	l1Client, err := client.DialEthClient(ctx, cfg.L1Rpc)
 	if err != nil {
@@ -114,9 +138,12 @@ func (e *Eip4844Rollup) initFromCLIConfig(ctx context.Context, cfg *cli_config.C
 	beaconCfg := eth.L1BeaconClientConfig{
 		FetchAllSidecars: eip4844Config.ShouldFetchAllSidecars,
 	}
-	e.l1BeaconClient = eth.NewL1BeaconClient(eth.NewBeaconHTTPClient(bCl), beaconCfg, fb...)
+	e.beaconClient = eth.NewBeaconHTTPClient(bCl)
+	e.l1BeaconClient = eth.NewL1BeaconClient(e.beaconClient, beaconCfg, fb...)
 
-	e.driverCtx = ctx
+	// The driver context outlives ctx so that submissions in flight on shutdown can finish,
+	// it is cancelled by Stop.
+	e.driverCtx, e.cancelDriverCtx = context.WithCancel(context.Background())
 
 	return nil
 }
@@ -124,12 +151,19 @@ func (e *Eip4844Rollup) initFromCLIConfig(ctx context.Context, cfg *cli_config.C
 // SendTransaction creates & submits a transaction to the batch inbox address with the given `txData`.
 // It currently uses the underlying `txmgr` to handle transaction sending & price management.
 // This is a blocking method. It should not be called concurrently.
-func (e *Eip4844Rollup) SendTransaction(ctx context.Context, data []byte) ([]byte, error) {
+func (e *Eip4844Rollup) SendTransaction(ctx context.Context, data []byte) (_ []byte, err error) {
+	ctx, span := tracer.Start(ctx, "eip4844.SendTransaction", trace.WithAttributes(
+		attribute.Int("data.size", len(data)),
+		attribute.Bool("eip4844.use_blobs", e.Eip4844Config.UseBlobs),
+	))
+	defer func() { tracing.EndSpan(span, err) }()
+	// The signer and the rpc client run on the driver context, only carry the span over to them.
+	driverCtx := trace.ContextWithSpan(e.driverCtx, span)
+
 	// Do the gas estimation offline. A value of 0 will cause the [txmgr] to estimate the gas limit.
 
 	var candidate *eth.TxCandidate
 	if e.Eip4844Config.UseBlobs {
-		var err error
 		if candidate, err = e.blobTxCandidate(data); err != nil {
 			// We could potentially fall through and try a calldata tx instead, but this would
 			// likely result in the chain spending more in gas fees than it is tuned for, so best
@@ -154,21 +188,68 @@ func (e *Eip4844Rollup) SendTransaction(ctx context.Context, data []byte) ([]byt
 		return nil, err
 	}
 
+	_, signSpan := tracer.Start(driverCtx, "eip4844.Sign")
 	signTx, err := e.Signer(e.driverCtx, e.From, tx)
+	tracing.EndSpan(signSpan, err)
 	if err != nil {
 		e.Log.Error("Failed to sign a transaction", "err", err)
 		return nil, err
 	}
+	span.SetAttributes(attribute.String("eip4844.tx_hash", signTx.Hash().Hex()))
 
-	err = e.ethClients.SendTransaction(e.driverCtx, signTx)
+	err = e.ethClients.SendTransaction(driverCtx, signTx)
 	if err != nil {
 		e.Log.Error("Failed to send transaction", "err", err)
 		return nil, err
 	}
+	if len(signTx.BlobHashes()) > 0 {
+		e.Metrics.RecordBlobFee(signTx.BlobGasFeeCap(), len(signTx.BlobHashes()))
+	}
 
 	return signTx.Hash().Bytes(), nil
 }
 
+// EstimateCost returns the fee in wei of sending size bytes at the fees of the latest block: the
+// execution gas at the base fee plus the suggested tip, and the blob gas at the blob base fee.
+func (e *Eip4844Rollup) EstimateCost(ctx context.Context, size int) (_ *big.Int, _ map[string]string, err error) {
+	ctx, span := tracer.Start(ctx, "eip4844.EstimateCost", trace.WithAttributes(attribute.Int("data.size", size)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	header, err := e.ethClients.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch the latest header: %w", err)
+	}
+	tip, err := e.ethClients.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch the suggested gas tip cap: %w", err)
+	}
+	gasPrice := new(big.Int).Add(header.BaseFee, tip)
+	detail := map[string]string{
+		"base_fee": header.BaseFee.String(),
+		"tip":      tip.String(),
+	}
+
+	if !e.Eip4844Config.UseBlobs {
+		// every byte is priced as non zero calldata
+		gas := params.TxGas + params.TxDataNonZeroGasEIP2028*uint64(size)
+		detail["gas"] = strconv.FormatUint(gas, 10)
+		return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas)), detail, nil
+	}
+	if size > eth.MaxBlobDataSize {
+		return nil, nil, fmt.Errorf("%d bytes don't fit in a blob of %d bytes", size, eth.MaxBlobDataSize)
+	}
+	if header.ExcessBlobGas == nil {
+		return nil, nil, errors.New("the latest block has no blob gas, cancun is not active")
+	}
+	blobFee := eip4844.CalcBlobFee(*header.ExcessBlobGas)
+	cost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(params.TxGas))
+	cost.Add(cost, new(big.Int).Mul(blobFee, big.NewInt(params.BlobTxBlobGasPerBlob)))
+	detail["gas"] = strconv.FormatUint(params.TxGas, 10)
+	detail["blob_base_fee"] = blobFee.String()
+	detail["blob_gas"] = strconv.Itoa(params.BlobTxBlobGasPerBlob)
+	return cost, detail, nil
+}
+
 func (e *Eip4844Rollup) blobTxCandidate(data []byte) (*eth.TxCandidate, error) {
 	var b eth.Blob
 	if err := b.FromData(data); err != nil {
@@ -189,20 +270,29 @@ func (e *Eip4844Rollup) calldataTxCandidate(data []byte) *eth.TxCandidate {
 }
 
 func (e *Eip4844Rollup) DataFromEVMTransactions(ctx context.Context, txHashStr string) (data eth.Data, err error) {
+	data, _, err = e.DataAndTimeFromEVMTransaction(ctx, txHashStr)
+	return data, err
+}
+
+// DataAndTimeFromEVMTransaction returns the blob data of the transaction and the time of the block
+// including it, which starts the retention window of the blob.
+func (e *Eip4844Rollup) DataAndTimeFromEVMTransaction(ctx context.Context, txHashStr string) (data eth.Data, included time.Time, err error) {
+	ctx, span := tracer.Start(ctx, "eip4844.DataFromEVMTransactions", trace.WithAttributes(attribute.String("eip4844.tx_hash", txHashStr)))
+	defer func() { tracing.EndSpan(span, err) }()
 	var datas []eth.Data
 	var txs types.Transactions
 
 	tx, header, err := e.getTransactionAndBlockByTxHash(ctx, txHashStr)
 	if err != nil {
 		log.Error("failed to get transaction and block by tx hash", "tx_hash", txHashStr, "err", err)
-		return nil, err
+		return nil, time.Time{}, err
 	}
 	txs = append(txs, tx)
 
 	_, hashes := dataAndHashesFromTxs(txs, e.Eip4844Config.DSConfig, e.Log)
 	if len(hashes) == 0 {
 		// there are no blobs to fetch so we can return immediately
-		return nil, fmt.Errorf("this transaction has no blob data, tx_hash=%s", txHashStr)
+		return nil, time.Time{}, fmt.Errorf("this transaction has no blob data, tx_hash=%s", txHashStr)
 	}
 
 	ref := eth.L1BlockRef{
@@ -212,26 +302,28 @@ func (e *Eip4844Rollup) DataFromEVMTransactions(ctx context.Context, txHashStr s
 		Time:       header.Time,
 	}
 
-	blobs, err := e.l1BeaconClient.GetBlobs(e.driverCtx, ref, hashes)
+	start := time.Now()
+	blobs, err := e.l1BeaconClient.GetBlobs(trace.ContextWithSpan(e.driverCtx, span), ref, hashes)
+	e.Metrics.RecordBeaconFetch(time.Since(start), err)
 	if errors.Is(err, ethereum.NotFound) {
 		// If the L1 block was available, then the blobs should be available too. The only
 		// exception is if the blob retention window has expired, which we will ultimately handle
 		// by failing over to a blob archival service.
-		return nil, fmt.Errorf("failed to fetch blobs: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to fetch blobs: %w", err)
 	} else if err != nil {
-		return nil, fmt.Errorf("failed to fetch blobs: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to fetch blobs: %w", err)
 	}
 
 	for _, blob := range blobs {
 		data, err := blob.ToData()
 		if err != nil {
-			return nil, fmt.Errorf("decodes the blob into raw byte data failed: %w", err)
+			return nil, time.Time{}, fmt.Errorf("decodes the blob into raw byte data failed: %w", err)
 		}
 
 		datas = append(datas, data)
 	}
 
-	return datas[0], nil
+	return datas[0], time.Unix(int64(header.Time), 0), nil
 }
 
 func (e *Eip4844Rollup) craftTx(ctx context.Context, candidate eth.TxCandidate) (*types.Transaction, error) {
@@ -246,7 +338,10 @@ func (e *Eip4844Rollup) craftTx(ctx context.Context, candidate eth.TxCandidate)
 		if candidate.To == nil {
 			return nil, errors.New("blob txs cannot deploy contracts")
 		}
-		if sidecar, blobHashes, err = MakeSidecar(candidate.Blobs); err != nil {
+		_, kzgSpan := tracer.Start(ctx, "eip4844.MakeSidecar", trace.WithAttributes(attribute.Int("eip4844.blobs", len(candidate.Blobs))))
+		sidecar, blobHashes, err = MakeSidecar(candidate.Blobs)
+		tracing.EndSpan(kzgSpan, err)
+		if err != nil {
 			return nil, fmt.Errorf("failed to make sidecar: %w", err)
 		}
 	}
@@ -339,6 +434,50 @@ func (e *Eip4844Rollup) getTransactionAndBlockByTxHash(ctx context.Context, txHa
 	return tx, header, nil
 }
 
+// TxReceipt returns the receipt of the transaction, nil while it is pending.
+func (e *Eip4844Rollup) TxReceipt(ctx context.Context, txHashStr string) (_ *types.Receipt, err error) {
+	_, span := tracer.Start(ctx, "eip4844.TxReceipt", trace.WithAttributes(attribute.String("eip4844.tx_hash", txHashStr)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	txHash := common.HexToHash(txHashStr)
+	if _, err := e.ethClients.TxByHash(txHash); err != nil {
+		return nil, fmt.Errorf("failed to get transaction %s: %w", txHashStr, err)
+	}
+	receipt, err := e.ethClients.TxReceiptDetailByHash(txHash)
+	if errors.Is(err, ethereum.NotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get transaction receipt %s: %w", txHashStr, err)
+	}
+	return receipt, nil
+}
+
+// HealthCheck reports whether the l1 rpc is reachable, the batcher account can pay for
+// transactions and the beacon node used to fetch blobs has finished syncing.
+func (e *Eip4844Rollup) HealthCheck(ctx context.Context) error {
+	if _, err := e.ethClients.HeaderByNumber(ctx, nil); err != nil {
+		return fmt.Errorf("failed to get l1 latest header: %w", err)
+	}
+
+	balance, err := e.ethClients.GetBalanceByBlockNumber(e.From.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to get batcher balance: %w", err)
+	}
+	if balance.Sign() <= 0 {
+		return fmt.Errorf("batcher %s balance is zero", e.From.String())
+	}
+
+	syncing, err := e.beaconClient.NodeSyncing(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get beacon node syncing status: %w", err)
+	}
+	if syncing.IsSyncing {
+		return fmt.Errorf("beacon node is syncing, sync distance: %d", syncing.SyncDistance)
+	}
+
+	return nil
+}
+
 func calcGasFeeCap(baseFee, gasTipCap *big.Int) *big.Int {
 	return new(big.Int).Add(
 		gasTipCap,
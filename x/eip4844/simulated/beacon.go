@@ -0,0 +1,87 @@
+package simulated
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	eth "github.com/eniac-x-labs/rollup-node/eth-serivce"
+)
+
+// beaconHandler serves the beacon API endpoints eth.BeaconHTTPClient calls.
+func (c *Chain) beaconHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /eth/v1/node/version", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, eth.APIVersionResponse{Data: eth.VersionInformation{Version: "simulated/v1.0.0"}})
+	})
+	mux.HandleFunc("GET /eth/v1/node/syncing", func(w http.ResponseWriter, _ *http.Request) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		info := eth.SyncingInformation{HeadSlot: eth.Uint64String(c.head().header.Number.Uint64()), IsSyncing: c.syncing}
+		if c.syncing {
+			info.SyncDistance = 1
+		}
+		writeJSON(w, eth.APISyncingResponse{Data: info})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/genesis", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, eth.APIGenesisResponse{Data: eth.ReducedGenesisData{GenesisTime: eth.Uint64String(c.conf.GenesisTime)}})
+	})
+	mux.HandleFunc("GET /eth/v1/config/spec", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, eth.APIConfigResponse{Data: eth.ReducedConfigData{SecondsPerSlot: eth.Uint64String(c.conf.SecondsPerSlot)}})
+	})
+	mux.HandleFunc("GET /eth/v1/beacon/blob_sidecars/{slot}", c.serveBlobSidecars)
+	return mux
+}
+
+// serveBlobSidecars serves the sidecars of the block at the slot, only the ones of the indices
+// query when given.
+func (c *Chain) serveBlobSidecars(w http.ResponseWriter, r *http.Request) {
+	slot, err := strconv.ParseUint(r.PathValue("slot"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid block id %q", r.PathValue("slot")))
+		return
+	}
+	var indices map[uint64]bool
+	if values := r.URL.Query()["indices"]; len(values) > 0 {
+		indices = make(map[uint64]bool, len(values))
+		for _, v := range values {
+			i, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid index %q", v))
+				return
+			}
+			indices[i] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.blockAtSlot(slot)
+	if b == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("block not found for slot %d", slot))
+		return
+	}
+	sidecars := make([]*eth.APIBlobSidecar, 0, len(b.sidecars))
+	for _, sidecar := range b.sidecars {
+		if indices == nil || indices[uint64(sidecar.Index)] {
+			sidecars = append(sidecars, sidecar)
+		}
+	}
+	writeJSON(w, eth.APIGetBlobSidecarsResponse{Data: sidecars})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError answers with the error format of the beacon API.
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}{code, message})
+}
@@ -0,0 +1,264 @@
+// Package simulated runs an L1 for the EIP-4844 tests without any external service: an execution
+// JSON-RPC endpoint accepting blob transactions, and a beacon API serving the sidecars of the blobs
+// included in its blocks. Both are HTTP servers on the loopback, so Eip4844Rollup dials them unchanged:
+//
+//	chain := simulated.New(simulated.Config{Balances: map[common.Address]*big.Int{batcher: ether}, AutoMine: true})
+//	defer chain.Close()
+//	cliCfg := &cli_config.CLIConfig{L1Rpc: chain.RPCURL(), L1ChainID: chain.ChainID(), PrivateKey: key}
+//
+// A block is produced in every slot Mine is called for, its time is the one of the slot, so the
+// beacon client maps it back to the slot its sidecars are served at.
+package simulated
+
+import (
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	eth "github.com/eniac-x-labs/rollup-node/eth-serivce"
+)
+
+type Config struct {
+	// ChainID defaults to 1337
+	ChainID *big.Int
+	// GenesisTime is the unix time of slot 0, the current time when 0
+	GenesisTime uint64
+	// SecondsPerSlot defaults to 12
+	SecondsPerSlot uint64
+	// Balances funds the accounts at genesis
+	Balances map[common.Address]*big.Int
+	// BaseFee and GasTipCap are the fees of every block, 1 gwei when nil
+	BaseFee   *big.Int
+	GasTipCap *big.Int
+	// AutoMine includes every transaction in a block of its own when it is sent, otherwise they are
+	// pending until Mine is called
+	AutoMine bool
+	// SidecarRetention is the number of slots the beacon serves sidecars for, 0 keeps them all
+	SidecarRetention uint64
+}
+
+type block struct {
+	header   *types.Header
+	txs      []*types.Transaction
+	receipts []*types.Receipt
+	sidecars []*eth.APIBlobSidecar
+}
+
+type txLookup struct {
+	block *block
+	index int
+}
+
+// Chain is the simulated L1, it serves requests once New returns until Close.
+type Chain struct {
+	conf   Config
+	signer types.Signer
+
+	mu       sync.Mutex
+	blocks   []*block
+	txs      map[common.Hash]txLookup
+	pending  []*types.Transaction
+	balances map[common.Address]*big.Int
+	nonces   map[common.Address]uint64
+	// pendingNonces counts the pending transactions of an account on top of its nonce
+	pendingNonces map[common.Address]uint64
+	syncing       bool
+
+	rpc    *rpc.Server
+	l1     *httptest.Server
+	beacon *httptest.Server
+}
+
+func New(conf Config) *Chain {
+	if conf.ChainID == nil {
+		conf.ChainID = big.NewInt(1337)
+	}
+	if conf.GenesisTime == 0 {
+		conf.GenesisTime = uint64(time.Now().Unix())
+	}
+	if conf.SecondsPerSlot == 0 {
+		conf.SecondsPerSlot = 12
+	}
+	if conf.BaseFee == nil {
+		conf.BaseFee = big.NewInt(params.GWei)
+	}
+	if conf.GasTipCap == nil {
+		conf.GasTipCap = big.NewInt(params.GWei)
+	}
+	c := &Chain{
+		conf:          conf,
+		signer:        types.NewCancunSigner(conf.ChainID),
+		txs:           make(map[common.Hash]txLookup),
+		balances:      make(map[common.Address]*big.Int, len(conf.Balances)),
+		nonces:        make(map[common.Address]uint64),
+		pendingNonces: make(map[common.Address]uint64),
+	}
+	for addr, balance := range conf.Balances {
+		c.balances[addr] = new(big.Int).Set(balance)
+	}
+	zero := uint64(0)
+	c.blocks = []*block{{header: &types.Header{
+		Number:        new(big.Int),
+		Time:          conf.GenesisTime,
+		GasLimit:      30_000_000,
+		BaseFee:       conf.BaseFee,
+		Difficulty:    new(big.Int),
+		ExcessBlobGas: &zero,
+		BlobGasUsed:   &zero,
+	}}}
+
+	c.rpc = rpc.NewServer()
+	if err := c.rpc.RegisterName("eth", &ethAPI{c}); err != nil {
+		panic(err)
+	}
+	c.l1 = httptest.NewServer(c.rpc)
+	c.beacon = httptest.NewServer(c.beaconHandler())
+	return c
+}
+
+// RPCURL is the url of the execution JSON-RPC endpoint.
+func (c *Chain) RPCURL() string {
+	return c.l1.URL
+}
+
+// BeaconURL is the url of the beacon API.
+func (c *Chain) BeaconURL() string {
+	return c.beacon.URL
+}
+
+func (c *Chain) ChainID() *big.Int {
+	return new(big.Int).Set(c.conf.ChainID)
+}
+
+// SetSyncing makes the beacon node report that it is syncing.
+func (c *Chain) SetSyncing(syncing bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncing = syncing
+}
+
+func (c *Chain) Close() {
+	c.l1.Close()
+	c.beacon.Close()
+	c.rpc.Stop()
+}
+
+// Mine includes the pending transactions in a new block at the next slot and returns its header.
+func (c *Chain) Mine() *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mine()
+}
+
+// Head returns the header of the latest block.
+func (c *Chain) Head() *types.Header {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return types.CopyHeader(c.head().header)
+}
+
+func (c *Chain) head() *block {
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *Chain) mine() *types.Header {
+	parent := c.head().header
+	number := new(big.Int).Add(parent.Number, common.Big1)
+	var blobGasUsed uint64
+	for _, tx := range c.pending {
+		blobGasUsed += tx.BlobGas()
+	}
+	excessBlobGas := eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+	header := &types.Header{
+		ParentHash:    parent.Hash(),
+		Number:        number,
+		Time:          c.conf.GenesisTime + number.Uint64()*c.conf.SecondsPerSlot,
+		GasLimit:      parent.GasLimit,
+		BaseFee:       c.conf.BaseFee,
+		Difficulty:    new(big.Int),
+		ExcessBlobGas: &excessBlobGas,
+		BlobGasUsed:   &blobGasUsed,
+		TxHash:        types.DeriveSha(types.Transactions(c.pending), trie.NewStackTrie(nil)),
+	}
+	b := &block{header: header}
+	blockHash := header.Hash()
+	blobGasPrice := eip4844.CalcBlobFee(excessBlobGas)
+
+	var cumulativeGas uint64
+	for i, tx := range c.pending {
+		cumulativeGas += tx.Gas()
+		receipt := &types.Receipt{
+			Type:              tx.Type(),
+			Status:            types.ReceiptStatusSuccessful,
+			CumulativeGasUsed: cumulativeGas,
+			Logs:              []*types.Log{},
+			TxHash:            tx.Hash(),
+			GasUsed:           tx.Gas(),
+			EffectiveGasPrice: new(big.Int).Add(c.conf.BaseFee, tx.EffectiveGasTipValue(c.conf.BaseFee)),
+			BlockHash:         blockHash,
+			BlockNumber:       number,
+			TransactionIndex:  uint(i),
+		}
+		if tx.Type() == types.BlobTxType {
+			receipt.BlobGasUsed = tx.BlobGas()
+			receipt.BlobGasPrice = blobGasPrice
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		if sidecar := tx.BlobTxSidecar(); sidecar != nil {
+			for j := range sidecar.Blobs {
+				b.sidecars = append(b.sidecars, &eth.APIBlobSidecar{
+					Index:         eth.Uint64String(len(b.sidecars)),
+					Blob:          eth.Blob(sidecar.Blobs[j]),
+					KZGCommitment: eth.Bytes48(sidecar.Commitments[j]),
+					KZGProof:      eth.Bytes48(sidecar.Proofs[j]),
+					SignedBlockHeader: eth.SignedBeaconBlockHeader{Message: eth.BeaconBlockHeader{
+						Slot:       eth.Uint64String(number.Uint64()),
+						ParentRoot: eth.Bytes32(parent.Hash()),
+						BodyRoot:   eth.Bytes32(blockHash),
+					}},
+				})
+			}
+		}
+		// the execution layer only keeps the transaction, the beacon its blobs
+		tx = tx.WithoutBlobTxSidecar()
+		b.txs = append(b.txs, tx)
+		b.receipts = append(b.receipts, receipt)
+		c.txs[tx.Hash()] = txLookup{block: b, index: i}
+
+		from, _ := types.Sender(c.signer, tx)
+		c.nonces[from]++
+		c.pendingNonces[from]--
+	}
+	c.pending = nil
+	c.blocks = append(c.blocks, b)
+	return types.CopyHeader(header)
+}
+
+// blockByNumber returns the block of number, the latest for a negative number such as rpc.LatestBlockNumber.
+func (c *Chain) blockByNumber(number rpc.BlockNumber) *block {
+	if number < 0 {
+		return c.head()
+	}
+	if int(number) >= len(c.blocks) {
+		return nil
+	}
+	return c.blocks[number]
+}
+
+// blockAtSlot returns the block of slot, nil for a slot without a block or whose sidecars are pruned.
+func (c *Chain) blockAtSlot(slot uint64) *block {
+	head := uint64(len(c.blocks) - 1)
+	if slot > head || (c.conf.SidecarRetention > 0 && head-slot >= c.conf.SidecarRetention) {
+		return nil
+	}
+	return c.blocks[slot]
+}
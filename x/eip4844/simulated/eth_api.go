@@ -0,0 +1,169 @@
+package simulated
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ethAPI serves the eth namespace, the methods client.EthClient calls.
+type ethAPI struct {
+	c *Chain
+}
+
+func (api *ethAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(api.c.ChainID())
+}
+
+func (api *ethAPI) BlockNumber() hexutil.Uint64 {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	return hexutil.Uint64(api.c.head().header.Number.Uint64())
+}
+
+// GetBlockByNumber returns the header of the block, the transactions are never included.
+func (api *ethAPI) GetBlockByNumber(number rpc.BlockNumber, _ bool) *types.Header {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	b := api.c.blockByNumber(number)
+	if b == nil {
+		return nil
+	}
+	return types.CopyHeader(b.header)
+}
+
+func (api *ethAPI) GetBalance(addr common.Address, _ rpc.BlockNumberOrHash) *hexutil.Big {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	balance := new(big.Int)
+	if b, ok := api.c.balances[addr]; ok {
+		balance.Set(b)
+	}
+	return (*hexutil.Big)(balance)
+}
+
+// GetTransactionCount returns the nonce of addr at the latest block, including its pending
+// transactions for the pending block.
+func (api *ethAPI) GetTransactionCount(addr common.Address, number rpc.BlockNumberOrHash) hexutil.Uint64 {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	nonce := api.c.nonces[addr]
+	if n, ok := number.Number(); ok && n == rpc.PendingBlockNumber {
+		nonce += api.c.pendingNonces[addr]
+	}
+	return hexutil.Uint64(nonce)
+}
+
+func (api *ethAPI) MaxPriorityFeePerGas() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Set(api.c.conf.GasTipCap))
+}
+
+func (api *ethAPI) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(new(big.Int).Add(api.c.conf.BaseFee, api.c.conf.GasTipCap))
+}
+
+// GetTransactionByHash returns an included or pending transaction, without its blobs.
+func (api *ethAPI) GetTransactionByHash(hash common.Hash) *types.Transaction {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	if lookup, ok := api.c.txs[hash]; ok {
+		return lookup.block.txs[lookup.index]
+	}
+	for _, tx := range api.c.pending {
+		if tx.Hash() == hash {
+			return tx.WithoutBlobTxSidecar()
+		}
+	}
+	return nil
+}
+
+// GetTransactionReceipt returns nil while the transaction is pending.
+func (api *ethAPI) GetTransactionReceipt(hash common.Hash) *types.Receipt {
+	api.c.mu.Lock()
+	defer api.c.mu.Unlock()
+	lookup, ok := api.c.txs[hash]
+	if !ok {
+		return nil
+	}
+	return lookup.block.receipts[lookup.index]
+}
+
+// SendRawTransaction accepts a transaction checked as the txpool of a node does, a blob transaction
+// must carry its sidecar.
+func (api *ethAPI) SendRawTransaction(_ context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	c := api.c
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.validate(tx); err != nil {
+		return common.Hash{}, err
+	}
+	from, _ := types.Sender(c.signer, tx)
+	c.balances[from] = new(big.Int).Sub(c.balances[from], tx.Cost())
+	c.pendingNonces[from]++
+	c.pending = append(c.pending, tx)
+	if c.conf.AutoMine {
+		c.mine()
+	}
+	return tx.Hash(), nil
+}
+
+func (c *Chain) validate(tx *types.Transaction) error {
+	if tx.ChainId().Cmp(c.conf.ChainID) != 0 {
+		return fmt.Errorf("invalid chain id %d, expected %d", tx.ChainId(), c.conf.ChainID)
+	}
+	from, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %w", err)
+	}
+	if _, ok := c.txs[tx.Hash()]; ok {
+		return errors.New("already known")
+	}
+	switch nonce := c.nonces[from] + c.pendingNonces[from]; {
+	case tx.Nonce() < nonce:
+		return fmt.Errorf("nonce too low: address %s, tx: %d state: %d", from, tx.Nonce(), nonce)
+	case tx.Nonce() > nonce:
+		return fmt.Errorf("nonce too high: address %s, tx: %d state: %d", from, tx.Nonce(), nonce)
+	}
+	if balance, ok := c.balances[from]; !ok || balance.Cmp(tx.Cost()) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value: address %s", from)
+	}
+	if tx.GasFeeCapIntCmp(c.conf.BaseFee) < 0 {
+		return fmt.Errorf("max fee per gas less than block base fee: address %s, maxFeePerGas: %s, baseFee: %s", from, tx.GasFeeCap(), c.conf.BaseFee)
+	}
+	if tx.Type() != types.BlobTxType {
+		return nil
+	}
+
+	if blobFee := eip4844.CalcBlobFee(*c.head().header.ExcessBlobGas); tx.BlobGasFeeCapIntCmp(blobFee) < 0 {
+		return fmt.Errorf("max fee per blob gas less than block blob gas fee: address %s, maxFeePerBlobGas: %s, blobBaseFee: %s", from, tx.BlobGasFeeCap(), blobFee)
+	}
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil {
+		return errors.New("missing sidecar in blob transaction")
+	}
+	hashes := tx.BlobHashes()
+	if len(hashes) == 0 || len(sidecar.Blobs) != len(hashes) || len(sidecar.Commitments) != len(hashes) || len(sidecar.Proofs) != len(hashes) {
+		return fmt.Errorf("invalid number of %d blobs compared to %d blob hashes", len(sidecar.Blobs), len(hashes))
+	}
+	for i, hash := range sidecar.BlobHashes() {
+		if hash != hashes[i] {
+			return fmt.Errorf("blob %d: computed hash %s mismatches transaction one %s", i, hash, hashes[i])
+		}
+		if err := kzg4844.VerifyBlobProof(sidecar.Blobs[i], sidecar.Commitments[i], sidecar.Proofs[i]); err != nil {
+			return fmt.Errorf("invalid blob %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package eip4844
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	cli_config "github.com/eniac-x-labs/rollup-node/config/cli-config"
+	eth "github.com/eniac-x-labs/rollup-node/eth-serivce"
+	"github.com/eniac-x-labs/rollup-node/x/eip4844/simulated"
+)
+
+var simulatedInbox = common.HexToAddress("0x4F34C922fB0D80c7d79Ac25e497d90d7efa513C2")
+
+func newSimulatedRollup(t *testing.T, conf simulated.Config) (*Eip4844Rollup, *simulated.Chain) {
+	ctx := context.Background()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	batcher := crypto.PubkeyToAddress(key.PublicKey)
+
+	conf.Balances = map[common.Address]*big.Int{batcher: big.NewInt(params.Ether)}
+	chain := simulated.New(conf)
+	t.Cleanup(chain.Close)
+
+	eip4844Cfg, err := ProcessEip4844Config(&ParseEip4844Config{
+		UseBlobs:          true,
+		L1BeaconAddr:      chain.BeaconURL(),
+		BatchInboxAddress: simulatedInbox.Hex(),
+		BatcherAddr:       batcher.Hex(),
+		L1ChainIdFlagName: chain.ChainID().Uint64(),
+	}, log.Root())
+	require.NoError(t, err)
+	e, err := NewEip4844WithConfig(ctx, &cli_config.CLIConfig{
+		L1Rpc:      chain.RPCURL(),
+		L1ChainID:  chain.ChainID(),
+		PrivateKey: hex.EncodeToString(crypto.FromECDSA(key)),
+	}, eip4844Cfg)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = e.Stop(ctx) })
+	return e, chain
+}
+
+func TestSimulatedRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newSimulatedRollup(t, simulated.Config{AutoMine: true})
+	require.NoError(t, e.HealthCheck(ctx))
+
+	for _, data := range [][]byte{[]byte("hello dappLink"), []byte("second batch")} {
+		txHash, err := e.SendTransaction(ctx, data)
+		require.NoError(t, err)
+		txHashStr := common.BytesToHash(txHash).Hex()
+
+		receipt, err := e.TxReceipt(ctx, txHashStr)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+		require.NotZero(t, receipt.BlobGasUsed)
+
+		got, err := e.DataFromEVMTransactions(ctx, txHashStr)
+		require.NoError(t, err)
+		require.Equal(t, data, []byte(got))
+	}
+}
+
+func TestSimulatedPendingAndPruned(t *testing.T) {
+	ctx := context.Background()
+	e, chain := newSimulatedRollup(t, simulated.Config{SidecarRetention: 2})
+
+	txHash, err := e.SendTransaction(ctx, []byte("pending batch"))
+	require.NoError(t, err)
+	txHashStr := common.BytesToHash(txHash).Hex()
+	receipt, err := e.TxReceipt(ctx, txHashStr)
+	require.NoError(t, err)
+	require.Nil(t, receipt)
+	_, err = e.DataFromEVMTransactions(ctx, txHashStr)
+	require.Error(t, err)
+
+	chain.Mine()
+	receipt, err = e.TxReceipt(ctx, txHashStr)
+	require.NoError(t, err)
+	require.NotNil(t, receipt)
+	got, err := e.DataFromEVMTransactions(ctx, txHashStr)
+	require.NoError(t, err)
+	require.Equal(t, []byte("pending batch"), []byte(got))
+
+	// the beacon node prunes the sidecars past the retention
+	chain.Mine()
+	chain.Mine()
+	_, err = e.DataFromEVMTransactions(ctx, txHashStr)
+	require.ErrorContains(t, err, "404")
+}
+
+func TestSimulatedHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	e, chain := newSimulatedRollup(t, simulated.Config{AutoMine: true})
+	require.NoError(t, e.HealthCheck(ctx))
+
+	chain.SetSyncing(true)
+	require.ErrorContains(t, e.HealthCheck(ctx), "syncing")
+	chain.SetSyncing(false)
+	require.NoError(t, e.HealthCheck(ctx))
+}
+
+func TestSimulatedEstimateCost(t *testing.T) {
+	ctx := context.Background()
+	e, _ := newSimulatedRollup(t, simulated.Config{
+		AutoMine:  true,
+		BaseFee:   big.NewInt(10 * params.GWei),
+		GasTipCap: big.NewInt(2 * params.GWei),
+	})
+
+	// the blob base fee is at its minimum of 1 wei without excess blob gas
+	cost, detail, err := e.EstimateCost(ctx, 1000)
+	require.NoError(t, err)
+	want := new(big.Int).Mul(big.NewInt(12*params.GWei), big.NewInt(int64(params.TxGas)))
+	want.Add(want, big.NewInt(params.BlobTxBlobGasPerBlob))
+	require.Equal(t, want, cost)
+	require.Equal(t, "1", detail["blob_base_fee"])
+
+	_, _, err = e.EstimateCost(ctx, eth.MaxBlobDataSize+1)
+	require.Error(t, err)
+
+	e.Eip4844Config.UseBlobs = false
+	cost, _, err = e.EstimateCost(ctx, 1000)
+	require.NoError(t, err)
+	require.Equal(t, new(big.Int).Mul(big.NewInt(12*params.GWei), big.NewInt(int64(params.TxGas+16*1000))), cost)
+}
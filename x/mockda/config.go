@@ -0,0 +1,112 @@
+package mockda
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+)
+
+const (
+	StoreMemory  = "memory"
+	StoreLocalFS = "localfs"
+)
+
+// Failure modes of the requests picked by FailureRate.
+const (
+	// FailError fails the request right away
+	FailError = "error"
+	// FailTimeout holds the request until its context ends
+	FailTimeout = "timeout"
+	// FailLose reports a submission as stored but drops its data
+	FailLose = "lose"
+	// FailDown fails every request and health check, as an unreachable backend
+	FailDown = "down"
+)
+
+// Config of the mock DA backends standing in for the real ones on a devnet.
+type Config struct {
+	// Store keeps the data in memory, lost on restart, or in files under Dir
+	Store string `mapstructure:"store"`
+	Dir   string `mapstructure:"dir"`
+	// DAs lists the names or da types served by the mock backends, every DA when empty
+	DAs []string `mapstructure:"das"`
+	// Latency delays every request, by up to Jitter more
+	Latency time.Duration `mapstructure:"latency"`
+	Jitter  time.Duration `mapstructure:"jitter"`
+	// FailureRate is the share of the requests failing as FailureMode says, from 0 to 1
+	FailureRate float64 `mapstructure:"failure_rate"`
+	FailureMode string  `mapstructure:"failure_mode"`
+	// Retention is how long data stays retrievable, the retention of the DA mocked when 0
+	Retention time.Duration `mapstructure:"retention"`
+}
+
+func DefaultConfig() Config {
+	return Config{Store: StoreMemory, Dir: "./devnet", FailureMode: FailError}
+}
+
+func (c Config) Check() error {
+	switch c.Store {
+	case StoreMemory:
+	case StoreLocalFS:
+		if len(c.Dir) == 0 {
+			return errors.New("dir is required by the localfs store")
+		}
+	default:
+		return fmt.Errorf("store must be %s or %s, got %q", StoreMemory, StoreLocalFS, c.Store)
+	}
+	if _, err := c.DATypes(); err != nil {
+		return err
+	}
+	if c.Latency < 0 || c.Jitter < 0 || c.Retention < 0 {
+		return errors.New("latency, jitter and retention must not be negative")
+	}
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return fmt.Errorf("failure_rate must be between 0 and 1, got %v", c.FailureRate)
+	}
+	switch c.FailureMode {
+	case FailError, FailTimeout, FailLose, FailDown:
+	default:
+		return fmt.Errorf("failure_mode must be one of %s, %s, %s, %s, got %q", FailError, FailTimeout, FailLose, FailDown, c.FailureMode)
+	}
+	return nil
+}
+
+// DATypes returns the da types served by the mock backends.
+func (c Config) DATypes() ([]int, error) {
+	if len(c.DAs) == 0 {
+		return _common.DATypes, nil
+	}
+	daTypes := make([]int, len(c.DAs))
+	for i, name := range c.DAs {
+		daType, err := _common.ParseDAType(name)
+		if err != nil {
+			return nil, err
+		}
+		if daType == _common.ErasureType {
+			return nil, errors.New("erasure is no DA backend, list the DAs of its shards")
+		}
+		daTypes[i] = daType
+	}
+	return daTypes, nil
+}
+
+// Serves reports whether daType is served by a mock backend.
+func (c Config) Serves(daType int) bool {
+	daTypes, _ := c.DATypes()
+	for _, t := range daTypes {
+		if t == daType {
+			return true
+		}
+	}
+	return false
+}
+
+// retention returns how long the data stored on daType stays retrievable.
+func (c Config) retention(daType int) time.Duration {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+	return _common.DARetention[daType]
+}
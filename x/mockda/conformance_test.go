@@ -0,0 +1,71 @@
+package mockda
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	"github.com/eniac-x-labs/rollup-node/x/conformance"
+)
+
+// backend adapts the mock of a DA to the conformance suite, the receipt is the one core.RetrieveFromDA takes.
+type backend struct {
+	devnet *Devnet
+	da     *DA
+}
+
+func (b *backend) Store(ctx context.Context, data []byte) (interface{}, error) {
+	res, err := b.da.Store(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if b.da.daType == _common.CelestiaType {
+		return res[1], nil
+	}
+	return res[0], nil
+}
+
+func (b *backend) Retrieve(ctx context.Context, receipt interface{}) ([]byte, error) {
+	return b.da.Retrieve(ctx, receipt)
+}
+
+func (b *backend) Close() error {
+	return b.devnet.Close()
+}
+
+// unknownReceipts are well formed receipts of data the mocks never stored.
+var unknownReceipts = map[int]interface{}{
+	_common.AnytrustType:          strings.Repeat("00", 32),
+	_common.AnytrustCommitteeType: strings.Repeat("00", 32),
+	_common.CelestiaType:          "1000000:00000000000000000000000000000000000000000000deadbeef:00",
+	_common.EigenDAType:           base64.StdEncoding.EncodeToString([]byte("deadbeef-00")),
+	_common.Eip4844Type:           "0x" + strings.Repeat("00", 32),
+	_common.NearDAType:            base64.StdEncoding.EncodeToString(make([]byte, 64)),
+}
+
+func TestConformance(t *testing.T) {
+	for _, store := range []string{StoreMemory, StoreLocalFS} {
+		for _, daType := range _common.DATypes {
+			t.Run(store+"/"+_common.DATypeName(daType), func(t *testing.T) {
+				conformance.Run(t, func(t *testing.T) conformance.Backend {
+					conf := DefaultConfig()
+					conf.Store, conf.Dir = store, t.TempDir()
+					d, err := NewDevnet(conf)
+					require.NoError(t, err)
+					t.Cleanup(func() { _ = d.Close() })
+					da, ok := d.DA(daType)
+					require.True(t, ok)
+					return &backend{devnet: d, da: da}
+				}, conformance.Options{
+					MaxSize:        chunk.DefaultChunkSizes[daType],
+					UnknownReceipt: unknownReceipts[daType],
+				})
+			})
+		}
+	}
+}
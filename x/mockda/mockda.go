@@ -0,0 +1,356 @@
+// Package mockda serves the DA backends of a devnet without any external service. Every mock
+// returns receipts in the format of the DA it stands in for, so clients and receipt tools work
+// unchanged, and keeps the data in a Store until the retention of the DA ends. Latency and
+// failures are simulated as configured.
+package mockda
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/celestiaorg/celestia-openrpc/types/share"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	"github.com/eniac-x-labs/rollup-node/common/chunk"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/x/celestia"
+)
+
+var (
+	ErrInjected    = errors.New("mock da: injected failure")
+	ErrUnavailable = errors.New("mock da: backend is down")
+	ErrTooLarge    = errors.New("mock da: payload above the ceiling of the DA")
+)
+
+// Devnet holds the mock backends of the DAs its config lists, they share a store.
+type Devnet struct {
+	conf  Config
+	store Store
+	das   map[int]*DA
+}
+
+func NewDevnet(conf Config) (*Devnet, error) {
+	daTypes, err := conf.DATypes()
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := celestia.NewNamespaces("", nil)
+	if err != nil {
+		return nil, err
+	}
+	store, err := NewStore(conf)
+	if err != nil {
+		return nil, err
+	}
+	d := &Devnet{conf: conf, store: store, das: make(map[int]*DA, len(daTypes))}
+	for _, daType := range daTypes {
+		d.das[daType] = &DA{daType: daType, conf: conf, store: store, namespace: namespaces.Default, now: time.Now}
+	}
+	log.Info("devnet serves mock DA backends", "das", daTypes, "store", conf.Store)
+	return d, nil
+}
+
+// DA returns the mock backend of daType, ok is false when daType isn't mocked.
+func (d *Devnet) DA(daType int) (da *DA, ok bool) {
+	da, ok = d.das[daType]
+	return da, ok
+}
+
+// HealthCheck fails when the backends are configured down.
+func (d *Devnet) HealthCheck(ctx context.Context) error {
+	if d.conf.FailureMode == FailDown {
+		return ErrUnavailable
+	}
+	return ctx.Err()
+}
+
+func (d *Devnet) Close() error {
+	return d.store.Close()
+}
+
+// DA mocks a single DA backend.
+type DA struct {
+	daType    int
+	conf      Config
+	store     Store
+	namespace share.Namespace
+	now       func() time.Time
+
+	// mu serializes the sequence numbers, the celestia heights and the near tx indexes
+	mu sync.Mutex
+}
+
+// HealthCheck fails when the backend is configured down.
+func (d *DA) HealthCheck(ctx context.Context) error {
+	if d.conf.FailureMode == FailDown {
+		return ErrUnavailable
+	}
+	return ctx.Err()
+}
+
+// Store stores data and returns the receipts the mocked DA would, see core.RollupWithType.
+func (d *DA) Store(ctx context.Context, data []byte) ([]interface{}, error) {
+	if max := chunk.DefaultChunkSizes[d.daType]; len(data) > max {
+		return nil, fmt.Errorf("%w: %d bytes, at most %d", ErrTooLarge, len(data), max)
+	}
+	lose, err := d.simulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expiry := d.now().Add(d.conf.retention(d.daType))
+	var (
+		key string
+		res []interface{}
+	)
+	switch d.daType {
+	case _common.AnytrustType, _common.AnytrustCommitteeType:
+		hash := crypto.Keccak256(data)
+		key = hex.EncodeToString(hash)
+		res = []interface{}{key, base64.StdEncoding.EncodeToString(anytrustCert(hash, expiry))}
+	case _common.CelestiaType:
+		namespace, err := d.resolveNamespace(ctx)
+		if err != nil {
+			return nil, err
+		}
+		height, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		commitment := sha256.Sum256(data)
+		receipt := &celestia.Receipt{Height: height, Namespace: namespace, Commitment: commitment[:]}
+		key = celestiaKey(height, namespace)
+		res = []interface{}{height, receipt.String()}
+	case _common.EigenDAType:
+		blobHash := sha256.Sum256(data)
+		// "<requested at ns>/<quorum>/<adversary threshold>/" followed by its sha256, as the disperser does
+		metadata := []byte(fmt.Sprintf("%d/0/33/", d.now().UnixNano()))
+		metadataHash := sha256.Sum256(metadata)
+		key = hex.EncodeToString(blobHash[:]) + "-" + hex.EncodeToString(append(metadata, metadataHash[:]...))
+		res = []interface{}{base64.StdEncoding.EncodeToString([]byte(key))}
+	case _common.Eip4844Type:
+		seq, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		txHash := crypto.Keccak256(binary.BigEndian.AppendUint64(nil, seq), data)
+		key = hex.EncodeToString(txHash)
+		res = []interface{}{"0x" + key}
+	case _common.NearDAType:
+		seq, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+		// the tx id starts with the tx index, followed by the commitment
+		frameRef := crypto.Keccak256(binary.BigEndian.AppendUint64(nil, seq), data)
+		binary.BigEndian.PutUint32(frameRef, uint32(seq))
+		commitment := sha256.Sum256(data)
+		frameRef = append(frameRef, commitment[:]...)
+		key = hex.EncodeToString(frameRef)
+		res = []interface{}{base64.StdEncoding.EncodeToString(frameRef)}
+	default:
+		return nil, _errors.UnknownDATypeErr
+	}
+	if lose {
+		log.Warn("mock da dropped the stored data", "da-type", _common.DATypeName(d.daType), "key", key)
+		return res, nil
+	}
+	if err := d.store.Put(d.storeKey(key), data, expiry); err != nil {
+		return nil, err
+	}
+	log.Debug("mock da stored data", "da-type", _common.DATypeName(d.daType), "key", key, "size", len(data))
+	return res, nil
+}
+
+// Retrieve returns the data of a receipt returned by Store, accepting the same args as the mocked DA.
+func (d *DA) Retrieve(ctx context.Context, args interface{}) ([]byte, error) {
+	if _, err := d.simulate(ctx); err != nil {
+		return nil, err
+	}
+	return d.get(args)
+}
+
+// Status reports the submissions of the DAs confirming them asynchronously as final, the mocks store
+// data before returning the receipt.
+func (d *DA) Status(ctx context.Context, args interface{}) (*_common.SubmissionStatus, error) {
+	if d.daType != _common.EigenDAType && d.daType != _common.Eip4844Type {
+		return nil, _errors.StatusNotTrackedErr
+	}
+	if _, err := d.simulate(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := d.get(args); err != nil {
+		return nil, err
+	}
+	status := "finalized"
+	if d.daType == _common.Eip4844Type {
+		status = "included"
+	}
+	return &_common.SubmissionStatus{DAType: d.daType, Status: status, Final: true}, nil
+}
+
+func (d *DA) get(args interface{}) ([]byte, error) {
+	key, check, err := d.retrieveKey(args)
+	if err != nil {
+		return nil, err
+	}
+	data, expiry, err := d.store.Get(d.storeKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if !d.now().Before(expiry) {
+		return nil, ErrExpired
+	}
+	if check != nil && !check(data) {
+		return nil, ErrNotFound
+	}
+	return data, nil
+}
+
+// retrieveKey returns the key of the data args locates, and a check of the data when the key
+// doesn't identify it alone.
+func (d *DA) retrieveKey(args interface{}) (string, func(data []byte) bool, error) {
+	if d.daType == _common.CelestiaType {
+		receipt := &celestia.Receipt{Namespace: d.namespace}
+		switch arg := args.(type) {
+		case uint64:
+			// a bare height is stored under the default namespace
+			receipt.Height = arg
+		case string:
+			var err error
+			if receipt, err = celestia.ParseReceipt(arg); err != nil {
+				return "", nil, err
+			}
+		default:
+			return "", nil, _errors.WrongArgTypeErr
+		}
+		var check func(data []byte) bool
+		if len(receipt.Commitment) != 0 {
+			check = func(data []byte) bool {
+				commitment := sha256.Sum256(data)
+				return bytes.Equal(commitment[:], receipt.Commitment)
+			}
+		}
+		return celestiaKey(receipt.Height, receipt.Namespace), check, nil
+	}
+
+	s, ok := args.(string)
+	if !ok {
+		return "", nil, _errors.WrongArgTypeErr
+	}
+	switch d.daType {
+	case _common.AnytrustType, _common.AnytrustCommitteeType, _common.Eip4844Type:
+		return strings.TrimPrefix(strings.ToLower(s), "0x"), nil, nil
+	case _common.EigenDAType:
+		reqID, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", nil, err
+		}
+		return string(reqID), nil, nil
+	case _common.NearDAType:
+		frameRef, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(frameRef) != 64 {
+			return "", nil, fmt.Errorf("nearda frame ref must be 64 bytes, got %d", len(frameRef))
+		}
+		return hex.EncodeToString(frameRef), nil, nil
+	}
+	return "", nil, _errors.UnknownDATypeErr
+}
+
+func (d *DA) storeKey(key string) string {
+	return _common.DATypeName(d.daType) + "/" + key
+}
+
+func celestiaKey(height uint64, namespace share.Namespace) string {
+	return fmt.Sprintf("%d/%s", height, namespace)
+}
+
+func (d *DA) resolveNamespace(ctx context.Context) (share.Namespace, error) {
+	name := _common.NamespaceFromContext(ctx)
+	if len(name) == 0 {
+		return d.namespace, nil
+	}
+	// the mock has no tenants, only raw namespaces
+	return celestia.ParseNamespace(name)
+}
+
+// next returns the next sequence number of the DA, kept in the store so it survives restarts.
+func (d *DA) next() (uint64, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	key := d.storeKey("seq")
+	var seq uint64
+	raw, _, err := d.store.Get(key)
+	switch {
+	case err == nil && len(raw) == 8:
+		seq = binary.BigEndian.Uint64(raw)
+	case err != nil && !errors.Is(err, ErrNotFound):
+		return 0, err
+	}
+	seq++
+	// the sequence never expires
+	return seq, d.store.Put(key, binary.BigEndian.AppendUint64(nil, seq), time.Unix(0, 1<<63-1))
+}
+
+// simulate delays a request as configured and decides whether it fails, lose is set for the
+// submissions whose data must be dropped.
+func (d *DA) simulate(ctx context.Context) (lose bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if d.conf.FailureMode == FailDown {
+		return false, ErrUnavailable
+	}
+	delay := d.conf.Latency
+	if d.conf.Jitter > 0 {
+		delay += rand.N(d.conf.Jitter)
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	if d.conf.FailureRate == 0 || rand.Float64() >= d.conf.FailureRate {
+		return false, nil
+	}
+	switch d.conf.FailureMode {
+	case FailTimeout:
+		<-ctx.Done()
+		return false, ctx.Err()
+	case FailLose:
+		return true, nil
+	}
+	return false, ErrInjected
+}
+
+// anytrustCert serializes a certificate of the data hash as das.Serialize does, signed by no
+// committee: the header flags, the keyset hash, the data hash, the timeout, the version, the
+// signers mask and the signature.
+func anytrustCert(dataHash []byte, expiry time.Time) []byte {
+	keysetHash := sha256.Sum256([]byte("mockda keyset"))
+	cert := []byte{0x88}
+	cert = append(cert, keysetHash[:]...)
+	cert = append(cert, dataHash...)
+	cert = binary.BigEndian.AppendUint64(cert, uint64(expiry.Unix()))
+	cert = append(cert, 1)
+	cert = binary.BigEndian.AppendUint64(cert, 1)
+	return append(cert, make([]byte, 96)...)
+}
@@ -0,0 +1,169 @@
+package mockda
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	_common "github.com/eniac-x-labs/rollup-node/common"
+	_errors "github.com/eniac-x-labs/rollup-node/common/errors"
+	"github.com/eniac-x-labs/rollup-node/common/receipt"
+)
+
+func newDA(t *testing.T, conf Config, daType int) *DA {
+	d, err := NewDevnet(conf)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = d.Close() })
+	da, ok := d.DA(daType)
+	require.True(t, ok)
+	return da
+}
+
+func TestReceipts(t *testing.T) {
+	ctx := context.Background()
+	data := []byte("rollup batch")
+	for _, daType := range _common.DATypes {
+		t.Run(_common.DATypeName(daType), func(t *testing.T) {
+			da := newDA(t, DefaultConfig(), daType)
+			res, err := da.Store(ctx, data)
+			require.NoError(t, err)
+
+			// the receipts decode as the ones of the mocked DA
+			args := res[0]
+			if daType == _common.CelestiaType {
+				args = res[1]
+			}
+			for _, r := range res {
+				s, ok := r.(string)
+				if !ok {
+					continue
+				}
+				_, err := receipt.Inspect(daType, s)
+				require.NoError(t, err, s)
+			}
+
+			got, err := da.Retrieve(ctx, args)
+			require.NoError(t, err)
+			require.Equal(t, data, got)
+
+			status, err := da.Status(ctx, args)
+			switch daType {
+			case _common.EigenDAType, _common.Eip4844Type:
+				require.NoError(t, err)
+				require.True(t, status.Final)
+			default:
+				require.ErrorIs(t, err, _errors.StatusNotTrackedErr)
+			}
+		})
+	}
+}
+
+func TestCelestiaHeights(t *testing.T) {
+	ctx := context.Background()
+	da := newDA(t, DefaultConfig(), _common.CelestiaType)
+	first, err := da.Store(ctx, []byte("first"))
+	require.NoError(t, err)
+	second, err := da.Store(ctx, []byte("second"))
+	require.NoError(t, err)
+	require.Equal(t, first[0].(uint64)+1, second[0])
+
+	data, err := da.Retrieve(ctx, second[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte("second"), data)
+
+	_, err = da.Retrieve(ctx, uint64(42))
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = da.Retrieve(ctx, 42)
+	require.ErrorIs(t, err, _errors.WrongArgTypeErr)
+}
+
+func TestLocalFSStore(t *testing.T) {
+	ctx := context.Background()
+	conf := DefaultConfig()
+	conf.Store = StoreLocalFS
+	conf.Dir = t.TempDir()
+	res, err := newDA(t, conf, _common.NearDAType).Store(ctx, []byte("near batch"))
+	require.NoError(t, err)
+
+	// a restarted devnet still serves the data and goes on with the sequence
+	da := newDA(t, conf, _common.NearDAType)
+	data, err := da.Retrieve(ctx, res[0])
+	require.NoError(t, err)
+	require.Equal(t, []byte("near batch"), data)
+	next, err := da.Store(ctx, []byte("near batch"))
+	require.NoError(t, err)
+	require.NotEqual(t, res[0], next[0])
+}
+
+func TestRetention(t *testing.T) {
+	ctx := context.Background()
+	conf := DefaultConfig()
+	conf.Retention = time.Hour
+	da := newDA(t, conf, _common.Eip4844Type)
+	now := time.Now()
+	da.now = func() time.Time { return now }
+	res, err := da.Store(ctx, []byte("blob"))
+	require.NoError(t, err)
+
+	now = now.Add(time.Hour)
+	_, err = da.Retrieve(ctx, res[0])
+	require.ErrorIs(t, err, ErrExpired)
+}
+
+func TestFailureModes(t *testing.T) {
+	ctx := context.Background()
+	conf := DefaultConfig()
+	conf.FailureRate = 1
+
+	_, err := newDA(t, conf, _common.EigenDAType).Store(ctx, []byte("blob"))
+	require.ErrorIs(t, err, ErrInjected)
+
+	conf.FailureMode = FailTimeout
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, err = newDA(t, conf, _common.EigenDAType).Store(timeoutCtx, []byte("blob"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	conf.FailureMode = FailLose
+	da := newDA(t, conf, _common.AnytrustType)
+	res, err := da.Store(ctx, []byte("blob"))
+	require.NoError(t, err)
+	_, err = da.Retrieve(ctx, res[0])
+	require.ErrorIs(t, err, ErrNotFound)
+
+	conf.FailureMode = FailDown
+	conf.FailureRate = 0
+	da = newDA(t, conf, _common.AnytrustType)
+	require.ErrorIs(t, da.HealthCheck(ctx), ErrUnavailable)
+	_, err = da.Retrieve(ctx, res[0])
+	require.ErrorIs(t, err, ErrUnavailable)
+
+	conf = DefaultConfig()
+	conf.Latency = 20 * time.Millisecond
+	start := time.Now()
+	_, err = newDA(t, conf, _common.AnytrustType).Store(ctx, []byte("blob"))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(start), conf.Latency)
+}
+
+func TestCheck(t *testing.T) {
+	require.NoError(t, DefaultConfig().Check())
+	for _, conf := range []Config{
+		{Store: "s3", FailureMode: FailError},
+		{Store: StoreLocalFS, FailureMode: FailError},
+		{Store: StoreMemory, FailureMode: FailError, DAs: []string{"erasure"}},
+		{Store: StoreMemory, FailureMode: FailError, DAs: []string{"avail"}},
+		{Store: StoreMemory, FailureMode: FailError, FailureRate: 2},
+		{Store: StoreMemory, FailureMode: "flaky"},
+		{Store: StoreMemory, FailureMode: FailError, Latency: -time.Second},
+	} {
+		require.Error(t, conf.Check(), "%+v", conf)
+	}
+
+	conf := Config{DAs: []string{"celestia", "2"}}
+	require.True(t, conf.Serves(_common.EigenDAType))
+	require.False(t, conf.Serves(_common.AnytrustType))
+	require.True(t, DefaultConfig().Serves(_common.NearDAType))
+}
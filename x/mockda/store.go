@@ -0,0 +1,145 @@
+package mockda
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNotFound = errors.New("mock da: no data for the receipt")
+	ErrExpired  = errors.New("mock da: data expired, it is past the retention of the DA")
+	ErrClosed   = errors.New("mock da: store closed")
+)
+
+// Store keeps the data of the mock backends by key, along with the time it expires. A closed store
+// fails every call with ErrClosed.
+type Store interface {
+	Put(key string, data []byte, expiry time.Time) error
+	// Get returns ErrNotFound for a missing key
+	Get(key string) ([]byte, time.Time, error)
+	Close() error
+}
+
+// NewStore returns the store configured by conf.
+func NewStore(conf Config) (Store, error) {
+	if conf.Store == StoreLocalFS {
+		return NewFileStore(conf.Dir)
+	}
+	return NewMemoryStore(), nil
+}
+
+type memoryEntry struct {
+	data   []byte
+	expiry time.Time
+}
+
+// MemoryStore keeps the data in memory, it is lost when the node stops.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	closed  bool
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry)}
+}
+
+func (s *MemoryStore) Put(key string, data []byte, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	s.entries[key] = memoryEntry{data: append([]byte(nil), data...), expiry: expiry}
+	return nil
+}
+
+func (s *MemoryStore) Get(key string) ([]byte, time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return nil, time.Time{}, ErrClosed
+	}
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, time.Time{}, ErrNotFound
+	}
+	return append([]byte(nil), entry.data...), entry.expiry, nil
+}
+
+func (s *MemoryStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed, s.entries = true, nil
+	return nil
+}
+
+// FileStore keeps every entry in a file of its dir, the expiry in unix nanoseconds followed by
+// the data, so a devnet keeps its data across restarts.
+type FileStore struct {
+	dir    string
+	closed atomic.Bool
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create mock da dir: %w", err)
+	}
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) path(key string) string {
+	name := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(name[:]))
+}
+
+func (s *FileStore) Put(key string, data []byte, expiry time.Time) error {
+	if s.closed.Load() {
+		return ErrClosed
+	}
+	raw := binary.BigEndian.AppendUint64(make([]byte, 0, 8+len(data)), uint64(expiry.UnixNano()))
+	raw = append(raw, data...)
+	// written aside then renamed, a crash never leaves a partial entry behind
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(raw)
+	if err = errors.Join(err, tmp.Close()); err == nil {
+		err = os.Rename(tmp.Name(), s.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+	return err
+}
+
+func (s *FileStore) Get(key string) ([]byte, time.Time, error) {
+	if s.closed.Load() {
+		return nil, time.Time{}, ErrClosed
+	}
+	raw, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if len(raw) < 8 {
+		return nil, time.Time{}, fmt.Errorf("mock da entry %s is truncated", s.path(key))
+	}
+	return raw[8:], time.Unix(0, int64(binary.BigEndian.Uint64(raw))), nil
+}
+
+func (s *FileStore) Close() error {
+	s.closed.Store(true)
+	return nil
+}
@@ -1,17 +1,36 @@
 package nearda
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+
 	"github.com/ethereum/go-ethereum/log"
 	near "github.com/near/rollup-data-availability/gopkg/da-rpc"
 )
 
+// rpcUrls are the public near rpc endpoints used to probe the DA account.
+var rpcUrls = map[string]string{
+	"Mainnet":  "https://rpc.mainnet.near.org",
+	"Testnet":  "https://rpc.testnet.near.org",
+	"Localnet": "http://127.0.0.1:3030",
+}
+
 type NearDAClient struct {
 	*near.Config
+	account string
+	rpcUrl  string
 }
 
 type INearDA interface {
 	Store(data []byte) ([]byte, error)
 	GetFromDA(frameRefBytes []byte, txIndex uint32) ([]byte, error)
+	HealthCheck(ctx context.Context) error
+	Close() error
 }
 
 func NewNearDAClient(nearconf *NearDAConfig) (INearDA, error) {
@@ -20,7 +39,11 @@ func NewNearDAClient(nearconf *NearDAConfig) (INearDA, error) {
 		log.Error("NewConfig failed:", err)
 		return nil, err
 	}
-	return &NearDAClient{conf}, nil
+	return &NearDAClient{
+		Config:  conf,
+		account: nearconf.Account,
+		rpcUrl:  rpcUrls[nearconf.Network],
+	}, nil
 }
 
 //func (n *NearDAClient) SubmitData(candidateHex string, data []byte) ([]byte, error) {
@@ -34,3 +57,66 @@ func (n *NearDAClient) Store(data []byte) ([]byte, error) {
 func (n *NearDAClient) GetFromDA(frameRefBytes []byte, txIndex uint32) ([]byte, error) {
 	return n.Get(frameRefBytes, txIndex)
 }
+
+type viewAccountResponse struct {
+	Result *struct {
+		Amount string `json:"amount"`
+	} `json:"result"`
+	Error *struct {
+		Name  string `json:"name"`
+		Cause struct {
+			Name string `json:"name"`
+		} `json:"cause"`
+	} `json:"error"`
+}
+
+// HealthCheck queries the DA account on the near rpc, which fails when the rpc is unreachable
+// or the account does not exist, and checks that the account can still pay for transactions.
+func (n *NearDAClient) HealthCheck(ctx context.Context) error {
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      "rollup-node",
+		"method":  "query",
+		"params": map[string]string{
+			"request_type": "view_account",
+			"finality":     "final",
+			"account_id":   n.account,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.rpcUrl, bytes.NewReader(reqBody))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("near rpc unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var view viewAccountResponse
+	if err := json.NewDecoder(resp.Body).Decode(&view); err != nil {
+		return fmt.Errorf("failed to decode near rpc response: %w", err)
+	}
+	if view.Error != nil {
+		return fmt.Errorf("near rpc view_account failed: %s %s", view.Error.Name, view.Error.Cause.Name)
+	}
+	if view.Result == nil {
+		return errors.New("near rpc view_account returned empty result")
+	}
+
+	amount, ok := new(big.Int).SetString(view.Result.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return fmt.Errorf("near account %s balance is zero", n.account)
+	}
+	return nil
+}
+
+// Close is a no-op, da-rpc does not expose a way to free the native client it holds.
+func (n *NearDAClient) Close() error {
+	return nil
+}